@@ -0,0 +1,251 @@
+package bluesky
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+const (
+	// defaultAppViewURL is the public, unauthenticated Bluesky AppView
+	defaultAppViewURL = "https://public.api.bsky.app"
+	// defaultPDSURL answers com.atproto.identity.resolveHandle for any handle
+	defaultPDSURL = "https://bsky.social"
+	// maxFeedPages bounds how many 100 item pages of likes or reposts are scanned per check
+	maxFeedPages = 20
+)
+
+// AppViewClient calls the XRPC endpoints of a Bluesky AppView and PDS
+type AppViewClient struct {
+	appViewURL string
+	pdsURL     string
+	httpClient *http.Client
+}
+
+// NewAppViewClient creates an AppView client.
+// Config via ENV:
+//   - BLUESKY_APPVIEW_URL (optional): AppView base URL (defaults to https://public.api.bsky.app)
+//   - BLUESKY_PDS_URL (optional): PDS used to resolve handles (defaults to https://bsky.social)
+func NewAppViewClient() *AppViewClient {
+	appViewURL := strings.TrimRight(os.Getenv("BLUESKY_APPVIEW_URL"), "/")
+	if appViewURL == "" {
+		appViewURL = defaultAppViewURL
+	}
+	pdsURL := strings.TrimRight(os.Getenv("BLUESKY_PDS_URL"), "/")
+	if pdsURL == "" {
+		pdsURL = defaultPDSURL
+	}
+	return &AppViewClient{
+		appViewURL: appViewURL,
+		pdsURL:     pdsURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name implements Provider
+func (c *AppViewClient) Name() string {
+	return ProviderAppView
+}
+
+// xrpcError is the XRPC error body
+type xrpcError struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// query performs an XRPC query against baseURL and decodes the response into out
+func (c *AppViewClient) query(ctx context.Context, baseURL string, method string, params url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/xrpc/"+method+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := resp.Header.Get("Retry-After"); ratelimit.FromSeconds(retryAfter) > 0 {
+			return ratelimit.New(ErrRateLimited, ratelimit.FromSeconds(retryAfter), "retry after "+retryAfter+"s")
+		}
+		reset := resp.Header.Get("RateLimit-Reset")
+		sec, err := strconv.ParseInt(reset, 10, 64)
+		if err != nil {
+			return ratelimit.New(ErrRateLimited, 0, "")
+		}
+		return ratelimit.New(ErrRateLimited, ratelimit.FromUnix(reset), "resets at "+time.Unix(sec, 0).UTC().Format(time.RFC3339))
+	}
+	if resp.StatusCode != http.StatusOK {
+		var xerr xrpcError
+		_ = json.Unmarshal(body, &xerr)
+		switch {
+		case xerr.Error == "NotFound", strings.Contains(xerr.Message, "Unable to resolve handle"),
+			strings.Contains(xerr.Message, "Profile not found"), xerr.Error == "AccountTakedown":
+			return fmt.Errorf("%w: %s", ErrUserNotFound, xerr.Message)
+		}
+		return fmt.Errorf("%s failed with status %d: %s %s", method, resp.StatusCode, xerr.Error, xerr.Message)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// ResolveHandle implements Provider
+func (c *AppViewClient) ResolveHandle(ctx context.Context, handle string) (string, error) {
+	params := url.Values{}
+	params.Set("handle", handle)
+	var out struct {
+		DID string `json:"did"`
+	}
+	if err := c.query(ctx, c.pdsURL, "com.atproto.identity.resolveHandle", params, &out); err != nil {
+		return "", err
+	}
+	if out.DID == "" {
+		return "", fmt.Errorf("%w: %s", ErrUserNotFound, handle)
+	}
+	return out.DID, nil
+}
+
+// FetchRelationship implements Provider
+func (c *AppViewClient) FetchRelationship(ctx context.Context, actorDID string, targetDID string) (Relationship, error) {
+	params := url.Values{}
+	params.Set("actor", actorDID)
+	params.Add("others", targetDID)
+	var out struct {
+		Relationships []struct {
+			Type       string `json:"$type"`
+			DID        string `json:"did"`
+			Following  string `json:"following"`
+			FollowedBy string `json:"followedBy"`
+			Blocking   string `json:"blocking"`
+			BlockedBy  string `json:"blockedBy"`
+			NotFound   bool   `json:"notFound"`
+		} `json:"relationships"`
+	}
+	if err := c.query(ctx, c.appViewURL, "app.bsky.graph.getRelationships", params, &out); err != nil {
+		return Relationship{}, err
+	}
+	for _, rel := range out.Relationships {
+		if rel.DID != targetDID {
+			continue
+		}
+		if rel.NotFound || strings.HasSuffix(rel.Type, "#notFoundActor") {
+			return Relationship{}, fmt.Errorf("%w: %s", ErrTargetNotFound, targetDID)
+		}
+		// Each field holds the AT-URI of the record (follow or block) when the relation exists
+		return Relationship{
+			Following:  rel.Following != "",
+			FollowedBy: rel.FollowedBy != "",
+			Blocked:    rel.Blocking != "",
+			BlockedBy:  rel.BlockedBy != "",
+		}, nil
+	}
+	return Relationship{}, fmt.Errorf("%w: %s", ErrTargetNotFound, targetDID)
+}
+
+// postExists checks the post through app.bsky.feed.getPosts; getLikes and getRepostedBy answer an empty
+// list for deleted posts, which would read as "no"
+func (c *AppViewClient) postExists(ctx context.Context, postURI string) error {
+	params := url.Values{}
+	params.Set("uris", postURI)
+	var out struct {
+		Posts []json.RawMessage `json:"posts"`
+	}
+	if err := c.query(ctx, c.appViewURL, "app.bsky.feed.getPosts", params, &out); err != nil {
+		return err
+	}
+	if len(out.Posts) == 0 {
+		return fmt.Errorf("%w: %s", ErrTargetNotFound, postURI)
+	}
+	return nil
+}
+
+// HasLiked implements Provider by paging app.bsky.feed.getLikes
+func (c *AppViewClient) HasLiked(ctx context.Context, actorDID string, postURI string) (bool, error) {
+	return c.scanFeed(ctx, "app.bsky.feed.getLikes", postURI, actorDID, func(body []byte) ([]string, string, error) {
+		var out struct {
+			Cursor string `json:"cursor"`
+			Likes  []struct {
+				Actor struct {
+					DID string `json:"did"`
+				} `json:"actor"`
+			} `json:"likes"`
+		}
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, "", err
+		}
+		dids := make([]string, len(out.Likes))
+		for i, like := range out.Likes {
+			dids[i] = like.Actor.DID
+		}
+		return dids, out.Cursor, nil
+	})
+}
+
+// HasReposted implements Provider by paging app.bsky.feed.getRepostedBy
+func (c *AppViewClient) HasReposted(ctx context.Context, actorDID string, postURI string) (bool, error) {
+	return c.scanFeed(ctx, "app.bsky.feed.getRepostedBy", postURI, actorDID, func(body []byte) ([]string, string, error) {
+		var out struct {
+			Cursor     string `json:"cursor"`
+			RepostedBy []struct {
+				DID string `json:"did"`
+			} `json:"repostedBy"`
+		}
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, "", err
+		}
+		dids := make([]string, len(out.RepostedBy))
+		for i, actor := range out.RepostedBy {
+			dids[i] = actor.DID
+		}
+		return dids, out.Cursor, nil
+	})
+}
+
+// scanFeed pages a feed method for postURI until actorDID shows up; decode returns the DIDs and next cursor.
+// It returns ErrScanLimit when the feed has more than maxFeedPages pages and actorDID was not on them.
+func (c *AppViewClient) scanFeed(ctx context.Context, method string, postURI string, actorDID string, decode func([]byte) ([]string, string, error)) (bool, error) {
+	if err := c.postExists(ctx, postURI); err != nil {
+		return false, err
+	}
+	params := url.Values{}
+	params.Set("uri", postURI)
+	params.Set("limit", "100")
+	for page := 0; page < maxFeedPages; page++ {
+		var raw json.RawMessage
+		if err := c.query(ctx, c.appViewURL, method, params, &raw); err != nil {
+			return false, err
+		}
+		dids, cursor, err := decode(raw)
+		if err != nil {
+			return false, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		for _, did := range dids {
+			if did == actorDID {
+				return true, nil
+			}
+		}
+		if cursor == "" || len(dids) == 0 {
+			return false, nil
+		}
+		params.Set("cursor", cursor)
+	}
+	return false, fmt.Errorf("%w: %s of %s has more than %d pages", ErrScanLimit, method, postURI, maxFeedPages)
+}
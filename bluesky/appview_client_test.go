@@ -0,0 +1,101 @@
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+const (
+	testAuthor = "did:plc:aaaaaaaaaaaaaaaaaaaaaaaa"
+	testLiker  = "did:plc:bbbbbbbbbbbbbbbbbbbbbbbb"
+	testOther  = "did:plc:cccccccccccccccccccccccc"
+)
+
+// fakeAppView serves getPosts, an endless getLikes feed without testLiker and a getRepostedBy feed with
+// testLiker on its second and last page
+func fakeAppView(t *testing.T) {
+	t.Helper()
+	cursor := func(r *http.Request) int {
+		n, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		return n
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /xrpc/app.bsky.feed.getPosts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"posts":[{"uri":%q}]}`, r.URL.Query().Get("uris"))
+	})
+	mux.HandleFunc("GET /xrpc/app.bsky.feed.getLikes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"cursor":"%d","likes":[{"actor":{"did":%q}}]}`, cursor(r)+1, testOther)
+	})
+	mux.HandleFunc("GET /xrpc/app.bsky.feed.getRepostedBy", func(w http.ResponseWriter, r *http.Request) {
+		if cursor(r) == 0 {
+			fmt.Fprintf(w, `{"cursor":"1","repostedBy":[{"did":%q}]}`, testOther)
+			return
+		}
+		fmt.Fprintf(w, `{"repostedBy":[{"did":%q}]}`, testLiker)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Setenv("BLUESKY_APPVIEW_URL", srv.URL)
+	t.Setenv("BLUESKY_PROVIDER", "")
+}
+
+func TestCheckEngagement(t *testing.T) {
+	fakeAppView(t)
+	post := "at://" + testAuthor + "/app.bsky.feed.post/3kabc"
+
+	res, err := CheckEngagement(testLiker, post, ActionRepost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Done || !res.Verifiable {
+		t.Errorf("repost on the second page: got done=%v verifiable=%v", res.Done, res.Verifiable)
+	}
+
+	res, err = CheckEngagement(testLiker, post, ActionLike)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Done || res.Verifiable || res.Reason == "" {
+		t.Errorf("likes past the scan cap: got done=%v verifiable=%v reason=%q, want an unverifiable result", res.Done, res.Verifiable, res.Reason)
+	}
+}
+
+func TestCheckEngagementRateLimited(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(90*time.Second).Unix(), 10)
+	tests := []struct {
+		name     string
+		header   map[string]string
+		min, max time.Duration
+	}{
+		{"ratelimit reset", map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": reset}, 80 * time.Second, 90 * time.Second},
+		{"retry after", map[string]string{"Retry-After": "30"}, 30 * time.Second, 30 * time.Second},
+		{"no hint", nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"error":"RateLimitExceeded","message":"Rate Limit Exceeded"}`)
+			}))
+			defer srv.Close()
+			t.Setenv("BLUESKY_APPVIEW_URL", srv.URL)
+			t.Setenv("BLUESKY_PROVIDER", "")
+
+			_, err := CheckEngagement(testLiker, "at://"+testAuthor+"/app.bsky.feed.post/3kabc", ActionLike)
+			var rl *ratelimit.Error
+			if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) || rl.RetryAfter < tt.min || rl.RetryAfter > tt.max {
+				t.Fatalf("got %v, want ErrRateLimited retrying after %s-%s", err, tt.min, tt.max)
+			}
+		})
+	}
+}
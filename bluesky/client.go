@@ -0,0 +1,126 @@
+package bluesky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Actions supported by CheckFollow and CheckEngagement
+const (
+	ActionFollow = "follow"
+	ActionLike   = "like"
+	ActionRepost = "repost"
+)
+
+// Relationship is the actor's relationship to a target account
+type Relationship struct {
+	// Following: the actor follows the target
+	Following bool
+	// FollowedBy: the target follows the actor
+	FollowedBy bool
+	// Blocked: the actor has blocked the target
+	Blocked bool
+	// BlockedBy: the target has blocked the actor
+	BlockedBy bool
+}
+
+// FollowResult is the outcome of CheckFollow together with the resolved DIDs
+type FollowResult struct {
+	UserDID   string
+	TargetDID string
+	// Source is the Provider name
+	Source string
+	Relationship
+}
+
+// CheckFollow checks if the user follows target; empty target uses BLUESKY_TARGET
+func CheckFollow(userID string, target string) (*FollowResult, error) {
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if strings.TrimSpace(target) == "" {
+		target = os.Getenv("BLUESKY_TARGET")
+	}
+	targetDID, err := resolveTargetDID(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	userDID, err := ResolveDID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	rel, err := provider.FetchRelationship(ctx, userDID, targetDID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &FollowResult{UserDID: userDID, TargetDID: targetDID, Source: provider.Name(), Relationship: rel}
+	log.Printf("[Bluesky][DEBUG] CheckFollow user=%s target=%s following=%v followed_by=%v", userDID, targetDID, res.Following, res.FollowedBy)
+	return res, nil
+}
+
+// EngagementResult is the outcome of CheckEngagement
+type EngagementResult struct {
+	UserDID string
+	PostURI string
+	Done    bool
+	Source  string
+	// Verifiable is false when the likes or reposts were too many to scan; Done is then false
+	Verifiable bool
+	// Reason explains why the check was not verifiable
+	Reason string
+}
+
+// CheckEngagement checks if the user liked or reposted post (an at:// URI or bsky.app post URL)
+func CheckEngagement(userID string, post string, action string) (*EngagementResult, error) {
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	postURI, err := ResolvePostURI(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	userDID, err := ResolveDID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	res := &EngagementResult{UserDID: userDID, PostURI: postURI, Source: provider.Name(), Verifiable: true}
+
+	switch action {
+	case ActionLike:
+		res.Done, err = provider.HasLiked(ctx, userDID, postURI)
+	case ActionRepost:
+		res.Done, err = provider.HasReposted(ctx, userDID, postURI)
+	default:
+		return nil, fmt.Errorf("unsupported bluesky engagement action %q", action)
+	}
+	if errors.Is(err, ErrScanLimit) {
+		res.Verifiable, res.Reason = false, err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Bluesky][DEBUG] CheckEngagement user=%s post=%s action=%s done=%v verifiable=%v", userDID, postURI, action, res.Done, res.Verifiable)
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package bluesky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Provider names selectable with BLUESKY_PROVIDER
+const (
+	ProviderAppView = "appview"
+)
+
+var (
+	// ErrInvalidIdentifier is returned when an identifier is neither a handle, a DID nor a profile URL
+	ErrInvalidIdentifier = errors.New("invalid bluesky identifier")
+	// ErrUserNotFound is returned when a handle does not resolve or the DID is unknown to the AppView
+	ErrUserNotFound = errors.New("bluesky user not found")
+	// ErrInvalidTarget is returned when a target is not a valid account identifier, at:// post URI or bsky.app post URL
+	ErrInvalidTarget = errors.New("invalid bluesky target")
+	// ErrTargetNotFound is returned when the target account or post does not exist or was deleted
+	ErrTargetNotFound = errors.New("bluesky target not found")
+	// ErrRateLimited is returned when the AppView answered 429
+	ErrRateLimited = errors.New("bluesky rate limited")
+	// ErrScanLimit is returned by HasLiked and HasReposted when the list is too long to scan to the end
+	ErrScanLimit = errors.New("bluesky list too long to scan")
+)
+
+// Provider is a Bluesky data backend used by the follow and engagement checks
+type Provider interface {
+	// Name identifies the provider in results (see FollowResult.Source)
+	Name() string
+	// ResolveHandle resolves a handle to its DID (com.atproto.identity.resolveHandle)
+	ResolveHandle(ctx context.Context, handle string) (string, error)
+	// FetchRelationship returns the actor's relationship to the target (app.bsky.graph.getRelationships)
+	FetchRelationship(ctx context.Context, actorDID string, targetDID string) (Relationship, error)
+	// HasLiked checks if the actor liked the post
+	HasLiked(ctx context.Context, actorDID string, postURI string) (bool, error)
+	// HasReposted checks if the actor reposted the post
+	HasReposted(ctx context.Context, actorDID string, postURI string) (bool, error)
+}
+
+// NewProvider creates the provider selected by BLUESKY_PROVIDER ("appview" by default)
+func NewProvider() (Provider, error) {
+	switch name := strings.ToLower(strings.TrimSpace(os.Getenv("BLUESKY_PROVIDER"))); name {
+	case "", ProviderAppView:
+		return NewAppViewClient(), nil
+	default:
+		return nil, fmt.Errorf("unknown BLUESKY_PROVIDER %q", name)
+	}
+}
@@ -0,0 +1,157 @@
+package bluesky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultResolveCacheTTL = 24 * time.Hour
+
+var (
+	didPattern    = regexp.MustCompile(`^did:(plc:[a-z2-7]{24}|web:[a-zA-Z0-9.:%-]+)$`)
+	handlePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	rkeyPattern   = regexp.MustCompile(`^[a-zA-Z0-9._:~-]{1,512}$`)
+
+	// profileHosts are the web clients whose profile URLs look like https://host/profile/<handle-or-did>
+	profileHosts = map[string]bool{
+		"bsky.app":     true,
+		"www.bsky.app": true,
+	}
+)
+
+// resolveCacheEntry is a cached handle -> DID mapping
+type resolveCacheEntry struct {
+	did       string
+	expiresAt time.Time
+}
+
+// resolveCache keeps resolved handles in memory so repeated checks don't hit the PDS
+var resolveCache = struct {
+	sync.RWMutex
+	entries map[string]resolveCacheEntry
+}{entries: map[string]resolveCacheEntry{}}
+
+// ResolveDID turns a user supplied identifier into a DID.
+// Accepted inputs:
+//   - a DID ("did:plc:..." or "did:web:...")
+//   - a handle, with or without the leading @ ("@alice.bsky.social")
+//   - a bsky.app profile URL ("https://bsky.app/profile/alice.bsky.social")
+//
+// Handles are resolved through com.atproto.identity.resolveHandle and cached (BLUESKY_RESOLVE_CACHE_TTL, default 24h).
+func ResolveDID(ctx context.Context, identifier string) (string, error) {
+	s := strings.TrimSpace(identifier)
+	if strings.Contains(s, "/") {
+		segments, ok := profilePath(s)
+		if !ok || len(segments) < 2 || segments[0] != "profile" {
+			return "", fmt.Errorf("%w: unsupported profile URL %q", ErrInvalidIdentifier, identifier)
+		}
+		s = segments[1]
+	}
+	s = strings.TrimPrefix(s, "@")
+	if didPattern.MatchString(s) {
+		return s, nil
+	}
+
+	handle := strings.ToLower(s)
+	if !handlePattern.MatchString(handle) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidIdentifier, identifier)
+	}
+	if did, ok := cachedDID(handle); ok {
+		return did, nil
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return "", err
+	}
+	did, err := provider.ResolveHandle(ctx, handle)
+	if err != nil {
+		return "", fmt.Errorf("resolve handle %q: %w", handle, err)
+	}
+
+	log.Printf("[Bluesky][DEBUG] ResolveDID handle=%s did=%s", handle, did)
+	storeDID(handle, did)
+	return did, nil
+}
+
+// ResolvePostURI turns an at:// post URI or a bsky.app post URL into an at:// URI whose authority is a DID
+func ResolvePostURI(ctx context.Context, post string) (string, error) {
+	s := strings.TrimSpace(post)
+	var authority, rkey string
+	if rest, ok := strings.CutPrefix(s, "at://"); ok {
+		parts := strings.Split(rest, "/")
+		if len(parts) != 3 || parts[1] != "app.bsky.feed.post" {
+			return "", fmt.Errorf("%w: %q", ErrInvalidTarget, post)
+		}
+		authority, rkey = parts[0], parts[2]
+	} else {
+		segments, ok := profilePath(s)
+		if !ok || len(segments) != 4 || segments[0] != "profile" || segments[2] != "post" {
+			return "", fmt.Errorf("%w: %q", ErrInvalidTarget, post)
+		}
+		authority, rkey = segments[1], segments[3]
+	}
+	if !rkeyPattern.MatchString(rkey) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, post)
+	}
+
+	did, err := resolveTargetDID(ctx, authority)
+	if err != nil {
+		return "", err
+	}
+	return "at://" + did + "/app.bsky.feed.post/" + rkey, nil
+}
+
+// resolveTargetDID resolves an account used as a target, reporting failures as target errors
+func resolveTargetDID(ctx context.Context, identifier string) (string, error) {
+	did, err := ResolveDID(ctx, identifier)
+	switch {
+	case errors.Is(err, ErrInvalidIdentifier):
+		return "", fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, ErrUserNotFound):
+		return "", fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	}
+	return did, err
+}
+
+// profilePath returns the path segments of s when it is a bsky.app URL
+func profilePath(raw string) ([]string, bool) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !profileHosts[strings.ToLower(u.Host)] {
+		return nil, false
+	}
+	return strings.Split(strings.Trim(u.Path, "/"), "/"), true
+}
+
+func cachedDID(handle string) (string, bool) {
+	resolveCache.RLock()
+	defer resolveCache.RUnlock()
+	entry, ok := resolveCache.entries[handle]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", false
+	}
+	return entry.did, true
+}
+
+func storeDID(handle string, did string) {
+	ttl := defaultResolveCacheTTL
+	if v := os.Getenv("BLUESKY_RESOLVE_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			ttl = d
+		}
+	}
+	resolveCache.Lock()
+	defer resolveCache.Unlock()
+	resolveCache.entries[handle] = resolveCacheEntry{did: did, expiresAt: time.Now().Add(ttl)}
+}
@@ -1,24 +1,35 @@
 package main
 
 import (
-	"checkingsocial/internal/handler"
-	"checkingsocial/internal/service"
+	"checkingsocial/internal/app"
+	"checkingsocial/pkg/cache"
+	"log"
 
 	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
 )
 
 func main() {
-	// Create a new Gin router
-	router := gin.Default()
+	// Load environment variables from .env file
+	_ = godotenv.Load()
 
-	// Create the service
-	socialService := service.NewSocialChecker()
+	// Connect to Redis; webhook-fed follow state lives there
+	if err := cache.InitRedis(); err != nil {
+		log.Printf("Redis unavailable, falling back to in-memory stores: %v", err)
+	} else {
+		defer cache.Close()
+	}
 
-	// Create the handler
-	socialHandler := handler.NewSocialHandler(socialService)
+	// Create a new Gin router
+	router := gin.Default()
 
-	// Register routes
-	socialHandler.RegisterRoutes(router)
+	// Build the services with the same decorator chain as the main server, so action routes
+	// here enforce ownership proofs, identity links and the ledger too
+	cleanup, err := app.Setup(router)
+	if err != nil {
+		log.Fatalf("Failed to set up services: %v", err)
+	}
+	defer cleanup()
 
 	// Start the server
 	if err := router.Run(":8080"); err != nil {
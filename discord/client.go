@@ -0,0 +1,267 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultAPIBaseURL is the Discord REST API base URL
+const defaultAPIBaseURL = "https://discord.com/api/v10"
+
+// maxRateLimitRetries bounds how many times a 429 is retried
+const maxRateLimitRetries = 2
+
+// Actions supported by CheckMember
+const (
+	ActionJoinGuild = "join_guild"
+	ActionHasRole   = "has_role"
+)
+
+// Membership statuses reported in MemberResult.Status
+const (
+	StatusMember    = "member"
+	StatusPending   = "pending"
+	StatusNotMember = "not_member"
+)
+
+// Discord JSON error codes
+const (
+	codeUnknownGuild  = 10004
+	codeUnknownMember = 10007
+	codeUnknownUser   = 10013
+)
+
+var (
+	// ErrInvalidUserID is returned when the user is not a Discord snowflake
+	ErrInvalidUserID = errors.New("invalid discord user id")
+	// ErrInvalidTarget is returned when the guild or role is not a snowflake
+	ErrInvalidTarget = errors.New("invalid discord guild or role")
+	// ErrGuildNotFound is returned when the guild does not exist or the bot is not in it
+	ErrGuildNotFound = errors.New("discord guild not found")
+	// ErrRateLimited is returned when a rate limit outlasts the request deadline
+	ErrRateLimited = errors.New("discord rate limited")
+
+	snowflakePattern = regexp.MustCompile(`^[0-9]{15,21}$`)
+)
+
+// Client calls the Discord REST API with a bot token
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a Discord client.
+// Config via ENV:
+//   - DISCORD_BOT_TOKEN: bot token; the bot must be in the guild (GUILD_MEMBERS intent is not needed for REST)
+//   - DISCORD_API_BASE_URL (optional): override the API base URL (defaults to https://discord.com/api/v10)
+func NewClient() (*Client, error) {
+	token := os.Getenv("DISCORD_BOT_TOKEN")
+	if token == "" {
+		return nil, errors.New("DISCORD_BOT_TOKEN environment variable not set")
+	}
+	baseURL := strings.TrimRight(os.Getenv("DISCORD_API_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = defaultAPIBaseURL
+	}
+	return &Client{
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// GuildMember is the guild member object
+type GuildMember struct {
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Nick     string    `json:"nick"`
+	Roles    []string  `json:"roles"`
+	JoinedAt time.Time `json:"joined_at"`
+	// Pending is set while the member has not passed membership screening
+	Pending bool `json:"pending"`
+}
+
+// HasRole reports whether the member has roleID
+func (m *GuildMember) HasRole(roleID string) bool {
+	for _, r := range m.Roles {
+		if r == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+// apiError is the Discord JSON error body
+type apiError struct {
+	Code       int     `json:"code"`
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+// GetGuildMember fetches a member of a guild; a nil member with no error means "Unknown Member"
+func (c *Client) GetGuildMember(ctx context.Context, guildID string, userID string) (*GuildMember, error) {
+	route := "guilds/" + guildID + "/members"
+	url := c.baseURL + "/guilds/" + guildID + "/members/" + userID
+
+	for attempt := 0; ; attempt++ {
+		if err := limiter.wait(ctx, route); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bot "+c.token)
+		req.Header.Set("User-Agent", "DiscordBot (checkingsocial, 1.0)")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to make request: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		limiter.update(route, resp.Header)
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			var member GuildMember
+			if err := json.Unmarshal(body, &member); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+			}
+			return &member, nil
+		case http.StatusTooManyRequests:
+			var apiErr apiError
+			_ = json.Unmarshal(body, &apiErr)
+			retryAfter := time.Duration(apiErr.RetryAfter * float64(time.Second))
+			limiter.block(route, apiErr.Global, retryAfter)
+			log.Printf("[Discord][WARN] rate limited route=%s global=%v retry_after=%s", route, apiErr.Global, retryAfter)
+			if attempt >= maxRateLimitRetries {
+				return nil, ratelimit.New(ErrRateLimited, retryAfter, "retry after "+retryAfter.String())
+			}
+			continue
+		case http.StatusNotFound:
+			var apiErr apiError
+			_ = json.Unmarshal(body, &apiErr)
+			switch apiErr.Code {
+			case codeUnknownMember, codeUnknownUser:
+				return nil, nil
+			case codeUnknownGuild:
+				return nil, fmt.Errorf("%w: %s", ErrGuildNotFound, guildID)
+			}
+			return nil, fmt.Errorf("discord request failed with status 404: %s", apiErr.Message)
+		case http.StatusForbidden:
+			// Missing Access: the bot is not in the guild
+			return nil, fmt.Errorf("%w: bot has no access to guild %s", ErrGuildNotFound, guildID)
+		}
+
+		snippet := string(body)
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		return nil, fmt.Errorf("discord request failed with status %d: %s", resp.StatusCode, snippet)
+	}
+}
+
+// MemberResult is the outcome of CheckMember
+type MemberResult struct {
+	UserID  string
+	GuildID string
+	RoleID  string
+	Status  string
+	Done    bool
+}
+
+// CheckMember checks join_guild or has_role for the Discord user ID.
+// target for join_guild is a guild ID (empty uses DISCORD_GUILD_ID); for has_role it is a role ID
+// (guild from DISCORD_GUILD_ID) or "<guild_id>/<role_id>" (empty uses DISCORD_ROLE_ID).
+func CheckMember(userID string, action string, target string) (*MemberResult, error) {
+	_ = godotenv.Load()
+
+	userID = strings.TrimSpace(userID)
+	if !snowflakePattern.MatchString(userID) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidUserID, userID)
+	}
+	res := &MemberResult{UserID: userID}
+	if err := res.parseTarget(action, strings.TrimSpace(target)); err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	member, err := client.GetGuildMember(ctx, res.GuildID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case member == nil:
+		res.Status = StatusNotMember
+	case member.Pending:
+		res.Status = StatusPending
+	default:
+		res.Status = StatusMember
+	}
+	res.Done = res.Status == StatusMember
+	if action == ActionHasRole {
+		res.Done = res.Done && member.HasRole(res.RoleID)
+	}
+
+	log.Printf("[Discord][DEBUG] CheckMember user=%s action=%s guild=%s role=%s status=%s done=%v",
+		userID, action, res.GuildID, res.RoleID, res.Status, res.Done)
+	return res, nil
+}
+
+// parseTarget fills GuildID and RoleID from the target and the env defaults
+func (r *MemberResult) parseTarget(action string, target string) error {
+	r.GuildID = strings.TrimSpace(os.Getenv("DISCORD_GUILD_ID"))
+	switch action {
+	case ActionJoinGuild:
+		if target != "" {
+			r.GuildID = target
+		}
+	case ActionHasRole:
+		if target == "" {
+			target = strings.TrimSpace(os.Getenv("DISCORD_ROLE_ID"))
+		}
+		if guild, role, ok := strings.Cut(target, "/"); ok {
+			r.GuildID, target = guild, role
+		}
+		r.RoleID = target
+		if !snowflakePattern.MatchString(r.RoleID) {
+			return fmt.Errorf("%w: role %q", ErrInvalidTarget, r.RoleID)
+		}
+	default:
+		return fmt.Errorf("unsupported discord action %q", action)
+	}
+	if !snowflakePattern.MatchString(r.GuildID) {
+		return fmt.Errorf("%w: guild %q", ErrInvalidTarget, r.GuildID)
+	}
+	return nil
+}
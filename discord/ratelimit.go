@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// limiter is shared by all clients so buckets survive between checks
+var limiter = newRateLimiter()
+
+// rateLimiter tracks Discord rate-limit buckets. Routes are mapped to the bucket hash Discord reports
+// in X-RateLimit-Bucket; a request waits when its bucket (or the global limit) is exhausted.
+type rateLimiter struct {
+	mu sync.Mutex
+	// routeBucket maps a route (which includes the guild, the major parameter) to its bucket hash
+	routeBucket map[string]string
+	// resets holds when an exhausted bucket (keyed by bucket hash + route) resets
+	resets      map[string]time.Time
+	globalReset time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{routeBucket: map[string]string{}, resets: map[string]time.Time{}}
+}
+
+// key returns the reset key of route: its bucket hash when known, the route otherwise
+func (l *rateLimiter) key(route string) string {
+	if bucket, ok := l.routeBucket[route]; ok {
+		return bucket + ":" + route
+	}
+	return route
+}
+
+// wait blocks until route may be requested; it fails fast when the wait would outlast ctx
+func (l *rateLimiter) wait(ctx context.Context, route string) error {
+	l.mu.Lock()
+	until := l.globalReset
+	if reset, ok := l.resets[l.key(route)]; ok && reset.After(until) {
+		until = reset
+	}
+	l.mu.Unlock()
+
+	delay := time.Until(until)
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return ratelimit.New(ErrRateLimited, delay, "bucket resets in "+delay.Round(time.Millisecond).String())
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// update records the bucket headers of a response
+func (l *rateLimiter) update(route string, h http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if bucket := h.Get("X-RateLimit-Bucket"); bucket != "" {
+		l.routeBucket[route] = bucket
+	}
+	key := l.key(route)
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining > 0 {
+		delete(l.resets, key)
+		return
+	}
+	if after, err := strconv.ParseFloat(h.Get("X-RateLimit-Reset-After"), 64); err == nil {
+		l.resets[key] = time.Now().Add(time.Duration(after * float64(time.Second)))
+	}
+}
+
+// block marks route (or every route when global) as limited for retryAfter after a 429
+func (l *rateLimiter) block(route string, global bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := time.Now().Add(retryAfter)
+	if global {
+		l.globalReset = until
+		return
+	}
+	l.resets[l.key(route)] = until
+}
@@ -0,0 +1,154 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+const (
+	testGuild      = "111111111111111111"
+	testOtherGuild = "222222222222222222"
+	testUser       = "333333333333333333"
+)
+
+// fakeDiscord answers the guild member route with respond and counts requests; the shared limiter is
+// replaced for the duration of the test
+func fakeDiscord(t *testing.T, respond func(w http.ResponseWriter, r *http.Request, hit int32)) (*Client, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r, hits.Add(1))
+	}))
+	t.Cleanup(srv.Close)
+
+	saved := limiter
+	limiter = newRateLimiter()
+	t.Cleanup(func() { limiter = saved })
+
+	t.Setenv("DISCORD_BOT_TOKEN", "test-token")
+	t.Setenv("DISCORD_API_BASE_URL", srv.URL)
+	client, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, &hits
+}
+
+// writeMember answers 200 with the bucket headers
+func writeMember(w http.ResponseWriter, remaining string, resetAfter string) {
+	w.Header().Set("X-RateLimit-Bucket", "members-bucket")
+	w.Header().Set("X-RateLimit-Remaining", remaining)
+	w.Header().Set("X-RateLimit-Reset-After", resetAfter)
+	w.Write([]byte(`{"user":{"id":"` + testUser + `"},"roles":[]}`))
+}
+
+func TestBucketWaitsForReset(t *testing.T) {
+	client, hits := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request, hit int32) {
+		writeMember(w, "0", "0.3")
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.GetGuildMember(ctx, testGuild, testUser); err != nil {
+		t.Fatal(err)
+	}
+	// The bucket is per guild (the major parameter), so another guild is not held
+	start := time.Now()
+	if _, err := client.GetGuildMember(ctx, testOtherGuild, testUser); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited > 200*time.Millisecond {
+		t.Errorf("other guild waited %v", waited)
+	}
+	start = time.Now()
+	if _, err := client.GetGuildMember(ctx, testGuild, testUser); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited < 200*time.Millisecond {
+		t.Errorf("exhausted bucket waited only %v, want until its reset", waited)
+	}
+	if n := hits.Load(); n != 3 {
+		t.Errorf("API got %d requests, want 3", n)
+	}
+}
+
+func TestBucketFailsFastPastDeadline(t *testing.T) {
+	client, hits := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request, hit int32) {
+		writeMember(w, "0", "60")
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := client.GetGuildMember(ctx, testGuild, testUser); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.GetGuildMember(ctx, testGuild, testUser)
+	var rl *ratelimit.Error
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) || rl.RetryAfter < 55*time.Second {
+		t.Fatalf("got %v, want ErrRateLimited retrying after about 60s", err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("API got %d requests, want the exhausted bucket to stop the second one", n)
+	}
+}
+
+func TestRetriesAfter429(t *testing.T) {
+	client, hits := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request, hit int32) {
+		if hit == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.2,"global":false}`))
+			return
+		}
+		writeMember(w, "4", "1")
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	member, err := client.GetGuildMember(ctx, testGuild, testUser)
+	if err != nil || member == nil {
+		t.Fatalf("member=%v err=%v", member, err)
+	}
+	if waited := time.Since(start); waited < 150*time.Millisecond {
+		t.Errorf("retried after %v, want retry_after to be honoured", waited)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("API got %d requests, want 2", n)
+	}
+}
+
+func TestGiveUpAfterRepeated429(t *testing.T) {
+	client, hits := fakeDiscord(t, func(w http.ResponseWriter, r *http.Request, hit int32) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.05,"global":true}`))
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.GetGuildMember(ctx, testGuild, testUser)
+	var rl *ratelimit.Error
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) || rl.RetryAfter != 50*time.Millisecond {
+		t.Fatalf("got %v, want ErrRateLimited carrying the last retry_after", err)
+	}
+	if n := hits.Load(); n != maxRateLimitRetries+1 {
+		t.Errorf("API got %d requests, want %d", n, maxRateLimitRetries+1)
+	}
+}
+
+func TestGlobalLimitHoldsEveryRoute(t *testing.T) {
+	l := newRateLimiter()
+	l.block("guilds/"+testGuild+"/members", true, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.wait(ctx, "guilds/"+testOtherGuild+"/members"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want the global limit to hold another route", err)
+	}
+}
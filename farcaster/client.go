@@ -2,44 +2,241 @@ package farcaster
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"time"
 
+	"checkingsocial/pkg/cache"
+
 	"github.com/joho/godotenv"
 )
 
-// CheckFollow checks if a user (userID) follows the TARGET_FIDS using Neynar API only
-// Redis and cronjob paths have been removed from this flow.
+// Relationships that can be verified against the target account
+const (
+	// RelationFollow: the user follows the target
+	RelationFollow = "follow"
+	// RelationFollowedBy: the target follows the user back
+	RelationFollowedBy = "followed_by"
+	// RelationMutual: both follow each other
+	RelationMutual = "mutual_follow"
+)
+
+// SourceCache is reported by FollowResult.Source and EngagementResult.Source when Redis answered;
+// otherwise the source is the Provider name
+const SourceCache = "redis"
+
+// Relationship statuses reported by FollowResult.Status
+const (
+	StatusOK      = "ok"
+	StatusMuted   = "muted"
+	StatusBlocked = "blocked"
+)
+
+// Relationship is the user's relationship to the target, from the user's point of view
+type Relationship struct {
+	// Following: the user follows the target
+	Following bool
+	// FollowedBy: the target follows the user
+	FollowedBy bool
+	// Blocked: the user has blocked the target
+	Blocked bool
+	// BlockedBy: the target has blocked the user
+	BlockedBy bool
+	// Muted: the user has muted the target
+	Muted bool
+	// MutedBy: the target has muted the user
+	MutedBy bool
+}
+
+// FollowResult is the outcome of a follow check together with the resolved FIDs
+type FollowResult struct {
+	UserFID   int64
+	TargetFID int64
+	// Source is where the answer came from: SourceCache or the Provider name
+	Source string
+	Relationship
+	// Ineligible is set when the user failed one of the configured EligibilityRules
+	Ineligible *EligibilityFailure
+	// Verifiable is false when a follow list was too long to scan, so the relationship could not be checked
+	Verifiable bool
+	// Reason explains why the check was not verifiable
+	Reason string
+}
+
+// KnowsMuteBlock reports whether the source that answered knows mute and block state;
+// the Redis follow state and hubs only know follow links
+func (r *FollowResult) KnowsMuteBlock() bool {
+	return r.Source != SourceCache && r.Source != ProviderHub
+}
+
+// Status summarizes mute/block state: blocked (either direction), muted (user muted the target) or ok.
+// It is empty when the source does not know mutes and blocks (see KnowsMuteBlock).
+func (r *FollowResult) Status() string {
+	switch {
+	case !r.KnowsMuteBlock():
+		return ""
+	case r.Blocked || r.BlockedBy:
+		return StatusBlocked
+	case r.Muted:
+		return StatusMuted
+	}
+	return StatusOK
+}
+
+// Holds reports whether the given relationship exists, ignoring eligibility
+func (r *FollowResult) Holds(relation string) bool {
+	switch relation {
+	case RelationFollow:
+		return r.Following
+	case RelationFollowedBy:
+		return r.FollowedBy
+	case RelationMutual:
+		return r.Following && r.FollowedBy
+	}
+	return false
+}
+
+// Verified reports whether the relationship counts: it exists and the user passed the eligibility rules
+func (r *FollowResult) Verified(relation string) bool {
+	return r.Holds(relation) && r.Ineligible == nil
+}
+
+// CheckFollow checks if a user (userID) follows the TARGET_FIDS.
+// Follow state pushed by Neynar webhooks into Redis is used when available, Neynar API otherwise.
 func CheckFollow(userID string) (bool, error) {
+	res, err := CheckFollowDetailed(userID, "", RelationFollow)
+	if err != nil {
+		return false, err
+	}
+	return res.Verified(RelationFollow), nil
+}
+
+// CheckFollowDetailed reports the follow relationship in both directions between the user and the target,
+// together with the FID the identifier resolved to.
+// userID and target may be a FID, a username, a profile URL or a verified ETH address (see ResolveFID);
+// an empty target defaults to TARGET_FIDS. relation is the relationship the caller is about to verify,
+// it decides whether the Redis state alone is enough to answer.
+func CheckFollowDetailed(userID string, target string, relation string) (*FollowResult, error) {
 	// Load environment variables from .env file
 	_ = godotenv.Load()
 
-	targetFIDStr := os.Getenv("TARGET_FIDS")
-	if targetFIDStr == "" {
-		return false, fmt.Errorf("TARGET_FIDS environment variable not set or empty")
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	targetFID, err := resolveTarget(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve userID (FID, username, profile URL or address) to a FID
+	userFID, err := ResolveFID(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse userID as int64
-	userFID, err := strconv.ParseInt(userID, 10, 64)
+	rules, err := LoadEligibilityRules()
 	if err != nil {
-		return false, fmt.Errorf("invalid userID format: %w", err)
+		return nil, err
+	}
+
+	// Webhook-fed Redis state answers instantly when no rule needs the Neynar profile or viewer context
+	if !rules.Enabled() {
+		if res, ok := cachedFollowResult(ctx, userFID, targetFID, relation); ok {
+			log.Printf("[Neynar][DEBUG] CheckFollowDetailed userFID=%d targetFID=%d answered from cache following=%v followed_by=%v", userFID, targetFID, res.Following, res.FollowedBy)
+			return res, nil
+		}
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Neynar][DEBUG] %s path for follow check targetFID=%d userFID=%d", provider.Name(), targetFID, userFID)
+	res := &FollowResult{UserFID: userFID, TargetFID: targetFID, Source: provider.Name(), Verifiable: true}
+
+	// Eligibility rules need the user's own profile, fetched together with the relationship
+	rel, user, err := provider.FetchRelationship(ctx, userFID, targetFID, rules)
+	if errors.Is(err, ErrScanLimit) {
+		res.Verifiable, res.Reason = false, err.Error()
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.Relationship = rel
+
+	if user != nil {
+		res.Ineligible = rules.Evaluate(user, time.Now())
+	}
+	if res.Ineligible == nil {
+		res.Ineligible = rules.EvaluateRelationship(res.Relationship)
+	}
+	if res.Ineligible != nil {
+		log.Printf("[Neynar][DEBUG] userFID=%d failed eligibility rule=%s reason=%s", userFID, res.Ineligible.Rule, res.Ineligible.Reason)
+	}
+	log.Printf("[Neynar][DEBUG] CheckFollowDetailed userFID=%d following=%v followed_by=%v status=%s", userFID, res.Following, res.FollowedBy, res.Status())
+	return res, nil
+}
+
+// resolveTarget resolves the target identifier, defaulting to TARGET_FIDS
+func resolveTarget(ctx context.Context, target string) (int64, error) {
+	if target != "" {
+		return ResolveFID(ctx, target)
+	}
+
+	targetFIDStr := os.Getenv("TARGET_FIDS")
+	if targetFIDStr == "" {
+		return 0, fmt.Errorf("TARGET_FIDS environment variable not set or empty")
 	}
 
 	// Parse targetFID as int64
 	targetFID, err := strconv.ParseInt(targetFIDStr, 10, 64)
 	if err != nil {
-		return false, fmt.Errorf("invalid targetFID format: %w", err)
+		return 0, fmt.Errorf("invalid targetFID format: %w", err)
 	}
+	return targetFID, nil
+}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// cachedFollowResult answers a follow check from the Redis follow state when every direction
+// the relation needs is known. Only those directions are set; mute and block state is unknown.
+// Cache errors are logged and treated as a miss.
+func cachedFollowResult(ctx context.Context, userFID int64, targetFID int64, relation string) (*FollowResult, bool) {
+	if !cache.Enabled() {
+		return nil, false
+	}
+	if relation != RelationFollow && relation != RelationFollowedBy && relation != RelationMutual {
+		return nil, false
+	}
 
-	log.Printf("[Neynar][DEBUG] Forcing Neynar API path for follow check TARGET_FIDS=%s userFID=%d", targetFIDStr, userFID)
-	return CheckFollowUsingNeynar(ctx, userFID, targetFID)
+	res := &FollowResult{UserFID: userFID, TargetFID: targetFID, Source: SourceCache, Verifiable: true}
+	if relation == RelationFollow || relation == RelationMutual {
+		known, following, err := cache.FollowState(ctx, strconv.FormatInt(targetFID, 10), userFID)
+		if err != nil {
+			log.Printf("[Redis][ERROR] follow state target=%d user=%d: %v", targetFID, userFID, err)
+			return nil, false
+		}
+		if !known {
+			return nil, false
+		}
+		res.Following = following
+	}
+	if relation == RelationFollowedBy || relation == RelationMutual {
+		known, followedBy, err := cache.FollowState(ctx, strconv.FormatInt(userFID, 10), targetFID)
+		if err != nil {
+			log.Printf("[Redis][ERROR] follow state target=%d user=%d: %v", userFID, targetFID, err)
+			return nil, false
+		}
+		if !known {
+			return nil, false
+		}
+		res.FollowedBy = followedBy
+	}
+	return res, true
 }
 
 // CheckFollowUsingNeynar checks if a user follows a target FID using Neynar API
@@ -58,3 +255,22 @@ func CheckFollowUsingNeynar(ctx context.Context, userFID int64, targetFID int64)
 	log.Printf("[Neynar][DEBUG] CheckFollowUsingNeynar result=%v", res)
 	return res, nil
 }
+
+// GetUser fetches the full profile from the configured provider for an identifier (FID, username, profile URL or address)
+func GetUser(identifier string) (*NeynarUser, error) {
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	fid, err := ResolveFID(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.FetchUser(ctx, fid)
+}
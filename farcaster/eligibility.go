@@ -0,0 +1,148 @@
+package farcaster
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Eligibility rule names reported in EligibilityFailure.Rule
+const (
+	RuleMinScore        = "min_score"
+	RuleMinFollowers    = "min_followers"
+	RuleMinAccountAge   = "min_account_age"
+	RuleVerifiedAddress = "verified_address"
+	RulePowerBadge      = "power_badge"
+	RuleNotMuted        = "not_muted"
+	RuleNotBlocked      = "not_blocked"
+)
+
+// EligibilityRules are quality gates a user must pass for a verification to count.
+// Zero values disable the corresponding rule.
+type EligibilityRules struct {
+	MinScore               float64
+	MinFollowers           int64
+	MinAccountAge          time.Duration
+	RequireVerifiedAddress bool
+	RequirePowerBadge      bool
+	// RejectMuted fails the verification when the user has muted the target
+	RejectMuted bool
+	// RejectBlocked fails the verification when either side has blocked the other
+	RejectBlocked bool
+}
+
+// EligibilityFailure describes the first rule a user failed
+type EligibilityFailure struct {
+	Rule   string
+	Reason string
+}
+
+// LoadEligibilityRules reads the rules from the environment:
+//   - FARCASTER_MIN_SCORE: minimum Neynar user score (0..1)
+//   - FARCASTER_MIN_FOLLOWERS: minimum follower count
+//   - FARCASTER_MIN_ACCOUNT_AGE: minimum account age, e.g. "720h" or "30d"
+//   - FARCASTER_REQUIRE_VERIFIED_ADDRESS: require at least one verified ETH/SOL address
+//   - FARCASTER_REQUIRE_POWER_BADGE: require the power badge
+//   - FARCASTER_REJECT_MUTED: fail when the user has muted the target
+//   - FARCASTER_REJECT_BLOCKED: fail when the user and the target have blocked each other
+func LoadEligibilityRules() (EligibilityRules, error) {
+	var rules EligibilityRules
+
+	if v := strings.TrimSpace(os.Getenv("FARCASTER_MIN_SCORE")); v != "" {
+		score, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return rules, fmt.Errorf("invalid FARCASTER_MIN_SCORE: %w", err)
+		}
+		rules.MinScore = score
+	}
+	if v := strings.TrimSpace(os.Getenv("FARCASTER_MIN_FOLLOWERS")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return rules, fmt.Errorf("invalid FARCASTER_MIN_FOLLOWERS: %w", err)
+		}
+		rules.MinFollowers = n
+	}
+	if v := strings.TrimSpace(os.Getenv("FARCASTER_MIN_ACCOUNT_AGE")); v != "" {
+		d, err := parseAge(v)
+		if err != nil {
+			return rules, fmt.Errorf("invalid FARCASTER_MIN_ACCOUNT_AGE: %w", err)
+		}
+		rules.MinAccountAge = d
+	}
+	rules.RequireVerifiedAddress = isTruthy(os.Getenv("FARCASTER_REQUIRE_VERIFIED_ADDRESS"))
+	rules.RequirePowerBadge = isTruthy(os.Getenv("FARCASTER_REQUIRE_POWER_BADGE"))
+	rules.RejectMuted = isTruthy(os.Getenv("FARCASTER_REJECT_MUTED"))
+	rules.RejectBlocked = isTruthy(os.Getenv("FARCASTER_REJECT_BLOCKED"))
+	return rules, nil
+}
+
+// Enabled reports whether any rule is configured
+func (r EligibilityRules) Enabled() bool {
+	return r.NeedsProfile() || r.RejectMuted || r.RejectBlocked
+}
+
+// NeedsProfile reports whether any rule is evaluated against the user's own profile
+func (r EligibilityRules) NeedsProfile() bool {
+	return r.MinScore > 0 || r.MinFollowers > 0 || r.MinAccountAge > 0 || r.RequireVerifiedAddress || r.RequirePowerBadge
+}
+
+// EvaluateRelationship checks the mute/block policy against the user's relationship to the target
+func (r EligibilityRules) EvaluateRelationship(rel Relationship) *EligibilityFailure {
+	if r.RejectBlocked && (rel.Blocked || rel.BlockedBy) {
+		return &EligibilityFailure{Rule: RuleNotBlocked, Reason: "user and target account have blocked each other"}
+	}
+	if r.RejectMuted && rel.Muted {
+		return &EligibilityFailure{Rule: RuleNotMuted, Reason: "user has muted the target account"}
+	}
+	return nil
+}
+
+// Evaluate checks the user against the rules and returns the first failed rule, or nil if the user is eligible
+func (r EligibilityRules) Evaluate(user *NeynarUser, now time.Time) *EligibilityFailure {
+	if r.MinScore > 0 {
+		if score := user.NeynarScore(); score < r.MinScore {
+			return &EligibilityFailure{Rule: RuleMinScore, Reason: fmt.Sprintf("neynar user score %.2f is below %.2f", score, r.MinScore)}
+		}
+	}
+	if r.MinFollowers > 0 && user.FollowerCount < r.MinFollowers {
+		return &EligibilityFailure{Rule: RuleMinFollowers, Reason: fmt.Sprintf("follower count %d is below %d", user.FollowerCount, r.MinFollowers)}
+	}
+	if r.MinAccountAge > 0 {
+		if user.RegisteredAt == nil {
+			return &EligibilityFailure{Rule: RuleMinAccountAge, Reason: "account registration time unavailable"}
+		}
+		if age := now.Sub(*user.RegisteredAt); age < r.MinAccountAge {
+			return &EligibilityFailure{Rule: RuleMinAccountAge, Reason: fmt.Sprintf("account age %s is below %s", age.Round(time.Hour), r.MinAccountAge)}
+		}
+	}
+	if r.RequireVerifiedAddress && len(user.VerifiedAddresses.EthAddresses) == 0 && len(user.VerifiedAddresses.SolAddresses) == 0 {
+		return &EligibilityFailure{Rule: RuleVerifiedAddress, Reason: "no verified address"}
+	}
+	if r.RequirePowerBadge && !user.PowerBadge {
+		return &EligibilityFailure{Rule: RulePowerBadge, Reason: "power badge required"}
+	}
+	return nil
+}
+
+// parseAge parses a Go duration, additionally accepting a whole number of days ("30d")
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// isTruthy returns true if an env-like string represents a truthy value
+func isTruthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "y", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package farcaster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"checkingsocial/pkg/cache"
+
+	"github.com/joho/godotenv"
+)
+
+// Engagement actions on a cast
+const (
+	ActionLike   = "like"
+	ActionRecast = "recast"
+	ActionReply  = "reply"
+)
+
+// maxReplyPages bounds how many conversation pages are scanned for a reply
+const maxReplyPages = 10
+
+var (
+	// ErrInvalidCastHash is returned when the target is not a 0x-prefixed cast hash
+	ErrInvalidCastHash = errors.New("invalid cast hash")
+
+	castHashPattern = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+)
+
+// EngagementResult is the outcome of a like/recast/reply check
+type EngagementResult struct {
+	UserFID  int64
+	CastHash string
+	Done     bool
+	// Source is where the answer came from: SourceCache or the Provider name
+	Source string
+	// Verifiable is false when the list was too long to scan, so Done could not be checked
+	Verifiable bool
+	// Reason explains why the check was not verifiable
+	Reason string
+}
+
+// CheckEngagement checks if the user liked, recasted or replied to castHash.
+// A positive answer recorded by webhooks in Redis is returned instantly, otherwise the provider is asked.
+func CheckEngagement(userID string, castHash string, action string) (*EngagementResult, error) {
+	_ = godotenv.Load()
+
+	castHash = strings.ToLower(strings.TrimSpace(castHash))
+	if !castHashPattern.MatchString(castHash) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCastHash, castHash)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	userFID, err := ResolveFID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &EngagementResult{UserFID: userFID, CastHash: castHash, Verifiable: true}
+	if done, ok := cachedEngagement(ctx, userFID, castHash, action); ok && done {
+		res.Done = true
+		res.Source = SourceCache
+		return res, nil
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	res.Source = provider.Name()
+
+	switch action {
+	case ActionLike, ActionRecast:
+		res.Done, err = provider.HasReacted(ctx, userFID, castHash, action)
+	case ActionReply:
+		res.Done, err = provider.HasReplied(ctx, userFID, castHash)
+	default:
+		return nil, fmt.Errorf("unsupported engagement action %q", action)
+	}
+	if errors.Is(err, ErrScanLimit) {
+		res.Verifiable, res.Reason = false, err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Neynar][DEBUG] CheckEngagement userFID=%d cast=%s action=%s done=%v verifiable=%v", userFID, castHash, action, res.Done, res.Verifiable)
+	return res, nil
+}
+
+// cachedEngagement looks the engagement up in the webhook-fed Redis state
+func cachedEngagement(ctx context.Context, fid int64, castHash string, action string) (done bool, ok bool) {
+	if !cache.Enabled() {
+		return false, false
+	}
+
+	var err error
+	switch action {
+	case ActionLike:
+		done, err = cache.HasReaction(ctx, "likes", castHash, fid)
+	case ActionRecast:
+		done, err = cache.HasReaction(ctx, "recasts", castHash, fid)
+	case ActionReply:
+		done, err = cache.HasReplied(ctx, castHash, fid)
+	default:
+		return false, false
+	}
+	if err != nil {
+		log.Printf("[Redis][ERROR] engagement %s cast=%s fid=%d: %v", action, castHash, fid, err)
+		return false, false
+	}
+	return done, true
+}
@@ -0,0 +1,171 @@
+package farcaster
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"checkingsocial/pkg/cache"
+)
+
+// fakeRedis is an in-process RESP server implementing the set and string commands used by pkg/cache
+type fakeRedis struct {
+	mu      sync.Mutex
+	sets    map[string]map[string]bool
+	strings map[string]string
+}
+
+// startFakeRedis serves a fakeRedis on a local port and points pkg/cache at it for the test
+func startFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeRedis{sets: map[string]map[string]bool{}, strings: map[string]string{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+
+	t.Setenv("REDIS_ADDR", ln.Addr().String())
+	t.Setenv("REDIS_DB", "")
+	t.Setenv("REDIS_PASSWORD", "")
+	if err := cache.InitRedis(); err != nil {
+		t.Fatalf("init redis: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cache.Close()
+		_ = ln.Close()
+	})
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	var queued [][]string
+	inMulti := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		switch name := strings.ToUpper(args[0]); {
+		case name == "MULTI":
+			inMulti, queued = true, nil
+			w.WriteString("+OK\r\n")
+		case name == "EXEC":
+			fmt.Fprintf(w, "*%d\r\n", len(queued))
+			for _, cmd := range queued {
+				w.WriteString(f.exec(cmd))
+			}
+			inMulti, queued = false, nil
+		case inMulti:
+			queued = append(queued, args)
+			w.WriteString("+QUEUED\r\n")
+		default:
+			w.WriteString(f.exec(args))
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// exec runs one command and returns its RESP2 reply
+func (f *fakeRedis) exec(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SADD", "SREM":
+		set := f.sets[args[1]]
+		if set == nil {
+			set = map[string]bool{}
+			f.sets[args[1]] = set
+		}
+		n := 0
+		for _, m := range args[2:] {
+			if set[m] != (strings.ToUpper(args[0]) == "SADD") {
+				set[m] = !set[m]
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "SISMEMBER":
+		if f.sets[args[1]][args[2]] {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	case "SCARD":
+		n := 0
+		for _, ok := range f.sets[args[1]] {
+			if ok {
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "DEL":
+		for _, k := range args[1:] {
+			delete(f.sets, k)
+			delete(f.strings, k)
+		}
+		return fmt.Sprintf(":%d\r\n", len(args)-1)
+	case "SET":
+		f.strings[args[1]] = args[2]
+		return "+OK\r\n"
+	case "GET":
+		v, ok := f.strings[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	}
+	// HELLO and anything else: go-redis falls back to RESP2 on an error reply
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+// readCommand reads one RESP array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("bad array header %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("bad bulk header %q", header)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
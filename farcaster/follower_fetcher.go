@@ -0,0 +1,57 @@
+package farcaster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"checkingsocial/pkg/cache"
+)
+
+// followerPageDelay is the pause between follower pages to stay under Neynar rate limits
+const followerPageDelay = 500 * time.Millisecond
+
+// FetchAndCacheFollowersUsingNeynar fetches every follower of targetFID and stores them in Redis.
+// It seeds the follow state that webhook events keep up to date afterwards.
+func (nc *NeynarClient) FetchAndCacheFollowersUsingNeynar(ctx context.Context, targetFID string) error {
+	if !cache.Enabled() {
+		return cache.ErrNotInitialized
+	}
+
+	if err := cache.ClearFollowers(ctx, targetFID); err != nil {
+		return fmt.Errorf("failed to clear followers cache: %w", err)
+	}
+
+	cursor := ""
+	total := 0
+	for {
+		resp, err := nc.FetchFollowers(ctx, targetFID, 100, cursor)
+		if err != nil {
+			return fmt.Errorf("failed to fetch followers: %w", err)
+		}
+
+		fids := resp.FIDs()
+		if err := cache.AddFollowerFIDs(ctx, targetFID, fids); err != nil {
+			return fmt.Errorf("failed to cache followers: %w", err)
+		}
+		total += len(fids)
+
+		if resp.Next == nil || resp.Next.Cursor == "" || len(fids) == 0 {
+			break
+		}
+		cursor = resp.Next.Cursor
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(followerPageDelay):
+		}
+	}
+
+	if err := cache.SetLastSyncTime(ctx, targetFID, time.Now()); err != nil {
+		return fmt.Errorf("failed to set last sync time: %w", err)
+	}
+	log.Printf("[Neynar] cached %d followers for targetFID=%s", total, targetFID)
+	return nil
+}
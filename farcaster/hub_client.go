@@ -0,0 +1,293 @@
+package farcaster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// hubPageSize is the page size requested from the Hub HTTP API
+	hubPageSize = 1000
+	// maxHubPages bounds how many pages are scanned per lookup
+	maxHubPages = 50
+)
+
+// Hub reaction types
+const (
+	hubReactionLike   = "REACTION_TYPE_LIKE"
+	hubReactionRecast = "REACTION_TYPE_RECAST"
+)
+
+// HubClient queries a Farcaster Hub's HTTP API directly, so no Neynar key is needed
+type HubClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHubClient creates a Hub client from FARCASTER_HUB_URL (e.g. http://localhost:2281)
+// and the optional FARCASTER_HUB_API_KEY for hosted hubs
+func NewHubClient() (*HubClient, error) {
+	baseURL := strings.TrimRight(os.Getenv("FARCASTER_HUB_URL"), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("FARCASTER_HUB_URL environment variable not set")
+	}
+
+	return &HubClient{
+		baseURL: baseURL,
+		apiKey:  os.Getenv("FARCASTER_HUB_API_KEY"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// HubCastID identifies a cast by author FID and hash
+type HubCastID struct {
+	Fid  int64  `json:"fid"`
+	Hash string `json:"hash"`
+}
+
+// HubMessageData is the data part of a Hub message; only one body is set depending on the type
+type HubMessageData struct {
+	Type      string `json:"type"`
+	Fid       int64  `json:"fid"`
+	Timestamp int64  `json:"timestamp"`
+	LinkBody  *struct {
+		Type      string `json:"type"`
+		TargetFid int64  `json:"targetFid"`
+	} `json:"linkBody,omitempty"`
+	ReactionBody *struct {
+		Type         string     `json:"type"`
+		TargetCastID *HubCastID `json:"targetCastId,omitempty"`
+	} `json:"reactionBody,omitempty"`
+	CastAddBody *struct {
+		Text         string     `json:"text"`
+		ParentCastID *HubCastID `json:"parentCastId,omitempty"`
+	} `json:"castAddBody,omitempty"`
+	UserDataBody *struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"userDataBody,omitempty"`
+	VerificationAddAddressBody *struct {
+		Address  string `json:"address"`
+		Protocol string `json:"protocol"`
+	} `json:"verificationAddAddressBody,omitempty"`
+}
+
+// HubMessage is a single signed message returned by the Hub
+type HubMessage struct {
+	Data HubMessageData `json:"data"`
+	Hash string         `json:"hash"`
+}
+
+// HubMessagesResponse is a page of messages
+type HubMessagesResponse struct {
+	Messages      []HubMessage `json:"messages"`
+	NextPageToken string       `json:"nextPageToken"`
+}
+
+// getJSON performs a GET against the Hub HTTP API and decodes the JSON body into out
+func (hc *HubClient) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", hc.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	req.Header.Set("accept", "application/json")
+	if hc.apiKey != "" {
+		req.Header.Set("x-api-key", hc.apiKey)
+	}
+
+	resp, err := hc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Hubs report missing entities as 404 or as 400 with errCode "not_found"
+	if resp.StatusCode == http.StatusNotFound || (resp.StatusCode == http.StatusBadRequest && strings.Contains(string(respBody), "not_found")) {
+		return ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("hub request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// scanMessages pages through a message endpoint (newest first) until match returns true.
+// It returns ErrScanLimit when there are more than maxHubPages pages and none matched.
+func (hc *HubClient) scanMessages(ctx context.Context, path string, query url.Values, match func(HubMessage) bool) (bool, error) {
+	query.Set("pageSize", strconv.Itoa(hubPageSize))
+	query.Set("reverse", "true")
+	for page := 0; page < maxHubPages; page++ {
+		var resp HubMessagesResponse
+		if err := hc.getJSON(ctx, path, query, &resp); err != nil {
+			return false, err
+		}
+		for _, msg := range resp.Messages {
+			if match(msg) {
+				return true, nil
+			}
+		}
+		if resp.NextPageToken == "" || len(resp.Messages) == 0 {
+			return false, nil
+		}
+		query.Set("pageToken", resp.NextPageToken)
+	}
+	return false, fmt.Errorf("%w: %s of fid %s has more than %d pages", ErrScanLimit, path, query.Get("fid"), maxHubPages)
+}
+
+// follows checks linksByFid of fid for a follow link to targetFID
+func (hc *HubClient) follows(ctx context.Context, fid int64, targetFID int64) (bool, error) {
+	q := url.Values{}
+	q.Set("fid", strconv.FormatInt(fid, 10))
+	q.Set("link_type", "follow")
+	return hc.scanMessages(ctx, "/v1/linksByFid", q, func(msg HubMessage) bool {
+		return msg.Data.LinkBody != nil && msg.Data.LinkBody.TargetFid == targetFID
+	})
+}
+
+// Name implements Provider
+func (hc *HubClient) Name() string {
+	return ProviderHub
+}
+
+// FetchRelationship implements Provider with linksByFid in both directions.
+// Hubs carry no mute/block state and no profile data, so no eligibility rule can be evaluated.
+func (hc *HubClient) FetchRelationship(ctx context.Context, userFID int64, targetFID int64, rules EligibilityRules) (Relationship, *NeynarUser, error) {
+	if rules.NeedsProfile() {
+		return Relationship{}, nil, fmt.Errorf("%w: eligibility rules need the neynar provider", ErrNotSupported)
+	}
+	if rules.RejectMuted || rules.RejectBlocked {
+		return Relationship{}, nil, fmt.Errorf("%w: mute and block rules need the neynar provider", ErrNotSupported)
+	}
+
+	var rel Relationship
+	var err error
+	if rel.Following, err = hc.follows(ctx, userFID, targetFID); err != nil {
+		return Relationship{}, nil, err
+	}
+	if rel.FollowedBy, err = hc.follows(ctx, targetFID, userFID); err != nil {
+		return Relationship{}, nil, err
+	}
+	return rel, nil, nil
+}
+
+// HasReacted implements Provider with reactionsByFid
+func (hc *HubClient) HasReacted(ctx context.Context, userFID int64, castHash string, action string) (bool, error) {
+	q := url.Values{}
+	q.Set("fid", strconv.FormatInt(userFID, 10))
+	switch action {
+	case ActionLike:
+		q.Set("reaction_type", hubReactionLike)
+	case ActionRecast:
+		q.Set("reaction_type", hubReactionRecast)
+	default:
+		return false, fmt.Errorf("unsupported reaction %q", action)
+	}
+	return hc.scanMessages(ctx, "/v1/reactionsByFid", q, func(msg HubMessage) bool {
+		body := msg.Data.ReactionBody
+		return body != nil && body.TargetCastID != nil && strings.EqualFold(body.TargetCastID.Hash, castHash)
+	})
+}
+
+// HasReplied implements Provider with castsByFid
+func (hc *HubClient) HasReplied(ctx context.Context, userFID int64, castHash string) (bool, error) {
+	q := url.Values{}
+	q.Set("fid", strconv.FormatInt(userFID, 10))
+	return hc.scanMessages(ctx, "/v1/castsByFid", q, func(msg HubMessage) bool {
+		body := msg.Data.CastAddBody
+		return body != nil && body.ParentCastID != nil && strings.EqualFold(body.ParentCastID.Hash, castHash)
+	})
+}
+
+// FetchUser implements Provider with userDataByFid and verificationsByFid.
+// Counts, power badge and score are not available from a Hub and stay zero.
+func (hc *HubClient) FetchUser(ctx context.Context, fid int64) (*NeynarUser, error) {
+	q := url.Values{}
+	q.Set("fid", strconv.FormatInt(fid, 10))
+
+	var userData HubMessagesResponse
+	if err := hc.getJSON(ctx, "/v1/userDataByFid", q, &userData); err != nil {
+		return nil, err
+	}
+	if len(userData.Messages) == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	user := &NeynarUser{Fid: fid}
+	for _, msg := range userData.Messages {
+		body := msg.Data.UserDataBody
+		if body == nil {
+			continue
+		}
+		switch body.Type {
+		case "USER_DATA_TYPE_USERNAME":
+			user.Username = body.Value
+		case "USER_DATA_TYPE_DISPLAY":
+			user.DisplayName = body.Value
+		case "USER_DATA_TYPE_PFP":
+			user.PfpURL = body.Value
+		case "USER_DATA_TYPE_BIO":
+			user.Profile.Bio.Text = body.Value
+		}
+	}
+
+	var verifications HubMessagesResponse
+	if err := hc.getJSON(ctx, "/v1/verificationsByFid", q, &verifications); err != nil {
+		return nil, err
+	}
+	for _, msg := range verifications.Messages {
+		body := msg.Data.VerificationAddAddressBody
+		if body == nil {
+			continue
+		}
+		if body.Protocol == "PROTOCOL_SOLANA" {
+			user.VerifiedAddresses.SolAddresses = append(user.VerifiedAddresses.SolAddresses, body.Address)
+		} else {
+			user.VerifiedAddresses.EthAddresses = append(user.VerifiedAddresses.EthAddresses, strings.ToLower(body.Address))
+		}
+	}
+	return user, nil
+}
+
+// LookupUserByUsername implements Provider with userNameProofByName
+func (hc *HubClient) LookupUserByUsername(ctx context.Context, username string) (*NeynarUser, error) {
+	var proof struct {
+		Fid  int64  `json:"fid"`
+		Name string `json:"name"`
+	}
+	q := url.Values{}
+	q.Set("name", username)
+	if err := hc.getJSON(ctx, "/v1/userNameProofByName", q, &proof); err != nil {
+		return nil, err
+	}
+	if proof.Fid == 0 {
+		return nil, ErrUserNotFound
+	}
+	return &NeynarUser{Fid: proof.Fid, Username: proof.Name}, nil
+}
+
+// LookupUsersByAddress implements Provider; Hubs have no address -> FID index
+func (hc *HubClient) LookupUsersByAddress(ctx context.Context, address string) ([]NeynarUser, error) {
+	return nil, fmt.Errorf("%w: address lookup needs the neynar provider", ErrNotSupported)
+}
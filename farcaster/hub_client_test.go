@@ -0,0 +1,138 @@
+package farcaster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeHub stands in for a Hub HTTP API, answering with the messages recorded in testdata/hub.
+// A request maps to <endpoint>_<fid or name>[_<reaction>][_page2].json; anything else is not_found.
+func fakeHub(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		name := filepath.Base(r.URL.Path) + "_" + q.Get("fid") + q.Get("name")
+		switch q.Get("reaction_type") {
+		case hubReactionLike:
+			name += "_like"
+		case hubReactionRecast:
+			name += "_recast"
+		}
+		if r.URL.Path == "/v1/linksByFid" && q.Get("fid") == "4242" {
+			if q.Get("link_type") != "follow" || q.Get("reverse") != "true" {
+				t.Errorf("linksByFid query = %s", r.URL.RawQuery)
+			}
+			name += "_page1"
+			if q.Get("pageToken") == "AuzO1V0DtaItCwwa10X6YsfStlynsGWT" {
+				name = "linksByFid_4242_page2"
+			}
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", "hub", name+".json"))
+		if err != nil {
+			body, _ = os.ReadFile(filepath.Join("testdata", "hub", "not_found.json"))
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("FARCASTER_PROVIDER", ProviderHub)
+	t.Setenv("FARCASTER_HUB_URL", srv.URL)
+	return srv
+}
+
+func TestHubProvider(t *testing.T) {
+	fakeHub(t)
+	hub, err := NewHubClient()
+	if err != nil {
+		t.Fatalf("NewHubClient: %v", err)
+	}
+	ctx := context.Background()
+	cast := "0xa1b2c3d4e5f60718293a4b5c6d7e8f9001122334"
+
+	rel, _, err := hub.FetchRelationship(ctx, 4242, 3, EligibilityRules{})
+	if err != nil {
+		t.Fatalf("FetchRelationship: %v", err)
+	}
+	if !rel.Following || rel.FollowedBy {
+		t.Fatalf("relationship = %+v, want following (second page) and not followed by", rel)
+	}
+	if ok, err := hub.HasReacted(ctx, 4242, cast, ActionLike); err != nil || !ok {
+		t.Fatalf("like: %v, %v", ok, err)
+	}
+	if ok, err := hub.HasReacted(ctx, 4242, cast, ActionRecast); err != nil || ok {
+		t.Fatalf("recast: %v, %v", ok, err)
+	}
+	if ok, err := hub.HasReplied(ctx, 4242, cast); err != nil || !ok {
+		t.Fatalf("reply: %v, %v", ok, err)
+	}
+
+	user, err := hub.FetchUser(ctx, 4242)
+	if err != nil {
+		t.Fatalf("FetchUser: %v", err)
+	}
+	if user.Username != "alice" || user.Profile.Bio.Text != "building things" ||
+		len(user.VerifiedAddresses.EthAddresses) != 1 || user.VerifiedAddresses.EthAddresses[0] != "0xabcdefabcdef0123456789abcdefabcdef012345" {
+		t.Fatalf("user = %+v", user)
+	}
+	if _, err := hub.FetchUser(ctx, 999); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("unknown fid: got %v, want ErrUserNotFound", err)
+	}
+	if u, err := hub.LookupUserByUsername(ctx, "alice"); err != nil || u.Fid != 4242 {
+		t.Fatalf("username proof: %+v, %v", u, err)
+	}
+}
+
+func TestHubRejectsUnsupportedRules(t *testing.T) {
+	fakeHub(t)
+	t.Setenv("TARGET_FIDS", "3")
+
+	res, err := CheckFollowDetailed("4242", "", RelationFollow)
+	if err != nil {
+		t.Fatalf("CheckFollowDetailed without rules: %v", err)
+	}
+	if !res.Verified(RelationFollow) || res.Source != ProviderHub {
+		t.Fatalf("result = %+v, want verified by the hub", res)
+	}
+	if res.Status() != "" || res.KnowsMuteBlock() {
+		t.Fatalf("hub answer has status %q, want none: hubs know no mutes or blocks", res.Status())
+	}
+
+	for _, env := range []string{"FARCASTER_REJECT_MUTED", "FARCASTER_REJECT_BLOCKED", "FARCASTER_MIN_FOLLOWERS"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, "1")
+			if _, err := CheckFollowDetailed("alice", "", RelationFollow); !errors.Is(err, ErrNotSupported) {
+				t.Fatalf("got %v, want ErrNotSupported", err)
+			}
+		})
+	}
+}
+
+func TestHubScanLimit(t *testing.T) {
+	pages := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every page follows someone else and links another page
+		pages++
+		fmt.Fprintf(w, `{"messages":[{"data":{"fid":4242,"linkBody":{"type":"follow","targetFid":%d}}}],"nextPageToken":"p%d"}`, 1000+pages, pages)
+	}))
+	defer srv.Close()
+	t.Setenv("FARCASTER_PROVIDER", ProviderHub)
+	t.Setenv("FARCASTER_HUB_URL", srv.URL)
+
+	res, err := CheckFollowDetailed("4242", "3", RelationFollow)
+	if err != nil {
+		t.Fatalf("CheckFollowDetailed: %v", err)
+	}
+	if res.Verifiable || res.Reason == "" || res.Verified(RelationFollow) {
+		t.Fatalf("result = %+v, want unverifiable after the page cap", res)
+	}
+	if pages != maxHubPages {
+		t.Fatalf("scanned %d pages, want %d", pages, maxHubPages)
+	}
+}
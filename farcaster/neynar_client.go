@@ -3,34 +3,44 @@ package farcaster
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	neynarAPIBaseURL = "https://api.neynar.com/v2"
+	defaultNeynarAPIBaseURL = "https://api.neynar.com/v2"
 )
 
 // NeynarClient wraps the Neynar API client
 type NeynarClient struct {
+	baseURL    string
 	apiKey     string
 	httpClient *http.Client
 }
 
-// NewNeynarClient creates a new Neynar API client
+// NewNeynarClient creates a new Neynar API client from NEYNAR_API_KEY and the optional
+// NEYNAR_API_BASE_URL (defaults to https://api.neynar.com/v2)
 func NewNeynarClient() (*NeynarClient, error) {
 	apiKey := os.Getenv("NEYNAR_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("NEYNAR_API_KEY environment variable not set")
 	}
+	baseURL := strings.TrimRight(os.Getenv("NEYNAR_API_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = defaultNeynarAPIBaseURL
+	}
 
 	return &NeynarClient{
-		apiKey: apiKey,
+		baseURL: baseURL,
+		apiKey:  apiKey,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -44,10 +54,51 @@ type FetchBulkUsersResponse struct {
 
 // NeynarUser represents a user from Neynar API
 type NeynarUser struct {
-	Fid           int64          `json:"fid"`
-	Username      string         `json:"username"`
-	DisplayName   string         `json:"display_name"`
-	ViewerContext *ViewerContext `json:"viewer_context"`
+	Fid               int64             `json:"fid"`
+	Username          string            `json:"username"`
+	DisplayName       string            `json:"display_name"`
+	CustodyAddress    string            `json:"custody_address"`
+	PfpURL            string            `json:"pfp_url"`
+	Profile           UserProfile       `json:"profile"`
+	FollowerCount     int64             `json:"follower_count"`
+	FollowingCount    int64             `json:"following_count"`
+	Verifications     []string          `json:"verifications"`
+	VerifiedAddresses VerifiedAddresses `json:"verified_addresses"`
+	PowerBadge        bool              `json:"power_badge"`
+	Score             *float64          `json:"score,omitempty"`
+	Experimental      *Experimental     `json:"experimental,omitempty"`
+	RegisteredAt      *time.Time        `json:"registered_at,omitempty"`
+	ViewerContext     *ViewerContext    `json:"viewer_context"`
+}
+
+// UserProfile holds the free-form profile fields of a user
+type UserProfile struct {
+	Bio struct {
+		Text string `json:"text"`
+	} `json:"bio"`
+}
+
+// VerifiedAddresses lists the wallet addresses a user has verified
+type VerifiedAddresses struct {
+	EthAddresses []string `json:"eth_addresses"`
+	SolAddresses []string `json:"sol_addresses"`
+}
+
+// Experimental holds Neynar's experimental user fields
+type Experimental struct {
+	NeynarUserScore float64 `json:"neynar_user_score"`
+}
+
+// NeynarScore returns the Neynar user score, preferring the top-level score field
+// and falling back to experimental.neynar_user_score for older responses
+func (u *NeynarUser) NeynarScore() float64 {
+	if u.Score != nil {
+		return *u.Score
+	}
+	if u.Experimental != nil {
+		return u.Experimental.NeynarUserScore
+	}
+	return 0
 }
 
 // ViewerContext represents the viewer's relationship to a user
@@ -74,7 +125,7 @@ func (nc *NeynarClient) FetchBulkUsers(ctx context.Context, fids []int64, viewer
 	}
 
 	// Create HTTP request
-	url := fmt.Sprintf("%s/farcaster/user/bulk", neynarAPIBaseURL)
+	url := fmt.Sprintf("%s/farcaster/user/bulk", nc.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -130,7 +181,26 @@ func (nc *NeynarClient) FetchBulkUsers(ctx context.Context, fids []int64, viewer
 // FollowersResponse represents the response from Neynar's followers endpoint
 type FollowersResponse struct {
 	Result FollowersResult `json:"result"`
-	Next   *NextCursor     `json:"next,omitempty"`
+	// Users is the v2 response shape: a list of follow objects wrapping the follower
+	Users []FollowEdge `json:"users"`
+	Next  *NextCursor  `json:"next,omitempty"`
+}
+
+// FollowEdge is a single follow object of the v2 followers response
+type FollowEdge struct {
+	User FollowerUserInfo `json:"user"`
+}
+
+// FIDs returns the follower FIDs of the page regardless of the response shape
+func (r *FollowersResponse) FIDs() []int64 {
+	fids := make([]int64, 0, len(r.Result.Users)+len(r.Users))
+	for _, u := range r.Result.Users {
+		fids = append(fids, u.Fid)
+	}
+	for _, e := range r.Users {
+		fids = append(fids, e.User.Fid)
+	}
+	return fids
 }
 
 type FollowersResult struct {
@@ -152,7 +222,7 @@ func (nc *NeynarClient) FetchFollowers(ctx context.Context, targetFID string, li
 		limit = 100
 	}
 
-	url := fmt.Sprintf("%s/farcaster/followers", neynarAPIBaseURL)
+	url := fmt.Sprintf("%s/farcaster/followers", nc.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -197,6 +267,26 @@ func (nc *NeynarClient) FetchFollowers(ctx context.Context, targetFID string, li
 	return &result, nil
 }
 
+// FetchFollowContext fetches the target user (with the user as viewer) and the user's own profile in one call
+func (nc *NeynarClient) FetchFollowContext(ctx context.Context, userFID int64, targetFID int64) (target *NeynarUser, user *NeynarUser, err error) {
+	resp, err := nc.FetchBulkUsers(ctx, []int64{targetFID, userFID}, userFID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+	for i := range resp.Users {
+		if resp.Users[i].Fid == targetFID {
+			target = &resp.Users[i]
+		}
+		if resp.Users[i].Fid == userFID {
+			user = &resp.Users[i]
+		}
+	}
+	if target == nil || user == nil {
+		return nil, nil, ErrUserNotFound
+	}
+	return target, user, nil
+}
+
 // CheckFollowUsingNeynar checks if a user follows a target FID using Neynar API
 // userFID: the FID to check
 // targetFID: the FID we want to check if userFID follows
@@ -219,3 +309,158 @@ func (nc *NeynarClient) CheckFollowUsingNeynar(ctx context.Context, userFID int6
 	// Check if the viewer (userFID) is following the target
 	return user.ViewerContext.Following, nil
 }
+
+var (
+	// ErrUserNotFound is returned when Neynar has no user for the given lookup
+	ErrUserNotFound = errors.New("farcaster user not found")
+	// ErrCastNotFound is returned when Neynar has no cast for the given hash
+	ErrCastNotFound = errors.New("farcaster cast not found")
+)
+
+// getJSON performs an authenticated GET against the Neynar API and decodes the JSON body into out
+func (nc *NeynarClient) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	endpoint := nc.baseURL + path
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	req.Header.Set("accept", "application/json")
+	req.Header.Set("x-api-key", nc.apiKey)
+
+	resp, err := nc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// LookupUserByUsername fetches a user by their Farcaster username (fname)
+func (nc *NeynarClient) LookupUserByUsername(ctx context.Context, username string) (*NeynarUser, error) {
+	var result struct {
+		User *NeynarUser `json:"user"`
+	}
+	q := url.Values{}
+	q.Set("username", username)
+	if err := nc.getJSON(ctx, "/farcaster/user/by_username", q, &result); err != nil {
+		return nil, err
+	}
+	if result.User == nil {
+		return nil, ErrUserNotFound
+	}
+	return result.User, nil
+}
+
+// LookupUsersByAddress fetches the users that have verified the given ETH address
+// The response is keyed by the lowercased address
+func (nc *NeynarClient) LookupUsersByAddress(ctx context.Context, address string) ([]NeynarUser, error) {
+	result := map[string][]NeynarUser{}
+	q := url.Values{}
+	q.Set("addresses", address)
+	if err := nc.getJSON(ctx, "/farcaster/user/bulk-by-address", q, &result); err != nil {
+		return nil, err
+	}
+	users := result[strings.ToLower(address)]
+	if len(users) == 0 {
+		return nil, ErrUserNotFound
+	}
+	return users, nil
+}
+
+// FetchUser fetches a single user by FID
+func (nc *NeynarClient) FetchUser(ctx context.Context, fid int64) (*NeynarUser, error) {
+	resp, err := nc.FetchBulkUsers(ctx, []int64{fid}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Users) == 0 {
+		return nil, ErrUserNotFound
+	}
+	return &resp.Users[0], nil
+}
+
+// Cast represents a cast from Neynar API
+type Cast struct {
+	Hash          string             `json:"hash"`
+	ParentHash    string             `json:"parent_hash"`
+	Author        NeynarUser         `json:"author"`
+	Text          string             `json:"text"`
+	ViewerContext *CastViewerContext `json:"viewer_context"`
+	DirectReplies []Cast             `json:"direct_replies"`
+}
+
+// CastViewerContext represents the viewer's reactions to a cast
+type CastViewerContext struct {
+	Liked    bool `json:"liked"`
+	Recasted bool `json:"recasted"`
+}
+
+// FetchCast fetches a cast by hash with the reactions of viewerFid
+func (nc *NeynarClient) FetchCast(ctx context.Context, castHash string, viewerFid int64) (*Cast, error) {
+	var result struct {
+		Cast *Cast `json:"cast"`
+	}
+	q := url.Values{}
+	q.Set("identifier", castHash)
+	q.Set("type", "hash")
+	if viewerFid > 0 {
+		q.Set("viewer_fid", strconv.FormatInt(viewerFid, 10))
+	}
+	if err := nc.getJSON(ctx, "/farcaster/cast", q, &result); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrCastNotFound
+		}
+		return nil, err
+	}
+	if result.Cast == nil {
+		return nil, ErrCastNotFound
+	}
+	return result.Cast, nil
+}
+
+// FetchDirectReplies fetches one page of direct replies to a cast
+func (nc *NeynarClient) FetchDirectReplies(ctx context.Context, castHash string, cursor string) ([]Cast, string, error) {
+	var result struct {
+		Conversation struct {
+			Cast Cast `json:"cast"`
+		} `json:"conversation"`
+		Next *NextCursor `json:"next,omitempty"`
+	}
+	q := url.Values{}
+	q.Set("identifier", castHash)
+	q.Set("type", "hash")
+	q.Set("reply_depth", "1")
+	q.Set("limit", "50")
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	if err := nc.getJSON(ctx, "/farcaster/cast/conversation", q, &result); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, "", ErrCastNotFound
+		}
+		return nil, "", err
+	}
+	next := ""
+	if result.Next != nil {
+		next = result.Next.Cursor
+	}
+	return result.Conversation.Cast.DirectReplies, next, nil
+}
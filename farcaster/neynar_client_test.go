@@ -0,0 +1,59 @@
+package farcaster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNeynarClientBaseURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/farcaster/user/bulk" || r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("unexpected request %s key=%q", r.URL.Path, r.Header.Get("x-api-key"))
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"users":[{"fid":4242,"username":"alice","viewer_context":{"following":true}}]}`))
+	}))
+	defer srv.Close()
+	t.Setenv("NEYNAR_API_KEY", "test-key")
+	t.Setenv("NEYNAR_API_BASE_URL", srv.URL+"/v2/")
+
+	client, err := NewNeynarClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.FetchBulkUsers(context.Background(), []int64{4242}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Users) != 1 || res.Users[0].Username != "alice" {
+		t.Fatalf("users = %+v", res.Users)
+	}
+}
+
+func TestNeynarHasRepliedScanLimit(t *testing.T) {
+	pages := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every page holds a reply by someone else and a cursor to another page
+		pages++
+		fmt.Fprintf(w, `{"conversation":{"cast":{"direct_replies":[{"author":{"fid":%d}}]}},"next":{"cursor":"c%d"}}`, 1000+pages, pages)
+	}))
+	defer srv.Close()
+	t.Setenv("NEYNAR_API_KEY", "test-key")
+	t.Setenv("NEYNAR_API_BASE_URL", srv.URL)
+
+	client, err := NewNeynarClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.HasReplied(context.Background(), 4242, "0xa1b2c3d4e5f60718293a4b5c6d7e8f9001122334"); !errors.Is(err, ErrScanLimit) {
+		t.Fatalf("got %v, want ErrScanLimit", err)
+	}
+	if pages != maxReplyPages {
+		t.Fatalf("scanned %d pages, want %d", pages, maxReplyPages)
+	}
+}
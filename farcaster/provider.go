@@ -0,0 +1,141 @@
+package farcaster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Provider names selectable with FARCASTER_PROVIDER
+const (
+	ProviderNeynar = "neynar"
+	ProviderHub    = "hub"
+)
+
+var (
+	// ErrNotSupported is returned when the configured provider cannot answer a lookup
+	ErrNotSupported = errors.New("not supported by farcaster provider")
+	// ErrScanLimit is returned when a list is too long to scan to the end without finding a match
+	ErrScanLimit = errors.New("farcaster list too long to scan")
+)
+
+// Provider is a Farcaster data backend used by the follow and engagement checks
+type Provider interface {
+	// Name identifies the provider in results (see FollowResult.Source)
+	Name() string
+	// FetchUser fetches a user profile by FID
+	FetchUser(ctx context.Context, fid int64) (*NeynarUser, error)
+	// LookupUserByUsername fetches a user by username (fname)
+	LookupUserByUsername(ctx context.Context, username string) (*NeynarUser, error)
+	// LookupUsersByAddress fetches the users that verified an ETH address
+	LookupUsersByAddress(ctx context.Context, address string) ([]NeynarUser, error)
+	// FetchRelationship returns the user's relationship to the target, plus the user's profile when
+	// rules need it. ErrNotSupported is returned when the provider cannot evaluate the rules.
+	FetchRelationship(ctx context.Context, userFID int64, targetFID int64, rules EligibilityRules) (Relationship, *NeynarUser, error)
+	// HasReacted checks if the user liked (ActionLike) or recasted (ActionRecast) a cast
+	HasReacted(ctx context.Context, userFID int64, castHash string, action string) (bool, error)
+	// HasReplied checks if the user replied to a cast
+	HasReplied(ctx context.Context, userFID int64, castHash string) (bool, error)
+}
+
+// NewProvider creates the provider selected by FARCASTER_PROVIDER ("neynar" by default, or "hub")
+func NewProvider() (Provider, error) {
+	switch name := strings.ToLower(strings.TrimSpace(os.Getenv("FARCASTER_PROVIDER"))); name {
+	case "", ProviderNeynar:
+		client, err := NewNeynarClient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Neynar client: %w", err)
+		}
+		return client, nil
+	case ProviderHub:
+		client, err := NewHubClient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Hub client: %w", err)
+		}
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unknown FARCASTER_PROVIDER %q", name)
+	}
+}
+
+// Name implements Provider
+func (nc *NeynarClient) Name() string {
+	return ProviderNeynar
+}
+
+// FetchRelationship implements Provider using the viewer context of the target
+func (nc *NeynarClient) FetchRelationship(ctx context.Context, userFID int64, targetFID int64, rules EligibilityRules) (Relationship, *NeynarUser, error) {
+	var target, user *NeynarUser
+	if rules.NeedsProfile() {
+		// The user's own profile is fetched together with the target
+		var err error
+		target, user, err = nc.FetchFollowContext(ctx, userFID, targetFID)
+		if err != nil {
+			return Relationship{}, nil, err
+		}
+	} else {
+		resp, err := nc.FetchBulkUsers(ctx, []int64{targetFID}, userFID)
+		if err != nil {
+			return Relationship{}, nil, fmt.Errorf("failed to fetch user: %w", err)
+		}
+		if len(resp.Users) == 0 {
+			return Relationship{}, nil, ErrUserNotFound
+		}
+		target = &resp.Users[0]
+	}
+
+	// The viewer is the user, so the viewer context is from the user's point of view
+	var rel Relationship
+	if vc := target.ViewerContext; vc != nil {
+		rel = Relationship{
+			Following:  vc.Following,
+			FollowedBy: vc.FollowedBy,
+			Blocked:    vc.Blocked,
+			BlockedBy:  vc.BlockedBy,
+			Muted:      vc.Muted,
+			MutedBy:    vc.MutedBy,
+		}
+	}
+	return rel, user, nil
+}
+
+// HasReacted implements Provider using the viewer context of the cast
+func (nc *NeynarClient) HasReacted(ctx context.Context, userFID int64, castHash string, action string) (bool, error) {
+	cast, err := nc.FetchCast(ctx, castHash, userFID)
+	if err != nil {
+		return false, err
+	}
+	if cast.ViewerContext == nil {
+		return false, nil
+	}
+	switch action {
+	case ActionLike:
+		return cast.ViewerContext.Liked, nil
+	case ActionRecast:
+		return cast.ViewerContext.Recasted, nil
+	}
+	return false, fmt.Errorf("unsupported reaction %q", action)
+}
+
+// HasReplied implements Provider by scanning the direct replies of the cast
+func (nc *NeynarClient) HasReplied(ctx context.Context, userFID int64, castHash string) (bool, error) {
+	cursor := ""
+	for page := 0; page < maxReplyPages; page++ {
+		replies, next, err := nc.FetchDirectReplies(ctx, castHash, cursor)
+		if err != nil {
+			return false, err
+		}
+		for _, reply := range replies {
+			if reply.Author.Fid == userFID {
+				return true, nil
+			}
+		}
+		if next == "" {
+			return false, nil
+		}
+		cursor = next
+	}
+	return false, fmt.Errorf("%w: replies of %s have more than %d pages", ErrScanLimit, castHash, maxReplyPages)
+}
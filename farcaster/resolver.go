@@ -0,0 +1,191 @@
+package farcaster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultResolveCacheTTL = 24 * time.Hour
+
+// maxResolveCacheEntries bounds the resolve cache; expired entries are swept first when it is full
+var maxResolveCacheEntries = 10000
+
+var (
+	// ErrInvalidIdentifier is returned when an identifier is neither a FID, username, profile URL nor ETH address
+	ErrInvalidIdentifier = errors.New("invalid farcaster identifier")
+
+	ethAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	usernamePattern   = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+	// profileHosts are the web clients whose profile URLs look like https://host/<username>
+	profileHosts = map[string]bool{
+		"warpcast.com":      true,
+		"www.warpcast.com":  true,
+		"farcaster.xyz":     true,
+		"www.farcaster.xyz": true,
+	}
+)
+
+// resolveCacheEntry is a cached identifier -> FID mapping
+type resolveCacheEntry struct {
+	fid       int64
+	expiresAt time.Time
+}
+
+// resolveCache keeps resolved identifier mappings in memory so repeated checks don't hit Neynar
+var resolveCache = struct {
+	sync.RWMutex
+	entries map[string]resolveCacheEntry
+}{entries: map[string]resolveCacheEntry{}}
+
+// ResolveFID turns a user supplied identifier into a FID.
+// Accepted inputs:
+//   - a numeric FID ("1093215")
+//   - a username, with or without the leading @ ("@alice", "alice.eth")
+//   - a Warpcast / farcaster.xyz profile URL ("https://warpcast.com/alice")
+//   - a verified ETH address ("0xabc...")
+//
+// Non numeric inputs are resolved through the configured provider and cached (FARCASTER_RESOLVE_CACHE_TTL, default 24h).
+func ResolveFID(ctx context.Context, identifier string) (int64, error) {
+	kind, value, err := parseIdentifier(identifier)
+	if err != nil {
+		return 0, err
+	}
+	if kind == "fid" {
+		return strconv.ParseInt(value, 10, 64)
+	}
+
+	cacheKey := kind + ":" + value
+	if fid, ok := cachedFID(cacheKey); ok {
+		return fid, nil
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return 0, err
+	}
+
+	var fid int64
+	switch kind {
+	case "username":
+		user, err := provider.LookupUserByUsername(ctx, value)
+		if err != nil {
+			return 0, fmt.Errorf("resolve username %q: %w", value, err)
+		}
+		fid = user.Fid
+	case "address":
+		users, err := provider.LookupUsersByAddress(ctx, value)
+		if err != nil {
+			return 0, fmt.Errorf("resolve address %s: %w", value, err)
+		}
+		fid = users[0].Fid
+	}
+
+	log.Printf("[Neynar][DEBUG] ResolveFID %s=%s fid=%d", kind, value, fid)
+	storeFID(cacheKey, fid)
+	return fid, nil
+}
+
+// parseIdentifier classifies an identifier and returns its normalized value
+func parseIdentifier(identifier string) (kind string, value string, err error) {
+	s := strings.TrimSpace(identifier)
+	if s == "" {
+		return "", "", ErrInvalidIdentifier
+	}
+
+	if fid, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if fid <= 0 {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalidIdentifier, identifier)
+		}
+		return "fid", s, nil
+	}
+
+	if ethAddressPattern.MatchString(s) {
+		return "address", strings.ToLower(s), nil
+	}
+
+	if strings.Contains(s, "/") {
+		username, ok := usernameFromProfileURL(s)
+		if !ok {
+			return "", "", fmt.Errorf("%w: unsupported profile URL %q", ErrInvalidIdentifier, identifier)
+		}
+		s = username
+	}
+
+	s = strings.ToLower(strings.TrimPrefix(s, "@"))
+	if !usernamePattern.MatchString(s) {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidIdentifier, identifier)
+	}
+	return "username", s, nil
+}
+
+// usernameFromProfileURL extracts the username from a profile URL such as https://warpcast.com/alice
+func usernameFromProfileURL(raw string) (string, bool) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !profileHosts[strings.ToLower(u.Host)] {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) == 0 || segments[0] == "" {
+		return "", false
+	}
+	return segments[0], true
+}
+
+func cachedFID(key string) (int64, bool) {
+	resolveCache.Lock()
+	defer resolveCache.Unlock()
+	entry, ok := resolveCache.entries[key]
+	if !ok {
+		return 0, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(resolveCache.entries, key)
+		return 0, false
+	}
+	return entry.fid, true
+}
+
+func storeFID(key string, fid int64) {
+	ttl := defaultResolveCacheTTL
+	if v := os.Getenv("FARCASTER_RESOLVE_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			ttl = d
+		}
+	}
+	resolveCache.Lock()
+	defer resolveCache.Unlock()
+	if _, ok := resolveCache.entries[key]; !ok && len(resolveCache.entries) >= maxResolveCacheEntries {
+		evictResolveCache()
+	}
+	resolveCache.entries[key] = resolveCacheEntry{fid: fid, expiresAt: time.Now().Add(ttl)}
+}
+
+// evictResolveCache makes room for one entry: expired entries go first, then arbitrary live ones.
+// The caller holds the write lock.
+func evictResolveCache() {
+	now := time.Now()
+	for key, entry := range resolveCache.entries {
+		if now.After(entry.expiresAt) {
+			delete(resolveCache.entries, key)
+		}
+	}
+	for key := range resolveCache.entries {
+		if len(resolveCache.entries) < maxResolveCacheEntries {
+			return
+		}
+		delete(resolveCache.entries, key)
+	}
+}
@@ -0,0 +1,34 @@
+package farcaster
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestResolveCacheEviction(t *testing.T) {
+	defer func(max int) { maxResolveCacheEntries = max }(maxResolveCacheEntries)
+	maxResolveCacheEntries = 2
+	resolveCache.Lock()
+	resolveCache.entries = map[string]resolveCacheEntry{
+		"username:stale": {fid: 9, expiresAt: time.Now().Add(-time.Minute)},
+	}
+	resolveCache.Unlock()
+
+	if _, ok := cachedFID("username:stale"); ok {
+		t.Fatalf("expired entry answered from the cache")
+	}
+	for i := 0; i < 5; i++ {
+		storeFID("username:u"+strconv.Itoa(i), int64(i))
+	}
+	resolveCache.RLock()
+	size := len(resolveCache.entries)
+	_, stale := resolveCache.entries["username:stale"]
+	resolveCache.RUnlock()
+	if size > maxResolveCacheEntries || stale {
+		t.Fatalf("cache holds %d entries (stale kept: %v), want at most %d", size, stale, maxResolveCacheEntries)
+	}
+	if fid, ok := cachedFID("username:u4"); !ok || fid != 4 {
+		t.Fatalf("latest entry = %d, %v, want 4", fid, ok)
+	}
+}
@@ -0,0 +1,277 @@
+package farcaster
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// siwfHeaderSuffix ends the first line of an EIP-4361 message, after the domain
+const siwfHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+// siwfFIDResourcePrefix is the resource carrying the FID in a Sign In With Farcaster message
+const siwfFIDResourcePrefix = "farcaster://fid/"
+
+var (
+	// ErrInvalidSIWFMessage is returned when the message is malformed, expired, or for another domain or nonce
+	ErrInvalidSIWFMessage = errors.New("invalid SIWF message")
+	// ErrSIWFSignatureMismatch is returned when the signature was not made by the message address
+	ErrSIWFSignatureMismatch = errors.New("SIWF signature does not match address")
+	// ErrAddressNotOwned is returned when the signing address is neither the custody nor a verified address of the FID
+	ErrAddressNotOwned = errors.New("address is not owned by fid")
+)
+
+// SIWFMessage is a parsed EIP-4361 message carrying a farcaster://fid/<fid> resource
+type SIWFMessage struct {
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+	// FID is taken from the farcaster://fid/<fid> resource
+	FID int64
+}
+
+// SIWFExpectations are the values the server issued and expects back in the message
+type SIWFExpectations struct {
+	// Domain is the expected domain; it is required so a message signed for another site cannot be replayed
+	Domain string
+	Nonce  string
+	Now    time.Time
+}
+
+// ParseSIWFMessage parses the text of an EIP-4361 message
+func ParseSIWFMessage(text string) (*SIWFMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) < 2 || !strings.HasSuffix(lines[0], siwfHeaderSuffix) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidSIWFMessage)
+	}
+
+	m := &SIWFMessage{
+		Domain:  strings.TrimSuffix(lines[0], siwfHeaderSuffix),
+		Address: strings.TrimSpace(lines[1]),
+	}
+	if !ethAddressPattern.MatchString(m.Address) {
+		return nil, fmt.Errorf("%w: bad address %q", ErrInvalidSIWFMessage, m.Address)
+	}
+
+	// The optional statement sits between the address and the URI field
+	i := 2
+	var statement []string
+	for ; i < len(lines) && !strings.HasPrefix(lines[i], "URI: "); i++ {
+		if lines[i] != "" {
+			statement = append(statement, lines[i])
+		}
+	}
+	m.Statement = strings.Join(statement, "\n")
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+		if line == "Resources:" {
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "- ") {
+				i++
+				m.Resources = append(m.Resources, strings.TrimPrefix(lines[i], "- "))
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected line %q", ErrInvalidSIWFMessage, line)
+		}
+		var err error
+		switch key {
+		case "URI":
+			m.URI = value
+		case "Version":
+			m.Version = value
+		case "Chain ID":
+			m.ChainID, err = strconv.ParseInt(value, 10, 64)
+		case "Nonce":
+			m.Nonce = value
+		case "Issued At":
+			m.IssuedAt, err = time.Parse(time.RFC3339, value)
+		case "Expiration Time":
+			m.ExpirationTime, err = parseTimePtr(value)
+		case "Not Before":
+			m.NotBefore, err = parseTimePtr(value)
+		case "Request ID":
+			m.RequestID = value
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSIWFMessage, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad %s: %v", ErrInvalidSIWFMessage, key, err)
+		}
+	}
+
+	if m.URI == "" || m.Nonce == "" || m.IssuedAt.IsZero() {
+		return nil, fmt.Errorf("%w: URI, Nonce and Issued At are required", ErrInvalidSIWFMessage)
+	}
+	if m.Version != "1" {
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidSIWFMessage, m.Version)
+	}
+	for _, r := range m.Resources {
+		if strings.HasPrefix(r, siwfFIDResourcePrefix) {
+			fid, err := strconv.ParseInt(strings.TrimPrefix(r, siwfFIDResourcePrefix), 10, 64)
+			if err != nil || fid <= 0 {
+				return nil, fmt.Errorf("%w: bad fid resource %q", ErrInvalidSIWFMessage, r)
+			}
+			m.FID = fid
+		}
+	}
+	if m.FID == 0 {
+		return nil, fmt.Errorf("%w: missing %s<fid> resource", ErrInvalidSIWFMessage, siwfFIDResourcePrefix)
+	}
+	return m, nil
+}
+
+// parseTimePtr parses an optional RFC3339 timestamp
+func parseTimePtr(value string) (*time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Validate checks the domain, nonce and validity window of the message
+func (m *SIWFMessage) Validate(expect SIWFExpectations) error {
+	if expect.Domain == "" {
+		return fmt.Errorf("%w: no expected domain configured", ErrInvalidSIWFMessage)
+	}
+	if !strings.EqualFold(m.Domain, expect.Domain) {
+		return fmt.Errorf("%w: domain %q does not match %q", ErrInvalidSIWFMessage, m.Domain, expect.Domain)
+	}
+	if m.Nonce != expect.Nonce {
+		return fmt.Errorf("%w: nonce mismatch", ErrInvalidSIWFMessage)
+	}
+	if m.ExpirationTime != nil && !expect.Now.Before(*m.ExpirationTime) {
+		return fmt.Errorf("%w: expired at %s", ErrInvalidSIWFMessage, m.ExpirationTime.Format(time.RFC3339))
+	}
+	if m.NotBefore != nil && expect.Now.Before(*m.NotBefore) {
+		return fmt.Errorf("%w: not valid before %s", ErrInvalidSIWFMessage, m.NotBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// personalMessageHash is the EIP-191 hash signed by personal_sign
+func personalMessageHash(message []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	fmt.Fprintf(h, "\x19Ethereum Signed Message:\n%d", len(message))
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+// AddressFromPublicKey derives the lowercase 0x Ethereum address of a secp256k1 public key
+func AddressFromPublicKey(pub *secp256k1.PublicKey) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(pub.SerializeUncompressed()[1:])
+	return "0x" + hex.EncodeToString(h.Sum(nil)[12:])
+}
+
+// SignPersonalMessage signs message with EIP-191 personal_sign and returns the 65 byte r||s||v signature.
+// Together with secp256k1.GeneratePrivateKey it allows producing SIWF signatures offline.
+func SignPersonalMessage(key *secp256k1.PrivateKey, message []byte) []byte {
+	compact := ecdsa.SignCompact(key, personalMessageHash(message), false)
+	// compact is v||r||s with v = 27 + recovery id
+	return append(compact[1:], compact[0])
+}
+
+// RecoverAddress returns the lowercase address that produced an EIP-191 personal_sign signature (r||s||v)
+func RecoverAddress(message []byte, signature []byte) (string, error) {
+	if len(signature) != 65 {
+		return "", fmt.Errorf("%w: signature must be 65 bytes, got %d", ErrSIWFSignatureMismatch, len(signature))
+	}
+	v := signature[64]
+	if v < 27 {
+		v += 27
+	}
+	if v != 27 && v != 28 {
+		return "", fmt.Errorf("%w: bad recovery id %d", ErrSIWFSignatureMismatch, signature[64])
+	}
+
+	compact := make([]byte, 0, 65)
+	compact = append(compact, v)
+	compact = append(compact, signature[:64]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, personalMessageHash(message))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSIWFSignatureMismatch, err)
+	}
+	return AddressFromPublicKey(pub), nil
+}
+
+// VerifySIWFSignature checks, without any network call, that signatureHex over text was made by the message address
+func VerifySIWFSignature(text string, signatureHex string, m *SIWFMessage) error {
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signatureHex), "0x"))
+	if err != nil {
+		return fmt.Errorf("%w: signature is not hex", ErrSIWFSignatureMismatch)
+	}
+	addr, err := RecoverAddress([]byte(text), sig)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(addr, m.Address) {
+		return fmt.Errorf("%w: recovered %s, message says %s", ErrSIWFSignatureMismatch, addr, m.Address)
+	}
+	return nil
+}
+
+// OwnsAddress reports whether address is the custody address or a verified ETH address of user
+func OwnsAddress(user *NeynarUser, address string) bool {
+	if strings.EqualFold(user.CustodyAddress, address) {
+		return true
+	}
+	for _, a := range user.VerifiedAddresses.EthAddresses {
+		if strings.EqualFold(a, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifySIWF parses and verifies a Sign In With Farcaster message, then checks with the provider that the
+// signing address belongs to the FID. The hub provider only knows verified addresses, not custody addresses.
+func VerifySIWF(ctx context.Context, text string, signatureHex string, expect SIWFExpectations) (*SIWFMessage, error) {
+	m, err := ParseSIWFMessage(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Validate(expect); err != nil {
+		return nil, err
+	}
+	if err := VerifySIWFSignature(text, signatureHex, m); err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	user, err := provider.FetchUser(ctx, m.FID)
+	if err != nil {
+		return nil, err
+	}
+	if !OwnsAddress(user, m.Address) {
+		return nil, fmt.Errorf("%w: %s is not a custody or verified address of fid %d", ErrAddressNotOwned, m.Address, m.FID)
+	}
+	return m, nil
+}
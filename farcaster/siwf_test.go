@@ -0,0 +1,97 @@
+package farcaster
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+var siwfTestNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// siwfTestMessage builds an EIP-4361 Sign In With Farcaster message for address
+func siwfTestMessage(domain string, address string, nonce string, expires time.Time) string {
+	return strings.Join([]string{
+		domain + siwfHeaderSuffix,
+		address,
+		"",
+		"Farcaster Auth",
+		"",
+		"URI: https://" + domain + "/login",
+		"Version: 1",
+		"Chain ID: 10",
+		"Nonce: " + nonce,
+		"Issued At: " + siwfTestNow.Add(-time.Minute).Format(time.RFC3339),
+		"Expiration Time: " + expires.Format(time.RFC3339),
+		"Resources:",
+		"- farcaster://fid/1093215",
+	}, "\n")
+}
+
+// verifySIWFOffline runs every check VerifySIWF makes before asking the provider about the address
+func verifySIWFOffline(text string, signatureHex string, expect SIWFExpectations) error {
+	m, err := ParseSIWFMessage(text)
+	if err != nil {
+		return err
+	}
+	if err := m.Validate(expect); err != nil {
+		return err
+	}
+	return VerifySIWFSignature(text, signatureHex, m)
+}
+
+func TestVerifySIWFSignatureOffline(t *testing.T) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	other, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	address := AddressFromPublicKey(key.PubKey())
+	expect := SIWFExpectations{Domain: "app.example.com", Nonce: "abcdef1234567890", Now: siwfTestNow}
+
+	text := siwfTestMessage(expect.Domain, address, expect.Nonce, siwfTestNow.Add(time.Hour))
+	sign := func(k *secp256k1.PrivateKey, text string) string {
+		return "0x" + hex.EncodeToString(SignPersonalMessage(k, []byte(text)))
+	}
+
+	if err := verifySIWFOffline(text, sign(key, text), expect); err != nil {
+		t.Fatalf("valid message: %v", err)
+	}
+	if m, _ := ParseSIWFMessage(text); m.FID != 1093215 {
+		t.Fatalf("fid = %d, want 1093215", m.FID)
+	}
+
+	expired := siwfTestMessage(expect.Domain, address, expect.Nonce, siwfTestNow.Add(-time.Second))
+	tampered := strings.Replace(text, "farcaster://fid/1093215", "farcaster://fid/2", 1)
+	otherAddress := siwfTestMessage(expect.Domain, AddressFromPublicKey(other.PubKey()), expect.Nonce, siwfTestNow.Add(time.Hour))
+
+	cases := []struct {
+		name    string
+		text    string
+		sig     string
+		expect  SIWFExpectations
+		wantErr error
+	}{
+		{"wrong nonce", text, sign(key, text), SIWFExpectations{Domain: expect.Domain, Nonce: "other-nonce", Now: siwfTestNow}, ErrInvalidSIWFMessage},
+		{"wrong domain", text, sign(key, text), SIWFExpectations{Domain: "evil.example.com", Nonce: expect.Nonce, Now: siwfTestNow}, ErrInvalidSIWFMessage},
+		{"no expected domain", text, sign(key, text), SIWFExpectations{Nonce: expect.Nonce, Now: siwfTestNow}, ErrInvalidSIWFMessage},
+		{"expired", expired, sign(key, expired), expect, ErrInvalidSIWFMessage},
+		{"tampered", tampered, sign(key, text), expect, ErrSIWFSignatureMismatch},
+		{"wrong address", otherAddress, sign(key, otherAddress), expect, ErrSIWFSignatureMismatch},
+		{"bad signature", text, "0xzz", expect, ErrSIWFSignatureMismatch},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifySIWFOffline(tc.text, tc.sig, tc.expect)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
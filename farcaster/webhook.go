@@ -0,0 +1,171 @@
+package farcaster
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"checkingsocial/pkg/cache"
+)
+
+// Webhook event types handled by ProcessWebhookEvent
+const (
+	EventFollowCreated   = "follow.created"
+	EventFollowDeleted   = "follow.deleted"
+	EventReactionCreated = "reaction.created"
+	EventCastCreated     = "cast.created"
+)
+
+var (
+	// ErrInvalidSignature is returned when the webhook signature is missing or does not match
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidEvent is returned when the webhook body cannot be decoded
+	ErrInvalidEvent = errors.New("invalid webhook event")
+)
+
+// WebhookEvent is the envelope Neynar posts to webhook targets
+type WebhookEvent struct {
+	CreatedAt int64           `json:"created_at"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// webhookUser is the subset of a user object needed from webhook payloads
+type webhookUser struct {
+	Fid int64 `json:"fid"`
+}
+
+// followEventData is the data of follow.created / follow.deleted: user follows target_user
+type followEventData struct {
+	User       webhookUser `json:"user"`
+	TargetUser webhookUser `json:"target_user"`
+}
+
+// reactionEventData is the data of reaction.created
+type reactionEventData struct {
+	// ReactionType is 1/"like" or 2/"recast" depending on the API version
+	ReactionType json.RawMessage `json:"reaction_type"`
+	User         webhookUser     `json:"user"`
+	Cast         struct {
+		Hash string `json:"hash"`
+	} `json:"cast"`
+}
+
+// castEventData is the data of cast.created
+type castEventData struct {
+	Hash       string      `json:"hash"`
+	ParentHash string      `json:"parent_hash"`
+	Author     webhookUser `json:"author"`
+}
+
+// VerifyWebhookSignature checks the signature header against the raw body.
+// secrets is a comma-separated list so a secret can be rotated without downtime.
+func VerifyWebhookSignature(body []byte, signature string, secrets string) error {
+	sig, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil || len(sig) == 0 {
+		return ErrInvalidSignature
+	}
+	for _, secret := range strings.Split(secrets, ",") {
+		secret = strings.TrimSpace(secret)
+		if secret == "" {
+			continue
+		}
+		mac := hmac.New(sha512.New, []byte(secret))
+		mac.Write(body)
+		if hmac.Equal(sig, mac.Sum(nil)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// HandleWebhook verifies the body with NEYNAR_WEBHOOK_SECRET, decodes it and applies the event to Redis
+func HandleWebhook(ctx context.Context, body []byte, signature string) (*WebhookEvent, error) {
+	secrets := os.Getenv("NEYNAR_WEBHOOK_SECRET")
+	if secrets == "" {
+		return nil, fmt.Errorf("NEYNAR_WEBHOOK_SECRET environment variable not set")
+	}
+	if err := VerifyWebhookSignature(body, signature, secrets); err != nil {
+		return nil, err
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if err := ProcessWebhookEvent(ctx, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ProcessWebhookEvent updates the Redis state used by CheckFollowDetailed and CheckEngagement.
+// Unknown event types are ignored.
+func ProcessWebhookEvent(ctx context.Context, event *WebhookEvent) error {
+	if !cache.Enabled() {
+		return cache.ErrNotInitialized
+	}
+
+	switch event.Type {
+	case EventFollowCreated, EventFollowDeleted:
+		var data followEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		if data.User.Fid == 0 || data.TargetUser.Fid == 0 {
+			return fmt.Errorf("%w: follow event without user or target_user", ErrInvalidEvent)
+		}
+		target := strconv.FormatInt(data.TargetUser.Fid, 10)
+		if event.Type == EventFollowCreated {
+			return cache.AddFollowerFID(ctx, target, data.User.Fid)
+		}
+		return cache.RemoveFollowerFID(ctx, target, data.User.Fid)
+
+	case EventReactionCreated:
+		var data reactionEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		if data.User.Fid == 0 || data.Cast.Hash == "" {
+			return fmt.Errorf("%w: reaction event without user or cast", ErrInvalidEvent)
+		}
+		kind, err := reactionKind(data.ReactionType)
+		if err != nil {
+			return err
+		}
+		return cache.AddReaction(ctx, kind, strings.ToLower(data.Cast.Hash), data.User.Fid)
+
+	case EventCastCreated:
+		var data castEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		// Only replies matter for verification
+		if data.ParentHash == "" || data.Author.Fid == 0 {
+			return nil
+		}
+		return cache.AddReply(ctx, strings.ToLower(data.ParentHash), data.Author.Fid)
+	}
+
+	log.Printf("[Neynar][DEBUG] ignoring webhook event type=%s", event.Type)
+	return nil
+}
+
+// reactionKind maps the reaction_type of a webhook payload to the cache set name
+func reactionKind(raw json.RawMessage) (string, error) {
+	switch strings.Trim(strings.ToLower(string(raw)), `"`) {
+	case "1", "like":
+		return "likes", nil
+	case "2", "recast":
+		return "recasts", nil
+	}
+	return "", fmt.Errorf("%w: unknown reaction_type %s", ErrInvalidEvent, string(raw))
+}
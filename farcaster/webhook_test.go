@@ -0,0 +1,137 @@
+package farcaster
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"checkingsocial/pkg/cache"
+)
+
+const testWebhookSecret = "test-webhook-secret"
+
+// replayWebhook signs a recorded payload from testdata/webhooks with secret and feeds it to HandleWebhook
+func replayWebhook(t *testing.T, fixture string, secret string) (*WebhookEvent, error) {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "webhooks", fixture))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write(body)
+	return HandleWebhook(context.Background(), body, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestHandleWebhookReplay(t *testing.T) {
+	startFakeRedis(t)
+	// The second secret is the current one after a rotation
+	t.Setenv("NEYNAR_WEBHOOK_SECRET", "old-secret,"+testWebhookSecret)
+	ctx := context.Background()
+
+	followState := func() (bool, bool) {
+		t.Helper()
+		known, following, err := cache.FollowState(ctx, "3", 4242)
+		if err != nil {
+			t.Fatalf("FollowState: %v", err)
+		}
+		return known, following
+	}
+
+	if known, _ := followState(); known {
+		t.Fatal("follow state known before any webhook")
+	}
+	// Neynar retries deliveries, so every event is replayed twice
+	for range 2 {
+		event, err := replayWebhook(t, "follow_created.json", testWebhookSecret)
+		if err != nil {
+			t.Fatalf("follow.created: %v", err)
+		}
+		if event.Type != EventFollowCreated {
+			t.Fatalf("event type = %q", event.Type)
+		}
+	}
+	if known, following := followState(); !known || !following {
+		t.Fatalf("after follow.created: known=%v following=%v", known, following)
+	}
+	for range 2 {
+		if _, err := replayWebhook(t, "follow_deleted.json", testWebhookSecret); err != nil {
+			t.Fatalf("follow.deleted: %v", err)
+		}
+	}
+	if known, following := followState(); !known || following {
+		t.Fatalf("after follow.deleted: known=%v following=%v", known, following)
+	}
+
+	if _, err := replayWebhook(t, "reaction_created.json", testWebhookSecret); err != nil {
+		t.Fatalf("reaction.created: %v", err)
+	}
+	hash := "0xa1b2c3d4e5f60718293a4b5c6d7e8f9001122334"
+	if ok, err := cache.HasReaction(ctx, "recasts", hash, 4242); err != nil || !ok {
+		t.Fatalf("recast not recorded: %v %v", ok, err)
+	}
+	if ok, _ := cache.HasReaction(ctx, "likes", hash, 4242); ok {
+		t.Fatal("recast recorded as a like")
+	}
+
+	if _, err := replayWebhook(t, "cast_created.json", testWebhookSecret); err != nil {
+		t.Fatalf("cast.created: %v", err)
+	}
+	if ok, err := cache.HasReplied(ctx, hash, 4242); err != nil || !ok {
+		t.Fatalf("reply not recorded: %v %v", ok, err)
+	}
+
+	if _, err := replayWebhook(t, "user_updated.json", testWebhookSecret); err != nil {
+		t.Fatalf("unhandled event type should be ignored: %v", err)
+	}
+}
+
+func TestHandleWebhookRejectsBadSignature(t *testing.T) {
+	startFakeRedis(t)
+	t.Setenv("NEYNAR_WEBHOOK_SECRET", testWebhookSecret)
+
+	if _, err := replayWebhook(t, "follow_created.json", "attacker-secret"); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("got %v, want ErrInvalidSignature", err)
+	}
+	if known, _, _ := cache.FollowState(context.Background(), "3", 4242); known {
+		t.Fatal("unsigned event was applied")
+	}
+	if _, err := HandleWebhook(context.Background(), []byte(`{}`), ""); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("missing signature: got %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestHandleWebhookWithoutRedis(t *testing.T) {
+	t.Setenv("NEYNAR_WEBHOOK_SECRET", testWebhookSecret)
+	if _, err := replayWebhook(t, "follow_created.json", testWebhookSecret); !errors.Is(err, cache.ErrNotInitialized) {
+		t.Fatalf("got %v, want cache.ErrNotInitialized", err)
+	}
+}
+
+func TestCheckFollowAnsweredFromWebhookState(t *testing.T) {
+	startFakeRedis(t)
+	t.Setenv("NEYNAR_WEBHOOK_SECRET", testWebhookSecret)
+	for _, env := range []string{"FARCASTER_MIN_SCORE", "FARCASTER_MIN_FOLLOWERS", "FARCASTER_MIN_ACCOUNT_AGE",
+		"FARCASTER_REQUIRE_VERIFIED_ADDRESS", "FARCASTER_REQUIRE_POWER_BADGE", "FARCASTER_REJECT_MUTED", "FARCASTER_REJECT_BLOCKED"} {
+		t.Setenv(env, "")
+	}
+	if _, err := replayWebhook(t, "follow_created.json", testWebhookSecret); err != nil {
+		t.Fatalf("follow.created: %v", err)
+	}
+
+	res, err := CheckFollowDetailed("4242", "3", RelationFollow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Source != SourceCache || !res.Verified(RelationFollow) {
+		t.Fatalf("got source=%s following=%v, want a follow answered from the cache", res.Source, res.Following)
+	}
+	// Redis knows nothing about mutes and blocks, so there is no "ok" to report
+	if status := res.Status(); status != "" {
+		t.Errorf("status = %q, want empty for a cache answer", status)
+	}
+}
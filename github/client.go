@@ -0,0 +1,388 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultAPIBaseURL is the GitHub REST API base URL
+const defaultAPIBaseURL = "https://api.github.com"
+
+// maxPages bounds how many 100 item pages are scanned per check
+const maxPages = 30
+
+// Actions supported by CheckAction
+const (
+	ActionStar   = "star"
+	ActionFollow = "follow"
+	ActionFork   = "fork"
+)
+
+var (
+	// ErrInvalidLogin is returned when a user is not a valid GitHub login or profile URL
+	ErrInvalidLogin = errors.New("invalid github login")
+	// ErrInvalidTarget is returned when a target is not "owner/repo", a repository URL or a login
+	ErrInvalidTarget = errors.New("invalid github target")
+	// ErrUserNotFound is returned when the login does not exist
+	ErrUserNotFound = errors.New("github user not found")
+	// ErrTargetNotFound is returned when the repository (or the account to follow) does not exist or is private
+	ErrTargetNotFound = errors.New("github target not found")
+	// ErrRateLimited is returned when the API rate limit is exhausted
+	ErrRateLimited = errors.New("github rate limited")
+
+	loginPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})$`)
+	repoPattern  = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,100}$`)
+	nextLink     = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+	// errScanLimit is returned by scan when the list is longer than maxPages pages
+	errScanLimit = errors.New("list too long to scan")
+)
+
+// Client calls the GitHub REST API
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a GitHub client.
+// Config via ENV:
+//   - GITHUB_TOKEN (optional): token for 5000 req/h instead of 60 req/h per IP; no scopes needed for public data
+//   - GITHUB_API_BASE_URL (optional): override the API base URL (defaults to https://api.github.com)
+func NewClient() *Client {
+	baseURL := strings.TrimRight(os.Getenv("GITHUB_API_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = defaultAPIBaseURL
+	}
+	return &Client{
+		baseURL:    baseURL,
+		token:      os.Getenv("GITHUB_TOKEN"),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// get requests rawURL (absolute, or a path under the base URL) and returns the status, Link next URL and body
+func (c *Client) get(ctx context.Context, rawURL string) (int, string, []byte, error) {
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		rawURL = c.baseURL + rawURL
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	// Secondary rate limits answer 403 or 429 with Retry-After instead of an exhausted quota
+	retryAfter := resp.Header.Get("Retry-After")
+	if resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && (resp.Header.Get("X-RateLimit-Remaining") == "0" || retryAfter != "")) {
+		if delay := ratelimit.FromSeconds(retryAfter); delay > 0 {
+			return 0, "", nil, ratelimit.New(ErrRateLimited, delay, "retry after "+retryAfter+"s")
+		}
+		reset := resp.Header.Get("X-RateLimit-Reset")
+		sec, err := strconv.ParseInt(reset, 10, 64)
+		if err != nil {
+			return 0, "", nil, ratelimit.New(ErrRateLimited, 0, "")
+		}
+		return 0, "", nil, ratelimit.New(ErrRateLimited, ratelimit.FromUnix(reset), "resets at "+time.Unix(sec, 0).UTC().Format(time.RFC3339))
+	}
+
+	var next string
+	if m := nextLink.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		next = m[1]
+	}
+	return resp.StatusCode, next, body, nil
+}
+
+// statusError builds the error for an unexpected status
+func statusError(path string, status int, body []byte) error {
+	snippet := string(body)
+	if len(snippet) > 512 {
+		snippet = snippet[:512]
+	}
+	return fmt.Errorf("github %s failed with status %d: %s", path, status, snippet)
+}
+
+// User is the part of a GitHub user used by the checks
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// GetUser fetches a user or organization by login
+func (c *Client) GetUser(ctx context.Context, login string) (*User, error) {
+	status, _, body, err := c.get(ctx, "/users/"+url.PathEscape(login))
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusOK:
+		var user User
+		if err := json.Unmarshal(body, &user); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		return &user, nil
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, login)
+	}
+	return nil, statusError("users", status, body)
+}
+
+// IsFollowing reports whether login follows target (a user or organization)
+func (c *Client) IsFollowing(ctx context.Context, login string, target string) (bool, error) {
+	status, _, body, err := c.get(ctx, "/users/"+url.PathEscape(login)+"/following/"+url.PathEscape(target))
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusNoContent:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, statusError("following", status, body)
+}
+
+// scan pages through a list endpoint following Link rel="next" until match returns true.
+// It returns errScanLimit when the list has more than maxPages pages and none matched.
+func (c *Client) scan(ctx context.Context, path string, notFound error, match func(item json.RawMessage) bool) (bool, error) {
+	next := path
+	for page := 0; page < maxPages && next != ""; page++ {
+		status, link, body, err := c.get(ctx, next)
+		if err != nil {
+			return false, err
+		}
+		if status == http.StatusNotFound {
+			return false, notFound
+		}
+		if status != http.StatusOK {
+			return false, statusError(path, status, body)
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			return false, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		for _, item := range items {
+			if match(item) {
+				return true, nil
+			}
+		}
+		next = link
+	}
+	if next != "" {
+		return false, fmt.Errorf("%w: %s has more than %d pages", errScanLimit, path, maxPages)
+	}
+	return false, nil
+}
+
+// HasStarred reports whether login starred repo ("owner/name"), scanning the user's starred repositories
+func (c *Client) HasStarred(ctx context.Context, login string, repo string) (bool, error) {
+	path := "/users/" + url.PathEscape(login) + "/starred?per_page=100"
+	return c.scan(ctx, path, fmt.Errorf("%w: %s", ErrUserNotFound, login), func(item json.RawMessage) bool {
+		var r struct {
+			FullName string `json:"full_name"`
+		}
+		return json.Unmarshal(item, &r) == nil && strings.EqualFold(r.FullName, repo)
+	})
+}
+
+// Repo is the part of a GitHub repository used by the fork check
+type Repo struct {
+	FullName string `json:"full_name"`
+	Fork     bool   `json:"fork"`
+	Parent   *struct {
+		FullName string `json:"full_name"`
+	} `json:"parent"`
+}
+
+// GetRepo fetches a repository ("owner/name"); nil without error when it does not exist
+func (c *Client) GetRepo(ctx context.Context, repo string) (*Repo, error) {
+	status, _, body, err := c.get(ctx, "/repos/"+repo)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusOK:
+		var r Repo
+		if err := json.Unmarshal(body, &r); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		return &r, nil
+	case http.StatusNotFound:
+		return nil, nil
+	}
+	return nil, statusError("repos", status, body)
+}
+
+// HasForked reports whether login owns a fork of repo ("owner/name"). It looks up login's repository of
+// the same name first and only scans the forks of repo, newest first, for forks that were renamed.
+func (c *Client) HasForked(ctx context.Context, login string, repo string) (bool, error) {
+	name := repo[strings.Index(repo, "/")+1:]
+	own, err := c.GetRepo(ctx, login+"/"+name)
+	if err != nil {
+		return false, err
+	}
+	if own != nil && own.Fork && own.Parent != nil && strings.EqualFold(own.Parent.FullName, repo) {
+		return true, nil
+	}
+
+	path := "/repos/" + repo + "/forks?sort=newest&per_page=100"
+	return c.scan(ctx, path, fmt.Errorf("%w: %s", ErrTargetNotFound, repo), func(item json.RawMessage) bool {
+		var r struct {
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		}
+		return json.Unmarshal(item, &r) == nil && strings.EqualFold(r.Owner.Login, login)
+	})
+}
+
+// ParseLogin accepts a login, "@login" or a github.com profile URL
+func ParseLogin(login string) (string, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(login), "@")
+	if segments, ok := githubPath(s); ok {
+		if len(segments) != 1 {
+			return "", fmt.Errorf("%w: %q", ErrInvalidLogin, login)
+		}
+		s = segments[0]
+	}
+	if !loginPattern.MatchString(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLogin, login)
+	}
+	return strings.ToLower(s), nil
+}
+
+// ParseRepo accepts "owner/repo" or a github.com repository URL and returns "owner/repo"
+func ParseRepo(repo string) (string, error) {
+	s := strings.TrimSpace(repo)
+	segments, isURL := githubPath(s)
+	if !isURL {
+		segments = strings.Split(s, "/")
+	}
+	// URLs may point below the repository (/tree/main, /issues...)
+	if len(segments) < 2 || (!isURL && len(segments) != 2) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, repo)
+	}
+	owner, name := segments[0], strings.TrimSuffix(segments[1], ".git")
+	if !loginPattern.MatchString(owner) || !repoPattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, repo)
+	}
+	return owner + "/" + name, nil
+}
+
+// githubPath returns the path segments of s when it is a github.com URL
+func githubPath(s string) ([]string, bool) {
+	if !strings.Contains(strings.ToLower(s), "github.com/") {
+		return nil, false
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || !strings.HasSuffix(strings.ToLower(u.Host), "github.com") {
+		return nil, false
+	}
+	return strings.Split(strings.Trim(u.Path, "/"), "/"), true
+}
+
+// ActionResult is the outcome of CheckAction
+type ActionResult struct {
+	Login  string
+	Target string
+	Done   bool
+	// Verifiable is false when the list to check was too long to scan; Done is then false
+	Verifiable bool
+	// Reason explains why the check was not verifiable
+	Reason string
+}
+
+// CheckAction checks star, follow or fork for a GitHub login.
+// target is "owner/repo" for star and fork (empty uses GITHUB_REPO) and a user or organization for follow
+// (empty uses GITHUB_ORG).
+func CheckAction(login string, action string, target string) (*ActionResult, error) {
+	_ = godotenv.Load()
+
+	login, err := ParseLogin(login)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	client := NewClient()
+	// A 404 on following or starred lists cannot tell "no" from "no such user", so the user is looked up first
+	if _, err := client.GetUser(ctx, login); err != nil {
+		return nil, err
+	}
+
+	res := &ActionResult{Login: login, Verifiable: true}
+	switch action {
+	case ActionFollow:
+		if strings.TrimSpace(target) == "" {
+			target = os.Getenv("GITHUB_ORG")
+		}
+		if res.Target, err = ParseLogin(target); err != nil {
+			return nil, fmt.Errorf("%w: follow target %q", ErrInvalidTarget, target)
+		}
+		// Following a missing account would read as a plain "no"
+		if _, err := client.GetUser(ctx, res.Target); errors.Is(err, ErrUserNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrTargetNotFound, res.Target)
+		} else if err != nil {
+			return nil, err
+		}
+		res.Done, err = client.IsFollowing(ctx, login, res.Target)
+	case ActionStar, ActionFork:
+		if strings.TrimSpace(target) == "" {
+			target = os.Getenv("GITHUB_REPO")
+		}
+		if res.Target, err = ParseRepo(target); err != nil {
+			return nil, err
+		}
+		if action == ActionStar {
+			res.Done, err = client.HasStarred(ctx, login, res.Target)
+		} else {
+			res.Done, err = client.HasForked(ctx, login, res.Target)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported github action %q", action)
+	}
+	if errors.Is(err, errScanLimit) {
+		res.Verifiable, res.Reason = false, err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[GitHub][DEBUG] CheckAction login=%s action=%s target=%s done=%v verifiable=%v", login, action, res.Target, res.Done, res.Verifiable)
+	return res, nil
+}
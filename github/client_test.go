@@ -0,0 +1,136 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// fakeGitHub serves the users, starred, repos and forks endpoints used by CheckAction.
+// alice stars nothing in an endless starred list, bob stars acme/widget on page 2; alice owns a fork of
+// acme/widget under the same name, bob a renamed one and carol an unrelated repository called widget.
+func fakeGitHub(t *testing.T) {
+	t.Helper()
+	var srv *httptest.Server
+	page := func(r *http.Request) int {
+		p, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		return max(p, 1)
+	}
+	writePage := func(w http.ResponseWriter, r *http.Request, items []string, hasNext bool) {
+		if hasNext {
+			next := fmt.Sprintf("%s%s?per_page=100&page=%d", srv.URL, r.URL.Path, page(r)+1)
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+		}
+		fmt.Fprint(w, "["+strings.Join(items, ",")+"]")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{login}", func(w http.ResponseWriter, r *http.Request) {
+		switch login := r.PathValue("login"); login {
+		case "alice", "bob", "carol", "acme":
+			fmt.Fprintf(w, `{"id":1,"login":%q,"type":"User"}`, login)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("GET /users/{login}/starred", func(w http.ResponseWriter, r *http.Request) {
+		items := []string{`{"full_name":"other/repo"}`}
+		if r.PathValue("login") == "bob" && page(r) == 2 {
+			items = append(items, `{"full_name":"Acme/Widget"}`)
+		}
+		writePage(w, r, items, true)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{name}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("owner") + "/" + r.PathValue("name") {
+		case "alice/widget":
+			fmt.Fprint(w, `{"full_name":"alice/widget","fork":true,"parent":{"full_name":"acme/widget"}}`)
+		case "carol/widget":
+			fmt.Fprint(w, `{"full_name":"carol/widget","fork":false}`)
+		case "acme/widget":
+			fmt.Fprint(w, `{"full_name":"acme/widget","fork":false}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("GET /repos/acme/widget/forks", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, []string{`{"owner":{"login":"bob"}}`}, false)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Setenv("GITHUB_API_BASE_URL", srv.URL)
+	t.Setenv("GITHUB_TOKEN", "")
+}
+
+func TestCheckAction(t *testing.T) {
+	fakeGitHub(t)
+
+	tests := []struct {
+		name       string
+		login      string
+		action     string
+		target     string
+		done       bool
+		verifiable bool
+	}{
+		{"starred on a later page", "bob", ActionStar, "acme/widget", true, true},
+		{"starred list longer than the scan cap", "alice", ActionStar, "acme/widget", false, false},
+		{"fork under the same name", "alice", ActionFork, "acme/widget", true, true},
+		{"renamed fork found in the forks list", "bob", ActionFork, "https://github.com/acme/widget", true, true},
+		{"same name but not a fork", "carol", ActionFork, "acme/widget", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := CheckAction(tt.login, tt.action, tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Done != tt.done || res.Verifiable != tt.verifiable {
+				t.Fatalf("got done=%v verifiable=%v (%s), want done=%v verifiable=%v", res.Done, res.Verifiable, res.Reason, tt.done, tt.verifiable)
+			}
+			if !res.Verifiable && res.Reason == "" {
+				t.Fatal("unverifiable result without a reason")
+			}
+		})
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(90*time.Second).Unix(), 10)
+	tests := []struct {
+		name     string
+		status   int
+		header   map[string]string
+		min, max time.Duration
+	}{
+		{"primary quota", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}, 80 * time.Second, 90 * time.Second},
+		{"secondary 403", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "4000", "Retry-After": "30"}, 30 * time.Second, 30 * time.Second},
+		{"secondary 429", http.StatusTooManyRequests, map[string]string{"Retry-After": "60"}, time.Minute, time.Minute},
+		{"429 without hint", http.StatusTooManyRequests, nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+			t.Setenv("GITHUB_API_BASE_URL", srv.URL)
+
+			_, err := NewClient().GetUser(context.Background(), "alice")
+			var rl *ratelimit.Error
+			if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) || rl.RetryAfter < tt.min || rl.RetryAfter > tt.max {
+				t.Fatalf("got %v, want ErrRateLimited retrying after %s-%s", err, tt.min, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/campaign"
+	"checkingsocial/internal/handler"
+	"checkingsocial/internal/identity"
+	"checkingsocial/internal/ledger"
+	"checkingsocial/internal/reverify"
+	"checkingsocial/internal/service"
+	"checkingsocial/pkg/cache"
+	"checkingsocial/pkg/cronjob"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Setup tạo các service với đầy đủ chuỗi decorator (OAuth, ledger, kiểm tra lại, identity, chứng minh quyền sở
+// hữu) và đăng ký route của chúng lên router. Mọi binary phục vụ kiểm tra hành động phải đi qua Setup để không
+// route nào bỏ sót một lớp kiểm tra. Gọi InitRedis trước Setup nếu dùng Redis; cleanup đóng ledger và dừng cron.
+func Setup(router *gin.Engine) (cleanup func(), err error) {
+	var closers []func()
+	cleanup = func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+	defer func() {
+		if err != nil {
+			cleanup()
+		}
+	}()
+
+	socialCheckerService := service.NewSocialChecker()
+
+	// Auth store holds sessions, ownership challenges and the OAuth tokens users granted
+	authCfg, err := service.LoadAuthConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load auth config: %w", err)
+	}
+	if !authCfg.SIWFEnabled() {
+		log.Printf("[App][WARN] SIWF_DOMAIN not set: Sign In With Farcaster is disabled and Farcaster follow checks accept unproven FIDs")
+	}
+	if !authCfg.RequireProvenX {
+		log.Printf("[App][WARN] REQUIRE_PROVEN_X is off: X follow checks accept unproven handles")
+	}
+	var authStore auth.Store = auth.NewMemoryStore()
+	if cache.Enabled() {
+		authStore = auth.NewRedisStore(cache.GetRedisClient())
+	}
+	socialCheckerService = service.NewOAuthChecker(socialCheckerService, authStore)
+
+	// Identity links map per-platform accounts to internal user IDs
+	var identityStore identity.Store = identity.NewMemoryStore()
+	if cache.Enabled() {
+		identityStore = identity.NewRedisStore(cache.GetRedisClient())
+	}
+
+	// Ledger: every social action check is persisted
+	ledgerCfg, err := service.LoadLedgerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load ledger config: %w", err)
+	}
+	var ledgerRepo ledger.Repository
+	if ledgerCfg.Backend == service.LedgerBackendRedis {
+		if !cache.Enabled() {
+			return nil, errors.New("LEDGER_BACKEND=redis requires REDIS_ADDR")
+		}
+		ledgerRepo = ledger.NewRedisRepository(cache.GetRedisClient())
+	} else {
+		sqliteRepo, err := ledger.NewSQLiteRepository(ledgerCfg.SQLitePath)
+		if err != nil {
+			return nil, fmt.Errorf("open ledger: %w", err)
+		}
+		ledgerRepo = sqliteRepo
+	}
+	closers = append(closers, func() { ledgerRepo.Close() })
+	socialCheckerService = service.NewLedgerChecker(socialCheckerService, ledgerRepo, identityStore)
+
+	// Re-verification of successful checks (unfollow detection)
+	reverifyCfg, err := service.LoadReverifyConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load re-verification config: %w", err)
+	}
+	if reverifyCfg.Enabled {
+		var store reverify.Store = reverify.NewMemoryStore()
+		if cache.Enabled() {
+			store = reverify.NewRedisStore(cache.GetRedisClient())
+		}
+		notifiers := reverify.MultiNotifier{reverify.LogNotifier{}}
+		if reverifyCfg.WebhookURL != "" {
+			notifiers = append(notifiers, reverify.NewWebhookNotifier(reverifyCfg.WebhookURL))
+		}
+
+		reverifier := service.NewReverifier(socialCheckerService, store, notifiers, reverifyCfg.Window)
+		if err := cronjob.InitCronScheduler(reverifyCfg.Schedule, func() {
+			if _, err := reverifier.Run(context.Background()); err != nil {
+				log.Printf("Re-verification failed: %v", err)
+			}
+		}); err != nil {
+			return nil, fmt.Errorf("schedule re-verification: %w", err)
+		}
+		closers = append(closers, cronjob.StopCronScheduler)
+
+		socialCheckerService = service.NewTrackingChecker(socialCheckerService, store)
+	}
+
+	// Identity linking lets callers send an internal user_id instead of a per-platform iduser
+	socialCheckerService = service.NewIdentityChecker(socialCheckerService, identityStore)
+
+	// Sessions prove account ownership (SIWF for Farcaster, tweet-a-code for X) for follow checks
+	socialCheckerService = service.NewProofChecker(socialCheckerService, authStore, authCfg)
+
+	socialHandler := handler.NewSocialHandler(socialCheckerService)
+	identityHandler := handler.NewIdentityHandler(service.NewIdentityService(identityStore, authStore))
+	authHandler := handler.NewAuthHandler(service.NewAuthService(authCfg, authStore, identityStore))
+	webhookHandler := handler.NewWebhookHandler(service.NewWebhookProcessor())
+	ledgerHandler := handler.NewLedgerHandler(service.NewLedgerService(ledgerRepo, identityStore, authStore))
+
+	// Campaigns are kept in Redis when available so they survive restarts
+	var campaignStore campaign.Store = campaign.NewMemoryStore()
+	if cache.Enabled() {
+		campaignStore = campaign.NewRedisStore(cache.GetRedisClient())
+	}
+	campaignHandler := handler.NewCampaignHandler(service.NewCampaignService(socialCheckerService, campaignStore))
+
+	// Register routes
+	socialHandler.RegisterRoutes(router)
+	webhookHandler.RegisterRoutes(router)
+	ledgerHandler.RegisterRoutes(router)
+	campaignHandler.RegisterRoutes(router)
+	identityHandler.RegisterRoutes(router)
+	authHandler.RegisterRoutes(router)
+
+	return cleanup, nil
+}
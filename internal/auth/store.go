@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"checkingsocial/internal/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrSessionNotFound được trả về khi token không tồn tại hoặc đã hết hạn.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrChallengeNotFound được trả về khi mã challenge không tồn tại hoặc đã hết hạn.
+	ErrChallengeNotFound = errors.New("challenge not found")
+	// ErrTokenNotFound được trả về khi tài khoản chưa cấp quyền OAuth.
+	ErrTokenNotFound = errors.New("oauth token not found")
+)
+
+// Challenge là một mã mà người dùng phải đăng lên tài khoản Account để chứng minh quyền sở hữu.
+type Challenge struct {
+	Code     string `json:"code"`
+	Platform string `json:"platform"`
+	Account  string `json:"account"`
+	// UserID (tuỳ chọn) được liên kết với Account khi challenge thành công
+	UserID string `json:"user_id,omitempty"`
+	// SessionToken là phiên đã yêu cầu challenge; rỗng nếu người gọi chưa đăng nhập
+	SessionToken string `json:"session_token,omitempty"`
+	// SecretHash là SHA-256 (hex) của secret chỉ người yêu cầu challenge biết; rỗng với state OAuth
+	SecretHash string    `json:"secret_hash,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// OAuthToken là token OAuth mà chủ tài khoản Account đã cấp, dùng cho các kiểm tra cần quyền của người dùng.
+type OAuthToken struct {
+	Platform     string    `json:"platform"`
+	Account      string    `json:"account"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Store định nghĩa interface lưu nonce dùng một lần, phiên đăng nhập, challenge và token OAuth.
+type Store interface {
+	// SaveNonce lưu nonce với thời hạn ttl.
+	SaveNonce(ctx context.Context, nonce string, ttl time.Duration) error
+	// ConsumeNonce xoá nonce, trả về false nếu nonce không tồn tại, đã dùng hoặc đã hết hạn.
+	ConsumeNonce(ctx context.Context, nonce string) (bool, error)
+	// SaveSession thêm mới hoặc ghi đè phiên, hết hạn tại s.ExpiresAt.
+	SaveSession(ctx context.Context, s model.Session) error
+	// GetSession trả về phiên theo token, hoặc ErrSessionNotFound.
+	GetSession(ctx context.Context, token string) (model.Session, error)
+	// SaveChallenge lưu challenge, hết hạn tại c.ExpiresAt.
+	SaveChallenge(ctx context.Context, c Challenge) error
+	// GetChallenge trả về challenge theo mã, hoặc ErrChallengeNotFound.
+	GetChallenge(ctx context.Context, code string) (Challenge, error)
+	// DeleteChallenge xoá challenge đã dùng.
+	DeleteChallenge(ctx context.Context, code string) error
+	// SaveOAuthToken thêm mới hoặc ghi đè token của (t.Platform, t.Account). Token không hết hạn trong
+	// store vì refresh token vẫn dùng được sau khi access token hết hạn.
+	SaveOAuthToken(ctx context.Context, t OAuthToken) error
+	// GetOAuthToken trả về token của tài khoản, hoặc ErrTokenNotFound.
+	GetOAuthToken(ctx context.Context, platform string, account string) (OAuthToken, error)
+	// DeleteOAuthToken xoá token (ví dụ khi người dùng đã thu hồi quyền).
+	DeleteOAuthToken(ctx context.Context, platform string, account string) error
+}
+
+// MemoryStore lưu nonce và phiên trong bộ nhớ (dùng khi không có Redis).
+type MemoryStore struct {
+	mu         sync.Mutex
+	nonces     map[string]time.Time
+	sessions   map[string]model.Session
+	challenges map[string]Challenge
+	tokens     map[string]OAuthToken
+}
+
+// NewMemoryStore tạo một MemoryStore rỗng.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nonces:     map[string]time.Time{},
+		sessions:   map[string]model.Session{},
+		challenges: map[string]Challenge{},
+		tokens:     map[string]OAuthToken{},
+	}
+}
+
+// SaveNonce implements Store.
+func (s *MemoryStore) SaveNonce(ctx context.Context, nonce string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	// Dọn các nonce hết hạn để map không lớn dần
+	for n, exp := range s.nonces {
+		if now.After(exp) {
+			delete(s.nonces, n)
+		}
+	}
+	s.nonces[nonce] = now.Add(ttl)
+	return nil
+}
+
+// ConsumeNonce implements Store.
+func (s *MemoryStore) ConsumeNonce(ctx context.Context, nonce string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	exp, ok := s.nonces[nonce]
+	delete(s.nonces, nonce)
+	return ok && time.Now().Before(exp), nil
+}
+
+// SaveSession implements Store.
+func (s *MemoryStore) SaveSession(ctx context.Context, session model.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.Token] = session
+	return nil
+}
+
+// GetSession implements Store.
+func (s *MemoryStore) GetSession(ctx context.Context, token string) (model.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[token]
+	if !ok {
+		return model.Session{}, ErrSessionNotFound
+	}
+	if time.Now().After(session.ExpiresAt) {
+		delete(s.sessions, token)
+		return model.Session{}, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// SaveChallenge implements Store.
+func (s *MemoryStore) SaveChallenge(ctx context.Context, c Challenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for code, old := range s.challenges {
+		if now.After(old.ExpiresAt) {
+			delete(s.challenges, code)
+		}
+	}
+	s.challenges[c.Code] = c
+	return nil
+}
+
+// GetChallenge implements Store.
+func (s *MemoryStore) GetChallenge(ctx context.Context, code string) (Challenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.challenges[code]
+	if !ok || time.Now().After(c.ExpiresAt) {
+		return Challenge{}, ErrChallengeNotFound
+	}
+	return c, nil
+}
+
+// DeleteChallenge implements Store.
+func (s *MemoryStore) DeleteChallenge(ctx context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.challenges, code)
+	return nil
+}
+
+// tokenKey là khoá của token trong MemoryStore.
+func tokenKey(platform string, account string) string {
+	return platform + ":" + account
+}
+
+// SaveOAuthToken implements Store.
+func (s *MemoryStore) SaveOAuthToken(ctx context.Context, t OAuthToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[tokenKey(t.Platform, t.Account)] = t
+	return nil
+}
+
+// GetOAuthToken implements Store.
+func (s *MemoryStore) GetOAuthToken(ctx context.Context, platform string, account string) (OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[tokenKey(platform, account)]
+	if !ok {
+		return OAuthToken{}, ErrTokenNotFound
+	}
+	return t, nil
+}
+
+// DeleteOAuthToken implements Store.
+func (s *MemoryStore) DeleteOAuthToken(ctx context.Context, platform string, account string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, tokenKey(platform, account))
+	return nil
+}
+
+// Redis keys của RedisStore.
+const (
+	redisNonceKey     = "auth:nonce:%s"
+	redisSessionKey   = "auth:session:%s"
+	redisChallengeKey = "auth:challenge:%s"
+	redisTokenKey     = "auth:oauth:%s:%s"
+)
+
+// RedisStore lưu nonce và phiên trong Redis, dùng TTL của Redis để hết hạn.
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore tạo RedisStore từ một Redis client đã kết nối.
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+// SaveNonce implements Store.
+func (s *RedisStore) SaveNonce(ctx context.Context, nonce string, ttl time.Duration) error {
+	return s.rdb.Set(ctx, fmt.Sprintf(redisNonceKey, nonce), 1, ttl).Err()
+}
+
+// ConsumeNonce implements Store. DEL là nguyên tử nên mỗi nonce chỉ được dùng một lần.
+func (s *RedisStore) ConsumeNonce(ctx context.Context, nonce string) (bool, error) {
+	n, err := s.rdb.Del(ctx, fmt.Sprintf(redisNonceKey, nonce)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// SaveSession implements Store.
+func (s *RedisStore) SaveSession(ctx context.Context, session model.Session) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return s.rdb.Set(ctx, fmt.Sprintf(redisSessionKey, session.Token), data, ttl).Err()
+}
+
+// GetSession implements Store.
+func (s *RedisStore) GetSession(ctx context.Context, token string) (model.Session, error) {
+	data, err := s.rdb.Get(ctx, fmt.Sprintf(redisSessionKey, token)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return model.Session{}, ErrSessionNotFound
+	}
+	if err != nil {
+		return model.Session{}, err
+	}
+	var session model.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return model.Session{}, fmt.Errorf("decode session: %w", err)
+	}
+	return session, nil
+}
+
+// SaveChallenge implements Store.
+func (s *RedisStore) SaveChallenge(ctx context.Context, c Challenge) error {
+	ttl := time.Until(c.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return s.rdb.Set(ctx, fmt.Sprintf(redisChallengeKey, c.Code), data, ttl).Err()
+}
+
+// GetChallenge implements Store.
+func (s *RedisStore) GetChallenge(ctx context.Context, code string) (Challenge, error) {
+	data, err := s.rdb.Get(ctx, fmt.Sprintf(redisChallengeKey, code)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Challenge{}, ErrChallengeNotFound
+	}
+	if err != nil {
+		return Challenge{}, err
+	}
+	var c Challenge
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Challenge{}, fmt.Errorf("decode challenge: %w", err)
+	}
+	return c, nil
+}
+
+// DeleteChallenge implements Store.
+func (s *RedisStore) DeleteChallenge(ctx context.Context, code string) error {
+	return s.rdb.Del(ctx, fmt.Sprintf(redisChallengeKey, code)).Err()
+}
+
+// SaveOAuthToken implements Store.
+func (s *RedisStore) SaveOAuthToken(ctx context.Context, t OAuthToken) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return s.rdb.Set(ctx, fmt.Sprintf(redisTokenKey, t.Platform, t.Account), data, 0).Err()
+}
+
+// GetOAuthToken implements Store.
+func (s *RedisStore) GetOAuthToken(ctx context.Context, platform string, account string) (OAuthToken, error) {
+	data, err := s.rdb.Get(ctx, fmt.Sprintf(redisTokenKey, platform, account)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return OAuthToken{}, ErrTokenNotFound
+	}
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	var t OAuthToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return OAuthToken{}, fmt.Errorf("decode oauth token: %w", err)
+	}
+	return t, nil
+}
+
+// DeleteOAuthToken implements Store.
+func (s *RedisStore) DeleteOAuthToken(ctx context.Context, platform string, account string) error {
+	return s.rdb.Del(ctx, fmt.Sprintf(redisTokenKey, platform, account)).Err()
+}
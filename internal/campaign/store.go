@@ -0,0 +1,138 @@
+package campaign
+
+import (
+	"checkingsocial/internal/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrNotFound được trả về khi không tìm thấy campaign.
+	ErrNotFound = errors.New("campaign not found")
+	// ErrConflict được trả về khi đã có campaign cùng ID.
+	ErrConflict = errors.New("campaign already exists")
+)
+
+// Store định nghĩa interface lưu campaign.
+type Store interface {
+	// Create thêm mới campaign, hoặc trả về ErrConflict nếu ID đã tồn tại.
+	Create(ctx context.Context, c model.Campaign) error
+	// Get trả về campaign theo ID, hoặc ErrNotFound.
+	Get(ctx context.Context, id string) (model.Campaign, error)
+	// List trả về mọi campaign, sắp xếp theo ID.
+	List(ctx context.Context) ([]model.Campaign, error)
+}
+
+// MemoryStore lưu campaign trong bộ nhớ (dùng khi không có Redis).
+type MemoryStore struct {
+	mu    sync.RWMutex
+	items map[string]model.Campaign
+}
+
+// NewMemoryStore tạo một MemoryStore rỗng.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]model.Campaign{}}
+}
+
+// Create implements Store.
+func (s *MemoryStore) Create(ctx context.Context, c model.Campaign) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.items[c.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrConflict, c.ID)
+	}
+	s.items[c.ID] = c
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(ctx context.Context, id string) (model.Campaign, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.items[id]
+	if !ok {
+		return model.Campaign{}, ErrNotFound
+	}
+	return c, nil
+}
+
+// List implements Store.
+func (s *MemoryStore) List(ctx context.Context) ([]model.Campaign, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]model.Campaign, 0, len(s.items))
+	for _, c := range s.items {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// redisKey là hash id -> JSON của RedisStore.
+const redisKey = "campaign:campaigns"
+
+// RedisStore lưu campaign trong Redis để giữ được qua các lần restart.
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore tạo RedisStore từ một Redis client đã kết nối.
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+// Create implements Store.
+func (s *RedisStore) Create(ctx context.Context, c model.Campaign) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	created, err := s.rdb.HSetNX(ctx, redisKey, c.ID, data).Result()
+	if err != nil {
+		return err
+	}
+	if !created {
+		return fmt.Errorf("%w: %s", ErrConflict, c.ID)
+	}
+	return nil
+}
+
+// Get implements Store.
+func (s *RedisStore) Get(ctx context.Context, id string) (model.Campaign, error) {
+	data, err := s.rdb.HGet(ctx, redisKey, id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return model.Campaign{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Campaign{}, err
+	}
+	var c model.Campaign
+	if err := json.Unmarshal(data, &c); err != nil {
+		return model.Campaign{}, fmt.Errorf("decode campaign %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// List implements Store.
+func (s *RedisStore) List(ctx context.Context) ([]model.Campaign, error) {
+	values, err := s.rdb.HGetAll(ctx, redisKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.Campaign, 0, len(values))
+	for id, raw := range values {
+		var c model.Campaign
+		if err := json.Unmarshal([]byte(raw), &c); err != nil {
+			return nil, fmt.Errorf("decode campaign %s: %w", id, err)
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"checkingsocial/internal/model"
+	"checkingsocial/internal/service"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthHandler xử lý đăng nhập bằng chứng minh quyền sở hữu tài khoản.
+type AuthHandler struct {
+	service service.AuthService
+}
+
+// NewAuthHandler tạo một instance mới của AuthHandler.
+func NewAuthHandler(s service.AuthService) *AuthHandler {
+	return &AuthHandler{service: s}
+}
+
+// RegisterRoutes đăng ký các route cho auth handler. Route SIWF chỉ được đăng ký khi SIWF được bật.
+func (h *AuthHandler) RegisterRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		if h.service.SIWFEnabled() {
+			api.POST("/auth/siwf/nonce", h.Nonce)
+			api.POST("/auth/siwf/verify", h.VerifySIWF)
+		}
+		api.GET("/auth/session", h.Session)
+		api.POST("/auth/x/challenge", h.XChallenge)
+		api.POST("/auth/x/verify", h.VerifyXChallenge)
+		api.GET("/auth/:platform/authorize", h.OAuthAuthorize)
+		api.GET("/auth/:platform/callback", h.OAuthCallback)
+	}
+}
+
+// Nonce cấp nonce dùng một lần cho message Sign In With Farcaster.
+// @Summary Cấp nonce SIWF
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} model.NonceResponse "Nonce"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /auth/siwf/nonce [post]
+func (h *AuthHandler) Nonce(c *gin.Context) {
+	nonce, err := h.service.IssueNonce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, nonce)
+}
+
+// VerifySIWF xác minh message SIWF (EIP-4361) đã ký bởi custody address hoặc địa chỉ đã xác minh của FID.
+// @Summary Đăng nhập bằng Farcaster
+// @Description Trả về token phiên; gửi lại qua header "Authorization: Bearer <token>" để chứng minh FID.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body model.SIWFVerifyRequest true "Message và chữ ký"
+// @Success 200 {object} model.Session "Phiên đăng nhập"
+// @Failure 400 {object} map[string]string "Lỗi validation"
+// @Failure 401 {object} map[string]string "Message, nonce hoặc chữ ký không hợp lệ"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /auth/siwf/verify [post]
+func (h *AuthHandler) VerifySIWF(c *gin.Context) {
+	var req model.SIWFVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.service.SignInWithFarcaster(req)
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// Session trả về phiên của token trong header Authorization.
+// @Summary Lấy phiên hiện tại
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} model.Session "Phiên đăng nhập"
+// @Failure 401 {object} map[string]string "Thiếu hoặc sai token"
+// @Router /auth/session [get]
+func (h *AuthHandler) Session(c *gin.Context) {
+	token := sessionToken(c)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+		return
+	}
+
+	session, err := h.service.GetSession(token)
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// XChallenge cấp mã để chứng minh quyền sở hữu handle X.
+// @Summary Tạo challenge X
+// @Description Người dùng tweet hoặc đặt mã vào bio rồi gọi /auth/x/verify. Gửi kèm token phiên để gắn handle vào phiên hiện tại.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body model.XChallengeRequest true "Handle X"
+// @Success 200 {object} model.XChallengeResponse "Mã challenge"
+// @Failure 400 {object} map[string]string "Lỗi validation"
+// @Failure 401 {object} map[string]string "Token phiên không hợp lệ"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /auth/x/challenge [post]
+func (h *AuthHandler) XChallenge(c *gin.Context) {
+	var req model.XChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.SessionToken = sessionToken(c)
+	challenge, err := h.service.IssueXChallenge(req)
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
+
+// VerifyXChallenge kiểm tra mã đã được đăng lên X và gắn handle vào phiên.
+// @Summary Xác minh challenge X
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body model.XChallengeVerifyRequest true "Mã challenge"
+// @Success 200 {object} model.Session "Phiên có handle X đã chứng minh"
+// @Failure 400 {object} map[string]string "Lỗi validation"
+// @Failure 401 {object} map[string]string "Chưa tìm thấy mã hoặc challenge đã hết hạn"
+// @Failure 403 {object} map[string]string "Sai secret hoặc challenge thuộc phiên khác"
+// @Failure 409 {object} map[string]string "Handle đã được liên kết với user khác"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /auth/x/verify [post]
+func (h *AuthHandler) VerifyXChallenge(c *gin.Context) {
+	var req model.XChallengeVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.SessionToken = sessionToken(c)
+	session, err := h.service.VerifyXChallenge(req)
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// OAuthAuthorize trả về URL để chủ tài khoản (kênh YouTube, tài khoản Reddit) cấp quyền đọc cho server.
+// @Summary Bắt đầu cấp quyền OAuth
+// @Description Mở URL trả về trong trình duyệt; nền tảng chuyển hướng về /auth/{platform}/callback. Gửi kèm token phiên để gắn tài khoản vào phiên hiện tại.
+// @Tags Auth
+// @Produce json
+// @Param platform path string true "Nền tảng (youtube, reddit)"
+// @Param user_id query string false "User ID nội bộ sẽ được liên kết với tài khoản"
+// @Success 200 {object} model.OAuthStartResponse "URL cấp quyền"
+// @Failure 401 {object} map[string]string "Token phiên không hợp lệ"
+// @Failure 404 {object} map[string]string "Nền tảng không hỗ trợ OAuth"
+// @Failure 500 {object} map[string]string "Lỗi server hoặc chưa cấu hình OAuth"
+// @Router /auth/{platform}/authorize [get]
+func (h *AuthHandler) OAuthAuthorize(c *gin.Context) {
+	var req model.OAuthStartRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.SessionToken = sessionToken(c)
+	start, err := h.service.StartOAuth(c.Param("platform"), req)
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, start)
+}
+
+// OAuthCallback nhận code từ nền tảng, lưu token và gắn tài khoản vào phiên.
+// @Summary Callback cấp quyền OAuth
+// @Tags Auth
+// @Produce json
+// @Param platform path string true "Nền tảng (youtube, reddit)"
+// @Param code query string false "Authorization code"
+// @Param state query string true "State từ /auth/{platform}/authorize"
+// @Param error query string false "Lỗi khi người dùng từ chối"
+// @Success 200 {object} model.Session "Phiên có tài khoản đã cấp quyền"
+// @Failure 400 {object} map[string]string "Lỗi validation"
+// @Failure 401 {object} map[string]string "State không hợp lệ hoặc người dùng từ chối"
+// @Failure 404 {object} map[string]string "Nền tảng không hỗ trợ OAuth"
+// @Failure 409 {object} map[string]string "Tài khoản đã được liên kết với user khác"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /auth/{platform}/callback [get]
+func (h *AuthHandler) OAuthCallback(c *gin.Context) {
+	var req model.OAuthCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.service.CompleteOAuth(c.Param("platform"), req)
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// sessionToken đọc token phiên từ header "Authorization: Bearer <token>".
+func sessionToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// authErrorStatus map lỗi xác thực của service sang HTTP status code.
+func authErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidProof), errors.Is(err, service.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrOAuthUnsupported):
+		return http.StatusNotFound
+	}
+	return identityErrorStatus(err)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"checkingsocial/internal/model"
+	"checkingsocial/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CampaignHandler xử lý các request liên quan đến campaign.
+type CampaignHandler struct {
+	service service.CampaignService
+}
+
+// NewCampaignHandler tạo một instance mới của CampaignHandler.
+func NewCampaignHandler(s service.CampaignService) *CampaignHandler {
+	return &CampaignHandler{service: s}
+}
+
+// RegisterRoutes đăng ký các route cho campaign handler.
+func (h *CampaignHandler) RegisterRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		api.POST("/campaigns", h.Create)
+		api.GET("/campaigns", h.List)
+		api.GET("/campaigns/:id", h.Get)
+		api.POST("/campaigns/:id/verify", h.Verify)
+	}
+}
+
+// Create tạo một campaign mới.
+// @Summary Tạo campaign
+// @Description Campaign gồm nhiều task (mẫu social action), có thể yêu cầu thứ tự và giới hạn start_at/end_at.
+// @Tags Campaign
+// @Accept json
+// @Produce json
+// @Param request body model.Campaign true "Định nghĩa campaign"
+// @Success 201 {object} model.Campaign "Campaign đã tạo"
+// @Failure 400 {object} map[string]string "Lỗi validation"
+// @Failure 409 {object} map[string]string "Đã có campaign cùng ID"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /campaigns [post]
+func (h *CampaignHandler) Create(c *gin.Context) {
+	var req model.Campaign
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.service.CreateCampaign(req)
+	if err != nil {
+		c.JSON(campaignErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// List trả về mọi campaign.
+// @Summary Danh sách campaign
+// @Tags Campaign
+// @Produce json
+// @Success 200 {array} model.Campaign "Danh sách campaign"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /campaigns [get]
+func (h *CampaignHandler) List(c *gin.Context) {
+	campaigns, err := h.service.ListCampaigns()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, campaigns)
+}
+
+// Get trả về một campaign theo ID.
+// @Summary Lấy campaign
+// @Tags Campaign
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} model.Campaign "Campaign"
+// @Failure 404 {object} map[string]string "Không tìm thấy campaign"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /campaigns/{id} [get]
+func (h *CampaignHandler) Get(c *gin.Context) {
+	campaign, err := h.service.GetCampaign(c.Param("id"))
+	if err != nil {
+		c.JSON(campaignErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, campaign)
+}
+
+// Verify kiểm tra mọi task của campaign cho một người dùng.
+// @Summary Verify campaign
+// @Description Trả về trạng thái từng task (completed, incomplete, locked, error) và completed=true khi mọi task đã xong.
+// @Tags Campaign
+// @Accept json
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param request body model.CampaignVerifyRequest true "Định danh của người dùng theo nền tảng"
+// @Success 200 {object} model.CampaignVerifyResponse "Kết quả verify"
+// @Failure 400 {object} map[string]string "Lỗi validation"
+// @Failure 404 {object} map[string]string "Không tìm thấy campaign"
+// @Failure 409 {object} map[string]string "Campaign chưa bắt đầu hoặc đã kết thúc"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /campaigns/{id}/verify [post]
+func (h *CampaignHandler) Verify(c *gin.Context) {
+	var req model.CampaignVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.SessionToken = sessionToken(c)
+	result, err := h.service.VerifyCampaign(c.Param("id"), req)
+	if err != nil {
+		c.JSON(campaignErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// campaignErrorStatus map lỗi campaign của service sang HTTP status code.
+func campaignErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidCampaign):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrCampaignNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCampaignInactive), errors.Is(err, service.ErrCampaignExists):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
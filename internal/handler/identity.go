@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"checkingsocial/internal/model"
+	"checkingsocial/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdentityHandler xử lý các request liên kết tài khoản giữa các nền tảng.
+type IdentityHandler struct {
+	service service.IdentityService
+}
+
+// NewIdentityHandler tạo một instance mới của IdentityHandler.
+func NewIdentityHandler(s service.IdentityService) *IdentityHandler {
+	return &IdentityHandler{service: s}
+}
+
+// RegisterRoutes đăng ký các route cho identity handler.
+func (h *IdentityHandler) RegisterRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		api.GET("/identities", h.Find)
+		api.GET("/identities/:user_id", h.Get)
+		api.POST("/identities/:user_id/accounts", h.Link)
+		api.DELETE("/identities/:user_id/accounts/:platform", h.Unlink)
+	}
+}
+
+// Get trả về các tài khoản đã liên kết với một user ID.
+// @Summary Lấy hồ sơ liên kết
+// @Tags Identity
+// @Produce json
+// @Param user_id path string true "User ID nội bộ"
+// @Success 200 {object} model.Identity "Hồ sơ liên kết"
+// @Failure 404 {object} map[string]string "Chưa liên kết tài khoản nào"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /identities/{user_id} [get]
+func (h *IdentityHandler) Get(c *gin.Context) {
+	result, err := h.service.GetIdentity(c.Param("user_id"))
+	if err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Find tìm hồ sơ liên kết theo tài khoản trên một nền tảng.
+// @Summary Tìm hồ sơ theo tài khoản
+// @Tags Identity
+// @Produce json
+// @Param platform query string true "Nền tảng (farcaster, x, discord...)"
+// @Param account_id query string true "Định danh trên nền tảng"
+// @Success 200 {object} model.Identity "Hồ sơ liên kết"
+// @Failure 400 {object} map[string]string "Thiếu tham số"
+// @Failure 404 {object} map[string]string "Tài khoản chưa được liên kết"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /identities [get]
+func (h *IdentityHandler) Find(c *gin.Context) {
+	platform, accountID := c.Query("platform"), c.Query("account_id")
+	if platform == "" || accountID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "platform and account_id are required"})
+		return
+	}
+
+	result, err := h.service.FindIdentity(platform, accountID)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Link liên kết một tài khoản đã chứng minh trong phiên với user ID.
+// @Summary Liên kết tài khoản
+// @Description Cần token phiên (SIWF, challenge X hoặc OAuth) đã chứng minh tài khoản. Phiên chưa thuộc user nào chỉ được tạo user ID mới. Tài khoản Farcaster có thể là FID, username, profile URL hoặc địa chỉ ETH và được lưu dưới dạng FID.
+// @Tags Identity
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID nội bộ"
+// @Param request body model.LinkAccountRequest true "Tài khoản cần liên kết"
+// @Success 200 {object} model.Identity "Hồ sơ sau khi liên kết"
+// @Failure 400 {object} map[string]string "Lỗi validation"
+// @Failure 401 {object} map[string]string "Thiếu token phiên"
+// @Failure 403 {object} map[string]string "Tài khoản chưa được chứng minh hoặc user ID thuộc phiên khác"
+// @Failure 409 {object} map[string]string "Tài khoản đã thuộc về user khác"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /identities/{user_id}/accounts [post]
+func (h *IdentityHandler) Link(c *gin.Context) {
+	var req model.LinkAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.SessionToken = sessionToken(c)
+	result, err := h.service.LinkAccount(c.Param("user_id"), req)
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Unlink xoá liên kết của user ID trên một nền tảng.
+// @Summary Huỷ liên kết tài khoản
+// @Description Cần token phiên có tài khoản đã chứng minh thuộc user ID.
+// @Tags Identity
+// @Produce json
+// @Param user_id path string true "User ID nội bộ"
+// @Param platform path string true "Nền tảng"
+// @Success 200 {object} model.Identity "Hồ sơ sau khi huỷ liên kết"
+// @Failure 401 {object} map[string]string "Thiếu token phiên"
+// @Failure 403 {object} map[string]string "Phiên không thuộc user ID"
+// @Failure 404 {object} map[string]string "Chưa liên kết tài khoản trên nền tảng này"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /identities/{user_id}/accounts/{platform} [delete]
+func (h *IdentityHandler) Unlink(c *gin.Context) {
+	result, err := h.service.UnlinkAccount(c.Param("user_id"), c.Param("platform"), sessionToken(c))
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// identityErrorStatus map lỗi identity của service sang HTTP status code.
+func identityErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrIdentityNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrIdentityConflict):
+		return http.StatusConflict
+	}
+	return errorStatus(err)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"checkingsocial/internal/ledger"
+	"checkingsocial/internal/service"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LedgerHandler xử lý các request truy vấn ledger kết quả kiểm tra.
+type LedgerHandler struct {
+	ledgerService service.LedgerService
+}
+
+// NewLedgerHandler tạo một instance mới của LedgerHandler.
+func NewLedgerHandler(s service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: s}
+}
+
+// RegisterRoutes đăng ký các route cho ledger handler.
+func (h *LedgerHandler) RegisterRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		api.GET("/ledger", h.List)
+	}
+}
+
+// List trả về các lần kiểm tra đã ghi của user sở hữu phiên, mới nhất trước.
+// @Summary Truy vấn ledger
+// @Description Cần header "Authorization: Bearer <token>"; chỉ trả về lịch sử của user đã liên kết với các tài khoản trong phiên. Lọc theo campaign và khoảng thời gian (RFC3339 hoặc unix giây).
+// @Tags Ledger
+// @Produce json
+// @Param user query string false "User ID nội bộ (mặc định là user của phiên)"
+// @Param campaign query string false "Campaign ID"
+// @Param from query string false "Thời điểm bắt đầu"
+// @Param to query string false "Thời điểm kết thúc"
+// @Param limit query int false "Số bản ghi tối đa (mặc định 100, tối đa 1000)"
+// @Success 200 {object} map[string]interface{} "Danh sách entry"
+// @Failure 400 {object} map[string]string "Tham số không hợp lệ"
+// @Failure 401 {object} map[string]string "Thiếu hoặc sai token phiên"
+// @Failure 403 {object} map[string]string "Phiên không sở hữu user"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /ledger [get]
+func (h *LedgerHandler) List(c *gin.Context) {
+	filter := ledger.Filter{
+		UserID:     c.Query("user"),
+		CampaignID: c.Query("campaign"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid from: %v", err)})
+		return
+	}
+	if filter.To, err = parseTimeParam(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid to: %v", err)})
+		return
+	}
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	entries, err := h.ledgerService.ListEntries(sessionToken(c), filter)
+	if err != nil {
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "total": len(entries)})
+}
+
+// parseTimeParam đọc thời điểm dạng RFC3339 hoặc unix giây; chuỗi rỗng trả về zero time.
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -3,7 +3,11 @@ package handler
 import (
 	"checkingsocial/internal/model"
 	"checkingsocial/internal/service"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,18 +28,22 @@ func (h *SocialHandler) RegisterRoutes(router *gin.Engine) {
 	{
 		// Route mới cho social action
 		api.POST("/social-action", h.SocialAction)
+		api.GET("/farcaster/users/:fid", h.FarcasterUser)
 	}
 }
 
 // SocialAction xử lý request thực hiện hành động trên mạng xã hội.
 // @Summary Thực hiện một hành động trên mạng xã hội
-// @Description Nhận một hành động và trả về true nếu xử lý thành công.
+// @Description Nhận một hành động và trả về result=true nếu người dùng đã thực hiện hành động.
 // @Tags Social
 // @Accept json
 // @Produce json
 // @Param request body model.SocialActionRequest true "Yêu cầu hành động"
-// @Success 200 {boolean} boolean "Trả về true hoặc false"
+// @Success 200 {object} model.SocialActionResponse "Kết quả kiểm tra"
 // @Failure 400 {object} map[string]string "Lỗi validation"
+// @Failure 401 {object} map[string]string "Cần đăng nhập SIWF để chứng minh FID"
+// @Failure 403 {object} map[string]string "IDUser không khớp FID của phiên"
+// @Failure 429 {object} map[string]string "Nền tảng giới hạn request, thử lại sau Retry-After giây"
 // @Failure 500 {object} map[string]string "Lỗi server"
 // @Router /social-action [post]
 func (h *SocialHandler) SocialAction(c *gin.Context) {
@@ -45,11 +53,64 @@ func (h *SocialHandler) SocialAction(c *gin.Context) {
 		return
 	}
 
+	req.SessionToken = sessionToken(c)
 	result, err := h.service.CheckSocialAction(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		setRetryAfter(c, err)
+		c.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
+
+// FarcasterUser trả về hồ sơ Farcaster đầy đủ của một người dùng.
+// @Summary Lấy hồ sơ Farcaster
+// @Description Trả về follower/following, pfp, bio, địa chỉ đã xác minh, power badge và Neynar user score.
+// @Tags Farcaster
+// @Produce json
+// @Param fid path string true "FID (hoặc username, profile URL, địa chỉ ETH)"
+// @Success 200 {object} model.FarcasterProfile "Hồ sơ người dùng"
+// @Failure 400 {object} map[string]string "FID không hợp lệ"
+// @Failure 404 {object} map[string]string "Không tìm thấy người dùng"
+// @Failure 500 {object} map[string]string "Lỗi server"
+// @Router /farcaster/users/{fid} [get]
+func (h *SocialHandler) FarcasterUser(c *gin.Context) {
+	profile, err := h.service.GetFarcasterProfile(c.Param("fid"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// errorStatus map lỗi của service sang HTTP status code.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidUser), errors.Is(err, service.ErrInvalidTarget):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrTargetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// defaultRetryAfter được gửi trong Retry-After khi nền tảng không cho biết thời gian chờ.
+const defaultRetryAfter = time.Minute
+
+// setRetryAfter đặt header Retry-After (giây, làm tròn lên) cho lỗi ErrRateLimited.
+func setRetryAfter(c *gin.Context, err error) {
+	if !errors.Is(err, service.ErrRateLimited) {
+		return
+	}
+	wait := service.RetryAfter(err)
+	if wait <= 0 {
+		wait = defaultRetryAfter
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
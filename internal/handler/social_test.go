@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"checkingsocial/github"
+	"checkingsocial/internal/model"
+	"checkingsocial/internal/service"
+	"checkingsocial/pkg/ratelimit"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errChecker trả về err cho mọi CheckSocialAction.
+type errChecker struct {
+	service.Checker
+	err error
+}
+
+func (c errChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	return model.SocialActionResponse{}, c.err
+}
+
+func TestSocialActionRateLimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		retryAfter string
+	}{
+		{"platform retry delay", fmt.Errorf("%w: %w", service.ErrRateLimited, ratelimit.New(github.ErrRateLimited, 29500*time.Millisecond, "")), http.StatusTooManyRequests, "30"},
+		{"unknown retry delay", fmt.Errorf("%w: %w", service.ErrRateLimited, github.ErrRateLimited), http.StatusTooManyRequests, "60"},
+		{"upstream unavailable", fmt.Errorf("%w: no nostr relay answered", service.ErrUpstreamUnavailable), http.StatusServiceUnavailable, ""},
+		{"other error", errors.New("boom"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			NewSocialHandler(errChecker{err: tt.err}).RegisterRoutes(router)
+
+			w := httptest.NewRecorder()
+			body := `{"social":"github","action":"follow","iduser":"alice","target":"octocat"}`
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/social-action", strings.NewReader(body)))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"checkingsocial/internal/service"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxWebhookBodySize giới hạn kích thước body webhook (1MB).
+	maxWebhookBodySize = 1 << 20
+	// neynarSignatureHeader chứa HMAC-SHA512 (hex) của body do Neynar ký.
+	neynarSignatureHeader = "X-Neynar-Signature"
+)
+
+// WebhookHandler xử lý webhook từ các nhà cung cấp (Neynar...).
+type WebhookHandler struct {
+	processor service.WebhookProcessor
+}
+
+// NewWebhookHandler tạo một instance mới của WebhookHandler.
+func NewWebhookHandler(p service.WebhookProcessor) *WebhookHandler {
+	return &WebhookHandler{processor: p}
+}
+
+// RegisterRoutes đăng ký các route cho webhook handler.
+func (h *WebhookHandler) RegisterRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		api.POST("/webhooks/neynar", h.Neynar)
+	}
+}
+
+// Neynar nhận event follow/reaction/cast từ Neynar và cập nhật trạng thái Redis.
+// @Summary Nhận webhook của Neynar
+// @Description Xác thực chữ ký X-Neynar-Signature (HMAC-SHA512) rồi xử lý follow.created, follow.deleted, reaction.created, cast.created.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string "Event đã xử lý"
+// @Failure 400 {object} map[string]string "Body không hợp lệ"
+// @Failure 401 {object} map[string]string "Chữ ký không hợp lệ"
+// @Failure 503 {object} map[string]string "Redis chưa sẵn sàng"
+// @Router /webhooks/neynar [post]
+func (h *WebhookHandler) Neynar(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	eventType, err := h.processor.HandleNeynarWebhook(body, c.GetHeader(neynarSignatureHeader))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidSignature):
+			status = http.StatusUnauthorized
+		case errors.Is(err, service.ErrInvalidPayload):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "type": eventType})
+}
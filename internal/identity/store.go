@@ -0,0 +1,204 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrNotFound được trả về khi user ID chưa liên kết tài khoản nào.
+	ErrNotFound = errors.New("no linked accounts")
+	// ErrConflict được trả về khi tài khoản đã được liên kết với một user ID khác.
+	ErrConflict = errors.New("account already linked to another user")
+)
+
+// Store định nghĩa interface lưu liên kết giữa user ID nội bộ và tài khoản trên từng nền tảng.
+type Store interface {
+	// Link liên kết tài khoản accountID trên platform với userID, ghi đè tài khoản cũ của platform đó.
+	// Trả về ErrConflict nếu tài khoản đã thuộc về user khác.
+	Link(ctx context.Context, userID string, platform string, accountID string) error
+	// Unlink xoá liên kết của userID trên platform.
+	Unlink(ctx context.Context, userID string, platform string) error
+	// Accounts trả về platform -> accountID của userID, hoặc ErrNotFound.
+	Accounts(ctx context.Context, userID string) (map[string]string, error)
+	// FindUser trả về user ID đã liên kết tài khoản accountID trên platform, hoặc ErrNotFound.
+	FindUser(ctx context.Context, platform string, accountID string) (string, error)
+}
+
+// MemoryStore lưu liên kết trong bộ nhớ (dùng khi không có Redis).
+type MemoryStore struct {
+	mu       sync.RWMutex
+	accounts map[string]map[string]string // userID -> platform -> accountID
+	owners   map[string]string            // platform|accountID -> userID
+}
+
+// NewMemoryStore tạo một MemoryStore rỗng.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{accounts: map[string]map[string]string{}, owners: map[string]string{}}
+}
+
+// Link implements Store.
+func (s *MemoryStore) Link(ctx context.Context, userID string, platform string, accountID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := ownerField(platform, accountID)
+	if owner, ok := s.owners[key]; ok && owner != userID {
+		return fmt.Errorf("%w: %s", ErrConflict, owner)
+	}
+	links := s.accounts[userID]
+	if links == nil {
+		links = map[string]string{}
+		s.accounts[userID] = links
+	}
+	if old, ok := links[platform]; ok {
+		delete(s.owners, ownerField(platform, old))
+	}
+	links[platform] = accountID
+	s.owners[key] = userID
+	return nil
+}
+
+// Unlink implements Store.
+func (s *MemoryStore) Unlink(ctx context.Context, userID string, platform string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links := s.accounts[userID]
+	old, ok := links[platform]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(links, platform)
+	delete(s.owners, ownerField(platform, old))
+	if len(links) == 0 {
+		delete(s.accounts, userID)
+	}
+	return nil
+}
+
+// Accounts implements Store.
+func (s *MemoryStore) Accounts(ctx context.Context, userID string) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	links, ok := s.accounts[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := make(map[string]string, len(links))
+	for p, id := range links {
+		out[p] = id
+	}
+	return out, nil
+}
+
+// FindUser implements Store.
+func (s *MemoryStore) FindUser(ctx context.Context, platform string, accountID string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	userID, ok := s.owners[ownerField(platform, accountID)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return userID, nil
+}
+
+// Redis keys của RedisStore.
+const (
+	redisUserKey   = "identity:user:%s" // Hash: platform -> accountID
+	redisOwnersKey = "identity:owners"  // Hash: platform|accountID -> userID
+)
+
+// RedisStore lưu liên kết trong Redis để giữ được qua các lần restart.
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore tạo RedisStore từ một Redis client đã kết nối.
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+// linkScript liên kết tài khoản trong một bước atomic: KEYS[1] là owners, KEYS[2] là hash của user;
+// ARGV là userID, platform, accountID. Trả về user đang sở hữu tài khoản khi xung đột, "" khi thành công.
+// Khoá trong owners có dạng platform|accountID như ownerField.
+var linkScript = redis.NewScript(`
+local field = ARGV[2] .. "|" .. ARGV[3]
+local owner = redis.call("HGET", KEYS[1], field)
+if owner and owner ~= ARGV[1] then
+	return owner
+end
+local old = redis.call("HGET", KEYS[2], ARGV[2])
+if old and old ~= ARGV[3] then
+	redis.call("HDEL", KEYS[1], ARGV[2] .. "|" .. old)
+end
+redis.call("HSET", KEYS[1], field, ARGV[1])
+redis.call("HSET", KEYS[2], ARGV[2], ARGV[3])
+return ""
+`)
+
+// unlinkScript xoá liên kết trong một bước atomic: KEYS như linkScript, ARGV là platform.
+// Trả về 0 khi user chưa liên kết tài khoản trên platform.
+var unlinkScript = redis.NewScript(`
+local old = redis.call("HGET", KEYS[2], ARGV[1])
+if not old then
+	return 0
+end
+redis.call("HDEL", KEYS[2], ARGV[1])
+redis.call("HDEL", KEYS[1], ARGV[1] .. "|" .. old)
+return 1
+`)
+
+// Link implements Store. Index xuôi và ngược được cập nhật cùng lúc bằng linkScript.
+func (s *RedisStore) Link(ctx context.Context, userID string, platform string, accountID string) error {
+	keys := []string{redisOwnersKey, fmt.Sprintf(redisUserKey, userID)}
+	owner, err := linkScript.Run(ctx, s.rdb, keys, userID, platform, accountID).Text()
+	if err != nil {
+		return err
+	}
+	if owner != "" {
+		return fmt.Errorf("%w: %s", ErrConflict, owner)
+	}
+	return nil
+}
+
+// Unlink implements Store.
+func (s *RedisStore) Unlink(ctx context.Context, userID string, platform string) error {
+	keys := []string{redisOwnersKey, fmt.Sprintf(redisUserKey, userID)}
+	removed, err := unlinkScript.Run(ctx, s.rdb, keys, platform).Int()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Accounts implements Store.
+func (s *RedisStore) Accounts(ctx context.Context, userID string) (map[string]string, error) {
+	links, err := s.rdb.HGetAll(ctx, fmt.Sprintf(redisUserKey, userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, ErrNotFound
+	}
+	return links, nil
+}
+
+// FindUser implements Store.
+func (s *RedisStore) FindUser(ctx context.Context, platform string, accountID string) (string, error) {
+	userID, err := s.rdb.HGet(ctx, redisOwnersKey, ownerField(platform, accountID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+	return userID, err
+}
+
+// ownerField là khoá của một tài khoản trong index ngược.
+func ownerField(platform string, accountID string) string {
+	return platform + "|" + accountID
+}
@@ -0,0 +1,100 @@
+package ledger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	// DefaultLimit là số bản ghi trả về khi Filter.Limit không được đặt.
+	DefaultLimit = 100
+	// MaxLimit là số bản ghi tối đa trả về trong một lần Query.
+	MaxLimit = 1000
+)
+
+// Entry là một lần kiểm tra CheckSocialAction đã được ghi lại, kể cả khi lỗi.
+type Entry struct {
+	ID string `json:"id"`
+	// UserID là user ID nội bộ sở hữu Account, rỗng khi tài khoản chưa được liên kết
+	UserID string `json:"user_id,omitempty"`
+	// Account là định danh của tài khoản trên Platform đã được kiểm tra
+	Account    string `json:"account"`
+	CampaignID string `json:"campaign_id,omitempty"`
+	Platform   string `json:"platform"`
+	Action     string `json:"action"`
+	Target     string `json:"target,omitempty"`
+	Result     bool   `json:"result"`
+	// Provider là nguồn dữ liệu đã trả lời (neynar, hub, redis, apify...)
+	Provider  string    `json:"provider,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Filter là điều kiện truy vấn ledger. Trường rỗng/zero nghĩa là không lọc theo trường đó.
+type Filter struct {
+	UserID     string
+	CampaignID string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// limit trả về Limit đã được chuẩn hoá về [1, MaxLimit].
+func (f Filter) limit() int {
+	switch {
+	case f.Limit <= 0:
+		return DefaultLimit
+	case f.Limit > MaxLimit:
+		return MaxLimit
+	}
+	return f.Limit
+}
+
+// match kiểm tra entry có thoả mãn filter không.
+func (f Filter) match(e Entry) bool {
+	if f.UserID != "" && e.UserID != f.UserID {
+		return false
+	}
+	if f.CampaignID != "" && e.CampaignID != f.CampaignID {
+		return false
+	}
+	if !f.From.IsZero() && e.CreatedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && e.CreatedAt.After(f.To) {
+		return false
+	}
+	return true
+}
+
+// Repository định nghĩa interface lưu và truy vấn ledger.
+type Repository interface {
+	// Record ghi một entry; ID và CreatedAt được tự sinh nếu để trống.
+	Record(ctx context.Context, e Entry) error
+	// Query trả về các entry thoả filter, mới nhất trước.
+	Query(ctx context.Context, f Filter) ([]Entry, error)
+	// Close giải phóng tài nguyên của repository.
+	Close() error
+}
+
+// prepare điền ID và CreatedAt còn thiếu của entry.
+func prepare(e Entry) Entry {
+	if e.ID == "" {
+		e.ID = newID()
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	e.CreatedAt = e.CreatedAt.UTC()
+	return e
+}
+
+// newID sinh ID ngẫu nhiên 16 byte dạng hex.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
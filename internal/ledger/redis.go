@@ -0,0 +1,99 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys của RedisRepository. Mỗi sorted set chứa entry dạng JSON, score là CreatedAt (unix ms).
+const (
+	redisAllKey      = "ledger:entries"
+	redisUserKey     = "ledger:user:%s"
+	redisCampaignKey = "ledger:campaign:%s"
+)
+
+// RedisRepository lưu ledger trong Redis, đánh index theo user và campaign.
+type RedisRepository struct {
+	rdb *redis.Client
+}
+
+// NewRedisRepository tạo RedisRepository từ một Redis client đã kết nối.
+func NewRedisRepository(rdb *redis.Client) *RedisRepository {
+	return &RedisRepository{rdb: rdb}
+}
+
+// Record implements Repository.
+func (r *RedisRepository) Record(ctx context.Context, e Entry) error {
+	e = prepare(e)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	z := redis.Z{Score: float64(e.CreatedAt.UnixMilli()), Member: data}
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, redisAllKey, z)
+		if e.UserID != "" {
+			pipe.ZAdd(ctx, fmt.Sprintf(redisUserKey, e.UserID), z)
+		}
+		if e.CampaignID != "" {
+			pipe.ZAdd(ctx, fmt.Sprintf(redisCampaignKey, e.CampaignID), z)
+		}
+		return nil
+	})
+	return err
+}
+
+// Query implements Repository. Index cụ thể nhất được dùng, các điều kiện còn lại lọc trong bộ nhớ.
+func (r *RedisRepository) Query(ctx context.Context, f Filter) ([]Entry, error) {
+	key := redisAllKey
+	exact := f.UserID == "" || f.CampaignID == ""
+	switch {
+	case f.UserID != "":
+		key = fmt.Sprintf(redisUserKey, f.UserID)
+	case f.CampaignID != "":
+		key = fmt.Sprintf(redisCampaignKey, f.CampaignID)
+	}
+
+	rng := &redis.ZRangeBy{Min: "-inf", Max: "+inf"}
+	if !f.From.IsZero() {
+		rng.Min = strconv.FormatInt(f.From.UnixMilli(), 10)
+	}
+	if !f.To.IsZero() {
+		rng.Max = strconv.FormatInt(f.To.UnixMilli(), 10)
+	}
+	limit := f.limit()
+	if exact {
+		// Index đã khớp mọi điều kiện nên có thể giới hạn ngay trong Redis
+		rng.Count = int64(limit)
+	}
+
+	members, err := r.rdb.ZRevRangeByScore(ctx, key, rng).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	out := []Entry{}
+	for _, m := range members {
+		var e Entry
+		if err := json.Unmarshal([]byte(m), &e); err != nil {
+			return nil, fmt.Errorf("decode ledger entry: %w", err)
+		}
+		if !f.match(e) {
+			continue
+		}
+		out = append(out, e)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Close implements Repository. Redis client được dùng chung nên không đóng ở đây.
+func (r *RedisRepository) Close() error {
+	return nil
+}
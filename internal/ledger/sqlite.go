@@ -0,0 +1,156 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS ledger_entries (
+	id          TEXT PRIMARY KEY,
+	user_id     TEXT NOT NULL,
+	account     TEXT NOT NULL DEFAULT '',
+	campaign_id TEXT NOT NULL DEFAULT '',
+	platform    TEXT NOT NULL,
+	action      TEXT NOT NULL,
+	target      TEXT NOT NULL DEFAULT '',
+	result      INTEGER NOT NULL,
+	provider    TEXT NOT NULL DEFAULT '',
+	latency_ms  INTEGER NOT NULL,
+	error       TEXT NOT NULL DEFAULT '',
+	created_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_created ON ledger_entries (created_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_user ON ledger_entries (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_campaign ON ledger_entries (campaign_id, created_at);
+`
+
+// sqliteMigrations nâng cấp file ledger tạo bởi phiên bản cũ. Mỗi migration bắt đầu bằng một ALTER TABLE;
+// lỗi "duplicate column" nghĩa là migration đã chạy rồi và các câu lệnh còn lại được bỏ qua.
+var sqliteMigrations = [][]string{
+	// Phiên bản cũ ghi định danh trên nền tảng vào user_id
+	{
+		`ALTER TABLE ledger_entries ADD COLUMN account TEXT NOT NULL DEFAULT ''`,
+		`UPDATE ledger_entries SET account = user_id, user_id = ''`,
+	},
+}
+
+// SQLiteRepository lưu ledger trong một file SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository mở (hoặc tạo) file SQLite tại path và khởi tạo schema.
+func NewSQLiteRepository(path string) (*SQLiteRepository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite %s: %w", path, err)
+	}
+	// SQLite chỉ cho một writer tại một thời điểm
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init ledger schema: %w", err)
+	}
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate ledger schema: %w", err)
+	}
+	return &SQLiteRepository{db: db}, nil
+}
+
+// migrateSQLite chạy các sqliteMigrations chưa được áp dụng, mỗi migration trong một transaction.
+func migrateSQLite(db *sql.DB) error {
+	for _, m := range sqliteMigrations {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(m[0]); err != nil {
+			tx.Rollback()
+			if strings.Contains(err.Error(), "duplicate column") {
+				continue
+			}
+			return err
+		}
+		for _, stmt := range m[1:] {
+			if _, err := tx.Exec(stmt); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record implements Repository.
+func (r *SQLiteRepository) Record(ctx context.Context, e Entry) error {
+	e = prepare(e)
+	_, err := r.db.ExecContext(ctx, `INSERT INTO ledger_entries
+		(id, user_id, account, campaign_id, platform, action, target, result, provider, latency_ms, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.UserID, e.Account, e.CampaignID, e.Platform, e.Action, e.Target, e.Result, e.Provider, e.LatencyMS, e.Error, e.CreatedAt.UnixMilli())
+	return err
+}
+
+// Query implements Repository.
+func (r *SQLiteRepository) Query(ctx context.Context, f Filter) ([]Entry, error) {
+	var where []string
+	var args []any
+	if f.UserID != "" {
+		where = append(where, "user_id = ?")
+		args = append(args, f.UserID)
+	}
+	if f.CampaignID != "" {
+		where = append(where, "campaign_id = ?")
+		args = append(args, f.CampaignID)
+	}
+	if !f.From.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, f.From.UnixMilli())
+	}
+	if !f.To.IsZero() {
+		where = append(where, "created_at <= ?")
+		args = append(args, f.To.UnixMilli())
+	}
+
+	query := `SELECT id, user_id, account, campaign_id, platform, action, target, result, provider, latency_ms, error, created_at
+		FROM ledger_entries`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC LIMIT ?"
+	args = append(args, f.limit())
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Entry{}
+	for rows.Next() {
+		var e Entry
+		var createdAt int64
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Account, &e.CampaignID, &e.Platform, &e.Action, &e.Target,
+			&e.Result, &e.Provider, &e.LatencyMS, &e.Error, &createdAt); err != nil {
+			return nil, err
+		}
+		e.CreatedAt = time.UnixMilli(createdAt).UTC()
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Close implements Repository.
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+// NonceResponse là nonce dùng một lần để đưa vào message Sign In With Farcaster
+type NonceResponse struct {
+	Nonce string `json:"nonce"`
+	// Domain là domain mà message phải khai báo
+	Domain    string    `json:"domain"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SIWFVerifyRequest là message EIP-4361 đã ký và chữ ký (hex) của nó
+type SIWFVerifyRequest struct {
+	Message   string `json:"message" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+// Session là phiên đăng nhập đã chứng minh quyền sở hữu tài khoản
+type Session struct {
+	Token string `json:"token"`
+	// Accounts là platform -> định danh đã được chứng minh (farcaster -> FID...)
+	Accounts map[string]string `json:"accounts"`
+	// Address là địa chỉ ETH đã ký message SIWF
+	Address   string    `json:"address,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// XChallengeRequest yêu cầu một mã để chứng minh quyền sở hữu handle X
+type XChallengeRequest struct {
+	Handle string `json:"handle" binding:"required"`
+	// UserID (tuỳ chọn) là user ID nội bộ sẽ được liên kết với handle khi challenge thành công
+	UserID string `json:"user_id,omitempty"`
+	// SessionToken được handler điền từ header Authorization
+	SessionToken string `json:"-"`
+}
+
+// XChallengeResponse là mã mà người dùng phải tweet hoặc đặt vào bio
+type XChallengeResponse struct {
+	Code   string `json:"code"`
+	Handle string `json:"handle"`
+	// Text là nội dung tweet gợi ý có chứa mã
+	Text string `json:"text"`
+	// Secret chỉ được trả cho người yêu cầu challenge và phải gửi kèm khi xác minh; mã Code thì công khai
+	// trên X nên không đủ để xác minh
+	Secret    string    `json:"secret"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// XChallengeVerifyRequest yêu cầu kiểm tra mã đã được đăng lên X
+type XChallengeVerifyRequest struct {
+	Code string `json:"code" binding:"required"`
+	// Secret là XChallengeResponse.Secret của challenge
+	Secret string `json:"secret" binding:"required"`
+	// SessionToken được handler điền từ header Authorization
+	SessionToken string `json:"-"`
+}
+
+// OAuthStartRequest yêu cầu URL cấp quyền OAuth cho một nền tảng
+type OAuthStartRequest struct {
+	// UserID (tuỳ chọn) là user ID nội bộ sẽ được liên kết với tài khoản khi cấp quyền thành công
+	UserID string `form:"user_id"`
+	// SessionToken được handler điền từ header Authorization
+	SessionToken string `json:"-" form:"-"`
+}
+
+// OAuthStartResponse là URL mà người dùng mở để cấp quyền
+type OAuthStartResponse struct {
+	URL string `json:"url"`
+	// State được nền tảng trả lại ở callback, dùng một lần
+	State     string    `json:"state"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OAuthCallbackRequest là query mà nền tảng gửi về redirect URL
+type OAuthCallbackRequest struct {
+	Code  string `form:"code"`
+	State string `form:"state" binding:"required"`
+	// Error được điền khi người dùng từ chối cấp quyền
+	Error string `form:"error"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// Campaign là một chiến dịch gồm nhiều task mà người dùng phải hoàn thành
+type Campaign struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Ordered=true nghĩa là các task phải hoàn thành theo thứ tự; task sau chỉ được kiểm tra khi task trước đã xong
+	Ordered bool           `json:"ordered"`
+	Tasks   []CampaignTask `json:"tasks" binding:"required,min=1,dive"`
+	StartAt *time.Time     `json:"start_at,omitempty"`
+	EndAt   *time.Time     `json:"end_at,omitempty"`
+}
+
+// CampaignTask là mẫu SocialActionRequest của một bước trong campaign; IDUser được điền khi verify
+type CampaignTask struct {
+	ID     string `json:"id"`
+	Social string `json:"social" binding:"required"`
+	Action string `json:"action" binding:"required"`
+	Target string `json:"target,omitempty"`
+}
+
+// Request tạo SocialActionRequest của task cho một người dùng
+func (t CampaignTask) Request(idUser string, campaignID string) SocialActionRequest {
+	return SocialActionRequest{
+		Social:     t.Social,
+		Action:     t.Action,
+		IDUser:     idUser,
+		Target:     t.Target,
+		CampaignID: campaignID,
+	}
+}
+
+// CampaignVerifyRequest chứa định danh của người dùng trên từng nền tảng, ví dụ {"x": "jack", "farcaster": "dwr"},
+// hoặc UserID nội bộ để lấy các tài khoản đã liên kết
+type CampaignVerifyRequest struct {
+	UserID     string            `json:"user_id,omitempty"`
+	Identities map[string]string `json:"identities" binding:"required_without=UserID"`
+	// SessionToken được handler điền từ header Authorization
+	SessionToken string `json:"-"`
+}
+
+// Trạng thái của một task sau khi verify
+const (
+	TaskStatusCompleted  = "completed"
+	TaskStatusIncomplete = "incomplete"
+	// TaskStatusLocked là task của campaign có thứ tự chưa được kiểm tra vì task trước chưa hoàn thành
+	TaskStatusLocked = "locked"
+	TaskStatusError  = "error"
+)
+
+// CampaignTaskResult là kết quả verify một task
+type CampaignTaskResult struct {
+	TaskID string `json:"task_id"`
+	Social string `json:"social"`
+	Action string `json:"action"`
+	Target string `json:"target,omitempty"`
+	Status string `json:"status"`
+	Result bool   `json:"result"`
+	Error  string `json:"error,omitempty"`
+	// Detail là response đầy đủ của lần kiểm tra (fid, source, failed_rule...)
+	Detail *SocialActionResponse `json:"detail,omitempty"`
+}
+
+// CampaignVerifyResponse là kết quả verify toàn bộ campaign cho một người dùng
+type CampaignVerifyResponse struct {
+	CampaignID string               `json:"campaign_id"`
+	Completed  bool                 `json:"completed"`
+	Done       int                  `json:"done"`
+	Total      int                  `json:"total"`
+	Tasks      []CampaignTaskResult `json:"tasks"`
+}
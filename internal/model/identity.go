@@ -0,0 +1,16 @@
+package model
+
+// Identity là hồ sơ liên kết một user ID nội bộ với tài khoản trên từng nền tảng
+type Identity struct {
+	UserID string `json:"user_id"`
+	// Accounts là platform -> định danh trên nền tảng (farcaster -> FID, x -> handle, discord -> user ID...)
+	Accounts map[string]string `json:"accounts"`
+}
+
+// LinkAccountRequest là request liên kết một tài khoản với user ID nội bộ
+type LinkAccountRequest struct {
+	Platform  string `json:"platform" binding:"required"`
+	AccountID string `json:"account_id" binding:"required"`
+	// SessionToken là token phiên đã chứng minh quyền sở hữu AccountID, được handler điền từ header Authorization
+	SessionToken string `json:"-"`
+}
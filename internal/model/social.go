@@ -5,7 +5,67 @@ package model
 type SocialActionRequest struct {
 	Social string `json:"social" binding:"required"`
 	Action string `json:"action" binding:"required"`
-	IDUser string `json:"iduser" binding:"required"`
+	// IDUser là định danh trên nền tảng; có thể bỏ trống khi có UserID đã liên kết tài khoản
+	IDUser string `json:"iduser" binding:"required_without=UserID"`
+	// UserID là user ID nội bộ; IDUser được điền từ tài khoản đã liên kết trên nền tảng Social
+	UserID string `json:"user_id,omitempty"`
+	// Target là đối tượng của hành động: tài khoản cần follow (mặc định lấy từ env) hoặc cast hash cần like/recast/reply
+	Target string `json:"target,omitempty"`
+	// CampaignID gắn lần kiểm tra với một campaign để truy vấn ledger
+	CampaignID string `json:"campaign_id,omitempty"`
+	// SessionToken là token phiên (header Authorization) chứng minh quyền sở hữu IDUser, không đọc từ body
+	SessionToken string `json:"-"`
+	// AccessToken là token OAuth mà chủ IDUser đã cấp, được điền từ store trước khi kiểm tra, không đọc từ body
+	AccessToken string `json:"-"`
+}
+
+// SocialActionResponse là kết quả kiểm tra một hành động trên mạng xã hội
+type SocialActionResponse struct {
+	Result bool `json:"result"`
+	// FID là Farcaster FID mà IDUser được resolve ra (username, profile URL, địa chỉ ETH...)
+	FID int64 `json:"fid,omitempty"`
+	// Source là nguồn dữ liệu trả lời kết quả (redis, neynar...)
+	Source string `json:"source,omitempty"`
+	// FailedRule là tên rule chống sybil/chất lượng mà người dùng không đạt (min_score, min_followers...)
+	FailedRule string `json:"failed_rule,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	// Status là trạng thái quan hệ: ok, muted, blocked (Farcaster), trạng thái thành viên (member, left, kicked...)
+	// hoặc StatusUnverifiable
+	Status       string        `json:"status,omitempty"`
+	Relationship *Relationship `json:"relationship,omitempty"`
+	// Balance là tổng số dư token (đơn vị nhỏ nhất) của các ví đã kiểm tra, cho các kiểm tra on-chain
+	Balance string `json:"balance,omitempty"`
+}
+
+// StatusUnverifiable là Status khi nền tảng không cho kiểm tra hành động (ví dụ instance ẩn danh sách
+// following); Result khi đó là false nhưng không có nghĩa người dùng chưa thực hiện hành động
+const StatusUnverifiable = "unverifiable"
+
+// Relationship mô tả quan hệ giữa người dùng và tài khoản mục tiêu, nhìn từ phía người dùng.
+// Các trường block/mute là nil (bị bỏ khỏi JSON) khi nguồn trả lời không biết trạng thái đó.
+type Relationship struct {
+	Following  bool  `json:"following"`
+	FollowedBy bool  `json:"followed_by"`
+	Blocked    *bool `json:"blocked,omitempty"`
+	BlockedBy  *bool `json:"blocked_by,omitempty"`
+	Muted      *bool `json:"muted,omitempty"`
+	MutedBy    *bool `json:"muted_by,omitempty"`
+}
+
+// FarcasterProfile là thông tin hồ sơ Farcaster hiển thị trên profile card
+type FarcasterProfile struct {
+	FID               int64    `json:"fid"`
+	Username          string   `json:"username"`
+	DisplayName       string   `json:"display_name"`
+	PfpURL            string   `json:"pfp_url,omitempty"`
+	Bio               string   `json:"bio,omitempty"`
+	FollowerCount     int64    `json:"follower_count"`
+	FollowingCount    int64    `json:"following_count"`
+	CustodyAddress    string   `json:"custody_address,omitempty"`
+	VerifiedAddresses []string `json:"verified_addresses"`
+	VerifiedSolAddrs  []string `json:"verified_sol_addresses,omitempty"`
+	PowerBadge        bool     `json:"power_badge"`
+	NeynarUserScore   float64  `json:"neynar_user_score"`
 }
 
 // SocialPlatform định nghĩa các nền tảng mạng xã hội được hỗ trợ
@@ -18,6 +78,15 @@ const (
 	TikTok    SocialPlatform = "tiktok"
 	YouTube   SocialPlatform = "youtube"
 	LinkedIn  SocialPlatform = "linkedin"
+	Telegram  SocialPlatform = "telegram"
+	Discord   SocialPlatform = "discord"
+	GitHub    SocialPlatform = "github"
+	Bluesky   SocialPlatform = "bluesky"
+	Lens      SocialPlatform = "lens"
+	Onchain   SocialPlatform = "onchain"
+	Reddit    SocialPlatform = "reddit"
+	Mastodon  SocialPlatform = "mastodon"
+	Nostr     SocialPlatform = "nostr"
 )
 
 // CheckRequest là request để kiểm tra tài khoản mạng xã hội
@@ -0,0 +1,92 @@
+package reverify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Loại event được gửi qua Notifier.
+const (
+	EventUnfollowed = "unfollowed"
+	EventRevoked    = "revoked"
+	EventRestored   = "restored"
+)
+
+// Event mô tả một thay đổi trạng thái phát hiện được khi kiểm tra lại.
+type Event struct {
+	Type           string       `json:"type"`
+	Verification   Verification `json:"verification"`
+	PreviousStatus string       `json:"previous_status"`
+	DetectedAt     time.Time    `json:"detected_at"`
+}
+
+// Notifier định nghĩa interface gửi event ra ngoài (log, webhook...).
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// LogNotifier ghi event ra log.
+type LogNotifier struct{}
+
+// Notify implements Notifier.
+func (LogNotifier) Notify(ctx context.Context, event Event) error {
+	req := event.Verification.Request
+	log.Printf("[Reverify] event=%s social=%s action=%s iduser=%s target=%s previous=%s",
+		event.Type, req.Social, req.Action, req.IDUser, req.Target, event.PreviousStatus)
+	return nil
+}
+
+// WebhookNotifier POST event dạng JSON tới một URL.
+type WebhookNotifier struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewWebhookNotifier tạo WebhookNotifier với timeout mặc định.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify implements Notifier.
+func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notifier webhook status %d: %s", resp.StatusCode, string(snippet))
+	}
+	return nil
+}
+
+// MultiNotifier gửi event tới nhiều Notifier, trả về lỗi đầu tiên gặp phải.
+type MultiNotifier []Notifier
+
+// Notify implements Notifier.
+func (m MultiNotifier) Notify(ctx context.Context, event Event) error {
+	var firstErr error
+	for _, n := range m {
+		if err := n.Notify(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,167 @@
+package reverify
+
+import (
+	"checkingsocial/internal/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Trạng thái của một verification đã được ghi nhận.
+const (
+	StatusVerified   = "verified"
+	StatusUnfollowed = "unfollowed"
+	StatusRevoked    = "revoked"
+)
+
+// Verification là một lần xác minh thành công cần được kiểm tra lại định kỳ.
+type Verification struct {
+	ID            string                    `json:"id"`
+	Request       model.SocialActionRequest `json:"request"`
+	Status        string                    `json:"status"`
+	VerifiedAt    time.Time                 `json:"verified_at"`
+	LastCheckedAt time.Time                 `json:"last_checked_at"`
+	// StatusChangedAt là thời điểm trạng thái thay đổi gần nhất (verified -> unfollowed...)
+	StatusChangedAt time.Time `json:"status_changed_at"`
+}
+
+// VerificationID tạo ID ổn định cho một request để các lần xác minh lặp lại chỉ cập nhật một bản ghi.
+func VerificationID(req model.SocialActionRequest) string {
+	return strings.Join([]string{req.Social, req.Action, req.IDUser, req.Target}, "|")
+}
+
+// Store định nghĩa interface lưu các verification cần kiểm tra lại.
+type Store interface {
+	// Save thêm mới hoặc ghi đè verification theo ID.
+	Save(ctx context.Context, v Verification) error
+	// Get trả về verification theo ID, ok=false nếu không tồn tại.
+	Get(ctx context.Context, id string) (Verification, bool, error)
+	// ListVerifiedSince trả về các verification có VerifiedAt >= since.
+	ListVerifiedSince(ctx context.Context, since time.Time) ([]Verification, error)
+}
+
+// MemoryStore lưu verification trong bộ nhớ (dùng khi không có Redis).
+type MemoryStore struct {
+	mu    sync.RWMutex
+	items map[string]Verification
+}
+
+// NewMemoryStore tạo một MemoryStore rỗng.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]Verification{}}
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(ctx context.Context, v Verification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[v.ID] = v
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(ctx context.Context, id string) (Verification, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.items[id]
+	return v, ok, nil
+}
+
+// ListVerifiedSince implements Store.
+func (s *MemoryStore) ListVerifiedSince(ctx context.Context, since time.Time) ([]Verification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []Verification
+	for _, v := range s.items {
+		if !v.VerifiedAt.Before(since) {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].VerifiedAt.Before(out[j].VerifiedAt) })
+	return out, nil
+}
+
+// Redis keys của RedisStore.
+const (
+	redisItemsKey = "reverify:verifications" // Hash: id -> JSON
+	redisIndexKey = "reverify:verified_at"   // Sorted set: id theo VerifiedAt (unix)
+)
+
+// RedisStore lưu verification trong Redis để giữ được qua các lần restart.
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore tạo RedisStore từ một Redis client đã kết nối.
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+// Save implements Store.
+func (s *RedisStore) Save(ctx context.Context, v Verification) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, redisItemsKey, v.ID, data)
+		pipe.ZAdd(ctx, redisIndexKey, redis.Z{Score: float64(v.VerifiedAt.Unix()), Member: v.ID})
+		return nil
+	})
+	return err
+}
+
+// Get implements Store.
+func (s *RedisStore) Get(ctx context.Context, id string) (Verification, bool, error) {
+	data, err := s.rdb.HGet(ctx, redisItemsKey, id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Verification{}, false, nil
+	}
+	if err != nil {
+		return Verification{}, false, err
+	}
+	var v Verification
+	if err := json.Unmarshal(data, &v); err != nil {
+		return Verification{}, false, fmt.Errorf("decode verification %s: %w", id, err)
+	}
+	return v, true, nil
+}
+
+// ListVerifiedSince implements Store.
+func (s *RedisStore) ListVerifiedSince(ctx context.Context, since time.Time) ([]Verification, error) {
+	ids, err := s.rdb.ZRangeByScore(ctx, redisIndexKey, &redis.ZRangeBy{
+		Min: fmt.Sprintf("%d", since.Unix()),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values, err := s.rdb.HMGet(ctx, redisItemsKey, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Verification, 0, len(values))
+	for i, raw := range values {
+		str, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		var v Verification
+		if err := json.Unmarshal([]byte(str), &v); err != nil {
+			return nil, fmt.Errorf("decode verification %s: %w", ids[i], err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
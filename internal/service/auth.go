@@ -0,0 +1,470 @@
+package service
+
+import (
+	"checkingsocial/farcaster"
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/identity"
+	"checkingsocial/internal/model"
+	"checkingsocial/twitter"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultNonceTTL      = 5 * time.Minute
+	defaultSessionTTL    = 24 * time.Hour
+	defaultXChallengeTTL = 30 * time.Minute
+	defaultOAuthStateTTL = 10 * time.Minute
+	xChallengeCodePrefix = "verify-"
+	xChallengeCodeLength = 10
+	// xChallengeSecretLength: secret trả riêng cho người yêu cầu challenge
+	xChallengeSecretLength = 32
+	// nonceAlphabet: EIP-4361 yêu cầu nonce chỉ gồm chữ và số, tối thiểu 8 ký tự
+	nonceAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	nonceLength   = 17
+)
+
+var (
+	// ErrUnauthenticated được trả về khi thiếu phiên hoặc phiên không hợp lệ/đã hết hạn.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden được trả về khi IDUser không khớp với tài khoản đã chứng minh trong phiên.
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidProof được trả về khi message/chữ ký chứng minh quyền sở hữu không hợp lệ.
+	ErrInvalidProof = errors.New("invalid ownership proof")
+)
+
+// AuthConfig là cấu hình đăng nhập, đọc từ env:
+//   - SIWF_DOMAIN: domain mà message SIWF phải khai báo (chống dùng lại message ký cho site khác); để trống thì
+//     tắt các route SIWF
+//   - SIWF_NONCE_TTL: thời hạn của nonce (mặc định 5m)
+//   - SESSION_TTL: thời hạn của phiên (mặc định 24h)
+//   - REQUIRE_PROVEN_FID: bắt buộc phiên SIWF cho các kiểm tra follow trên Farcaster (mặc định bật khi có
+//     SIWF_DOMAIN, đặt false để tắt; bật mà thiếu SIWF_DOMAIN là lỗi)
+//   - X_CHALLENGE_TTL: thời hạn của mã challenge X (mặc định 30m)
+//   - REQUIRE_PROVEN_X: bắt buộc phiên đã chứng minh handle X cho các kiểm tra follow trên X (mặc định bật khi
+//     X_PROVIDER có provider resolve được user ID, tức "api"; đặt false để tắt; bật mà không có provider đó là lỗi)
+//   - OAUTH_STATE_TTL: thời hạn của state trong luồng cấp quyền OAuth (mặc định 10m)
+type AuthConfig struct {
+	Domain           string
+	NonceTTL         time.Duration
+	SessionTTL       time.Duration
+	RequireProvenFID bool
+	XChallengeTTL    time.Duration
+	RequireProvenX   bool
+	OAuthStateTTL    time.Duration
+}
+
+// LoadAuthConfig đọc AuthConfig từ env.
+func LoadAuthConfig() (AuthConfig, error) {
+	cfg := AuthConfig{
+		Domain:        strings.TrimSpace(os.Getenv("SIWF_DOMAIN")),
+		NonceTTL:      defaultNonceTTL,
+		SessionTTL:    defaultSessionTTL,
+		XChallengeTTL: defaultXChallengeTTL,
+		OAuthStateTTL: defaultOAuthStateTTL,
+	}
+	var err error
+	if cfg.RequireProvenFID, err = envFlag("REQUIRE_PROVEN_FID", cfg.SIWFEnabled()); err != nil {
+		return cfg, err
+	}
+	if cfg.RequireProvenFID && !cfg.SIWFEnabled() {
+		return cfg, errors.New("REQUIRE_PROVEN_FID needs SIWF_DOMAIN: set it to enable Sign In With Farcaster or set REQUIRE_PROVEN_FID=false")
+	}
+	if cfg.RequireProvenX, err = envFlag("REQUIRE_PROVEN_X", twitter.ResolvesUserIDs()); err != nil {
+		return cfg, err
+	}
+	if cfg.RequireProvenX && !twitter.ResolvesUserIDs() {
+		return cfg, errors.New(`REQUIRE_PROVEN_X needs an X provider that resolves user ids: add "api" to X_PROVIDER or set REQUIRE_PROVEN_X=false`)
+	}
+	for env, dst := range map[string]*time.Duration{
+		"SIWF_NONCE_TTL":  &cfg.NonceTTL,
+		"SESSION_TTL":     &cfg.SessionTTL,
+		"X_CHALLENGE_TTL": &cfg.XChallengeTTL,
+		"OAUTH_STATE_TTL": &cfg.OAuthStateTTL,
+	} {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid %s: %w", env, err)
+			}
+			*dst = d
+		}
+	}
+	return cfg, nil
+}
+
+// SIWFEnabled cho biết Sign In With Farcaster có được bật (đã đặt SIWF_DOMAIN) hay không.
+func (c AuthConfig) SIWFEnabled() bool {
+	return c.Domain != ""
+}
+
+// envFlag đọc biến môi trường bật/tắt, trả về def khi biến không được đặt.
+func envFlag(name string, def bool) (bool, error) {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv(name)))
+	switch v {
+	case "":
+		return def, nil
+	case "1", "true", "yes", "y", "on":
+		return true, nil
+	case "0", "false", "no", "n", "off":
+		return false, nil
+	}
+	return def, fmt.Errorf("invalid %s: %q", name, v)
+}
+
+// AuthService định nghĩa interface đăng nhập bằng chứng minh quyền sở hữu tài khoản.
+type AuthService interface {
+	SIWFEnabled() bool
+	IssueNonce() (model.NonceResponse, error)
+	SignInWithFarcaster(req model.SIWFVerifyRequest) (model.Session, error)
+	GetSession(token string) (model.Session, error)
+	IssueXChallenge(req model.XChallengeRequest) (model.XChallengeResponse, error)
+	VerifyXChallenge(req model.XChallengeVerifyRequest) (model.Session, error)
+	StartOAuth(platform string, req model.OAuthStartRequest) (model.OAuthStartResponse, error)
+	CompleteOAuth(platform string, req model.OAuthCallbackRequest) (model.Session, error)
+}
+
+// authService là implementation của AuthService.
+type authService struct {
+	cfg        AuthConfig
+	store      auth.Store
+	identities identity.Store
+	// fetchXTexts lấy bio và các tweet gần đây của một handle X
+	fetchXTexts func(handle string) ([]string, error)
+}
+
+// NewAuthService tạo một instance mới của authService. Tài khoản được chứng minh kèm user_id
+// sẽ được liên kết vào identities.
+func NewAuthService(cfg AuthConfig, store auth.Store, identities identity.Store) AuthService {
+	return &authService{cfg: cfg, store: store, identities: identities, fetchXTexts: twitter.FetchProfileTexts}
+}
+
+// SIWFEnabled implements AuthService.
+func (s *authService) SIWFEnabled() bool {
+	return s.cfg.SIWFEnabled()
+}
+
+// IssueNonce tạo nonce dùng một lần cho message SIWF.
+func (s *authService) IssueNonce() (model.NonceResponse, error) {
+	nonce, err := randomAlphanumeric(nonceLength)
+	if err != nil {
+		return model.NonceResponse{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.store.SaveNonce(ctx, nonce, s.cfg.NonceTTL); err != nil {
+		return model.NonceResponse{}, err
+	}
+	return model.NonceResponse{Nonce: nonce, Domain: s.cfg.Domain, ExpiresAt: time.Now().Add(s.cfg.NonceTTL)}, nil
+}
+
+// randomAlphanumeric tạo chuỗi ngẫu nhiên gồm n chữ và số.
+func randomAlphanumeric(n int) (string, error) {
+	random := make([]byte, n)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	out := make([]byte, n)
+	for i, b := range random {
+		out[i] = nonceAlphabet[int(b)%len(nonceAlphabet)]
+	}
+	return string(out), nil
+}
+
+// SignInWithFarcaster xác minh message SIWF đã ký và tạo phiên gắn với FID đã chứng minh.
+func (s *authService) SignInWithFarcaster(req model.SIWFVerifyRequest) (model.Session, error) {
+	msg, err := farcaster.ParseSIWFMessage(req.Message)
+	if err != nil {
+		return model.Session{}, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// Nonce bị tiêu thụ trước khi kiểm tra chữ ký để không thể thử lại cùng nonce
+	ok, err := s.store.ConsumeNonce(ctx, msg.Nonce)
+	if err != nil {
+		return model.Session{}, err
+	}
+	if !ok {
+		return model.Session{}, fmt.Errorf("%w: unknown or expired nonce", ErrInvalidProof)
+	}
+
+	now := time.Now()
+	msg, err = farcaster.VerifySIWF(ctx, req.Message, req.Signature, farcaster.SIWFExpectations{
+		Domain: s.cfg.Domain,
+		Nonce:  msg.Nonce,
+		Now:    now,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, farcaster.ErrInvalidSIWFMessage),
+			errors.Is(err, farcaster.ErrSIWFSignatureMismatch),
+			errors.Is(err, farcaster.ErrAddressNotOwned):
+			return model.Session{}, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+		}
+		return model.Session{}, wrapFarcasterError(err)
+	}
+
+	session, err := s.newSession(now)
+	if err != nil {
+		return model.Session{}, err
+	}
+	session.Accounts["farcaster"] = strconv.FormatInt(msg.FID, 10)
+	session.Address = strings.ToLower(msg.Address)
+	if err := s.store.SaveSession(ctx, session); err != nil {
+		return model.Session{}, err
+	}
+	return session, nil
+}
+
+// newSession tạo phiên rỗng với token ngẫu nhiên.
+func (s *authService) newSession(now time.Time) (model.Session, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return model.Session{}, err
+	}
+	return model.Session{
+		Token:     hex.EncodeToString(token),
+		Accounts:  map[string]string{},
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.cfg.SessionTTL),
+	}, nil
+}
+
+// GetSession trả về phiên theo token.
+func (s *authService) GetSession(token string) (model.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return loadSession(ctx, s.store, token)
+}
+
+// loadSession lấy phiên theo token; thiếu token hoặc phiên không tồn tại/hết hạn là ErrUnauthenticated.
+func loadSession(ctx context.Context, store auth.Store, token string) (model.Session, error) {
+	if token == "" {
+		return model.Session{}, fmt.Errorf("%w: missing bearer token", ErrUnauthenticated)
+	}
+	session, err := store.GetSession(ctx, token)
+	if errors.Is(err, auth.ErrSessionNotFound) {
+		return model.Session{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	return session, err
+}
+
+// IssueXChallenge tạo mã mà người dùng phải tweet hoặc đặt vào bio của handle.
+func (s *authService) IssueXChallenge(req model.XChallengeRequest) (model.XChallengeResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	account, err := canonicalAccountID(ctx, "x", req.Handle)
+	if err != nil {
+		return model.XChallengeResponse{}, err
+	}
+	handle, err := twitter.ResolveUsername(ctx, req.Handle)
+	if err != nil {
+		return model.XChallengeResponse{}, wrapTwitterError(err)
+	}
+	suffix, err := randomAlphanumeric(xChallengeCodeLength)
+	if err != nil {
+		return model.XChallengeResponse{}, err
+	}
+	secret, err := randomAlphanumeric(xChallengeSecretLength)
+	if err != nil {
+		return model.XChallengeResponse{}, err
+	}
+	var session model.Session
+	if req.SessionToken != "" {
+		if session, err = s.GetSession(req.SessionToken); err != nil {
+			return model.XChallengeResponse{}, err
+		}
+	}
+	if req.UserID != "" {
+		// Kiểm tra sớm; VerifyXChallenge kiểm tra lại với handle đã được chứng minh
+		if _, err := linkTarget(ctx, s.identities, session, req.UserID); err != nil {
+			return model.XChallengeResponse{}, err
+		}
+	}
+
+	challenge := auth.Challenge{
+		Code:         xChallengeCodePrefix + suffix,
+		Platform:     "x",
+		Account:      account,
+		UserID:       req.UserID,
+		SessionToken: req.SessionToken,
+		SecretHash:   hashSecret(secret),
+		ExpiresAt:    time.Now().Add(s.cfg.XChallengeTTL),
+	}
+	if err := s.store.SaveChallenge(ctx, challenge); err != nil {
+		return model.XChallengeResponse{}, err
+	}
+	return model.XChallengeResponse{
+		Code:      challenge.Code,
+		Handle:    handle,
+		Text:      fmt.Sprintf("Verifying my account: %s", challenge.Code),
+		Secret:    secret,
+		ExpiresAt: challenge.ExpiresAt,
+	}, nil
+}
+
+// hashSecret trả về SHA-256 (hex) của secret; store chỉ lưu hash.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyXChallenge kiểm tra mã đã xuất hiện trong bio hoặc tweet gần đây, rồi gắn handle vào phiên
+// (tạo phiên mới nếu người gọi chưa đăng nhập) và vào user_id của challenge nếu có. Mã được đăng công khai
+// nên người gọi phải gửi secret của challenge và cùng phiên (hoặc cùng không có phiên) với người yêu cầu.
+func (s *authService) VerifyXChallenge(req model.XChallengeVerifyRequest) (model.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	challenge, err := s.store.GetChallenge(ctx, req.Code)
+	if errors.Is(err, auth.ErrChallengeNotFound) {
+		return model.Session{}, fmt.Errorf("%w: unknown or expired challenge", ErrInvalidProof)
+	}
+	if err != nil {
+		return model.Session{}, err
+	}
+	if challenge.SecretHash == "" || subtle.ConstantTimeCompare([]byte(challenge.SecretHash), []byte(hashSecret(req.Secret))) != 1 {
+		return model.Session{}, fmt.Errorf("%w: challenge secret does not match", ErrForbidden)
+	}
+	if challenge.SessionToken != req.SessionToken {
+		return model.Session{}, fmt.Errorf("%w: challenge was issued to another session", ErrForbidden)
+	}
+
+	texts, err := s.fetchXTexts(challenge.Account)
+	if err != nil {
+		return model.Session{}, err
+	}
+	found := false
+	for _, text := range texts {
+		if strings.Contains(text, challenge.Code) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		// Challenge được giữ lại để người dùng thử lại sau khi tweet
+		return model.Session{}, fmt.Errorf("%w: code not found in bio or recent tweets of x account %s", ErrInvalidProof, challenge.Account)
+	}
+	if err := s.store.DeleteChallenge(ctx, challenge.Code); err != nil {
+		return model.Session{}, err
+	}
+
+	var session model.Session
+	if challenge.SessionToken != "" {
+		if session, err = s.GetSession(challenge.SessionToken); err != nil {
+			return model.Session{}, err
+		}
+	} else if session, err = s.newSession(time.Now()); err != nil {
+		return model.Session{}, err
+	}
+	// Handle chỉ được gắn vào phiên khi liên kết thành công
+	if err := s.linkSessionAccount(ctx, session, challenge.UserID, "x", challenge.Account); err != nil {
+		return model.Session{}, err
+	}
+	session.Accounts["x"] = challenge.Account
+	if err := s.store.SaveSession(ctx, session); err != nil {
+		return model.Session{}, err
+	}
+	return session, nil
+}
+
+// linkSessionAccount liên kết tài khoản vừa được chứng minh trong phiên với userID (nếu có). userID phải là
+// identity của phiên hoặc một user ID mới (xem linkTarget), không thể là user của người khác.
+func (s *authService) linkSessionAccount(ctx context.Context, session model.Session, userID string, platform string, account string) error {
+	if userID == "" {
+		return nil
+	}
+	target, err := linkTarget(ctx, s.identities, session, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.identities.Link(ctx, target, platform, account); err != nil {
+		return wrapIdentityError(err)
+	}
+	return nil
+}
+
+// proofChecker bọc một Checker và yêu cầu tài khoản đã chứng minh (SIWF cho Farcaster, challenge cho X)
+// cho các kiểm tra follow.
+type proofChecker struct {
+	Checker
+	store auth.Store
+	// required: platform -> bắt buộc phải có phiên
+	required map[string]bool
+}
+
+// NewProofChecker tạo Checker đối chiếu IDUser với tài khoản đã chứng minh trong phiên. Với nền tảng
+// không bắt buộc, request không có phiên vẫn được kiểm tra như trước; request có phiên luôn được đối chiếu.
+func NewProofChecker(inner Checker, store auth.Store, cfg AuthConfig) Checker {
+	return &proofChecker{
+		Checker:  inner,
+		store:    store,
+		required: map[string]bool{"farcaster": cfg.RequireProvenFID, "x": cfg.RequireProvenX},
+	}
+}
+
+// CheckSocialAction đối chiếu phiên rồi gọi Checker bên trong.
+func (p *proofChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if !needsOwnershipProof(req.Social, req.Action) {
+		return p.Checker.CheckSocialAction(req)
+	}
+	if req.SessionToken == "" {
+		if p.required[req.Social] {
+			return model.SocialActionResponse{}, fmt.Errorf("%w: prove ownership of your %s account first", ErrUnauthenticated, req.Social)
+		}
+		return p.Checker.CheckSocialAction(req)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	session, err := p.store.GetSession(ctx, req.SessionToken)
+	if errors.Is(err, auth.ErrSessionNotFound) {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	if err != nil {
+		return model.SocialActionResponse{}, err
+	}
+	proven := session.Accounts[req.Social]
+	if proven == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: session has no proven %s account", ErrForbidden, req.Social)
+	}
+
+	if req.IDUser == "" {
+		req.IDUser = proven
+	} else {
+		account, err := canonicalAccountID(ctx, req.Social, req.IDUser)
+		if err != nil {
+			return model.SocialActionResponse{}, err
+		}
+		if account != proven {
+			return model.SocialActionResponse{}, fmt.Errorf("%w: %s is not the %s account proven by the session", ErrForbidden, req.IDUser, req.Social)
+		}
+	}
+	return p.Checker.CheckSocialAction(req)
+}
+
+// needsOwnershipProof cho biết kiểm tra có dựa vào quyền sở hữu IDUser không (các kiểm tra follow).
+func needsOwnershipProof(social string, action string) bool {
+	switch social {
+	case "farcaster", "x":
+		switch action {
+		case farcaster.RelationFollow, farcaster.RelationFollowedBy, farcaster.RelationMutual:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/identity"
+	"checkingsocial/internal/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyXChallengeLinksOnlySessionIdentity(t *testing.T) {
+	ctx := context.Background()
+	sessions := auth.NewMemoryStore()
+	identities := identity.NewMemoryStore()
+	if err := identities.Link(ctx, "u-alice", "github", "alice"); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	newTestSession(t, sessions, "alice", map[string]string{"github": "alice"})
+
+	svc := &authService{
+		cfg:        AuthConfig{SessionTTL: time.Hour},
+		store:      sessions,
+		identities: identities,
+		fetchXTexts: func(handle string) ([]string, error) {
+			return []string{"Verifying my account: CODE-" + handle}, nil
+		},
+	}
+	issue := func(account, userID, token string) {
+		err := sessions.SaveChallenge(ctx, auth.Challenge{
+			Code:         "CODE-" + account,
+			Platform:     "x",
+			Account:      account,
+			UserID:       userID,
+			SessionToken: token,
+			SecretHash:   hashSecret("secret-" + account),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("save challenge: %v", err)
+		}
+	}
+	redeem := func(account, secret, token string) error {
+		_, err := svc.VerifyXChallenge(model.XChallengeVerifyRequest{Code: "CODE-" + account, Secret: secret, SessionToken: token})
+		return err
+	}
+	verify := func(account, userID, token string) error {
+		issue(account, userID, token)
+		return redeem(account, "secret-"+account, token)
+	}
+
+	if err := verify("1", "u-mallory", "alice"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("link into a user other than the session's: got %v, want ErrForbidden", err)
+	}
+	if session, _ := sessions.GetSession(ctx, "alice"); session.Accounts["x"] != "" {
+		t.Fatalf("x account %q kept in the session after a failed link", session.Accounts["x"])
+	}
+	if err := verify("2", "u-alice", ""); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("link into an existing user without a session: got %v, want ErrForbidden", err)
+	}
+	if err := verify("3", "u-alice", "alice"); err != nil {
+		t.Fatalf("link into the session's own user: %v", err)
+	}
+	if owner, err := identities.FindUser(ctx, "x", "3"); err != nil || owner != "u-alice" {
+		t.Fatalf("owner of x account = %q, %v; want u-alice", owner, err)
+	}
+
+	// The code of a session-less challenge is public once tweeted; without the issuer's secret nobody can
+	// redeem it into a new session or link it
+	issue("4", "u-new", "")
+	if err := redeem("4", "", ""); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("redeem someone else's challenge without its secret: got %v, want ErrForbidden", err)
+	}
+	if err := redeem("4", "guessed", "mallory"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("redeem someone else's challenge with a wrong secret: got %v, want ErrForbidden", err)
+	}
+	if owner, err := identities.FindUser(ctx, "x", "4"); err == nil {
+		t.Fatalf("x account linked to %q by a rejected redeem", owner)
+	}
+	if err := redeem("4", "secret-4", ""); err != nil {
+		t.Fatalf("issuer redeems with the secret: %v", err)
+	}
+}
+
+func TestCompleteOAuthLinksOnlySessionIdentity(t *testing.T) {
+	ctx := context.Background()
+	sessions := auth.NewMemoryStore()
+	identities := identity.NewMemoryStore()
+	if err := identities.Link(ctx, "u-alice", "github", "alice"); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	newTestSession(t, sessions, "alice", map[string]string{"github": "alice"})
+	svc := &authService{cfg: AuthConfig{SessionTTL: time.Hour, OAuthStateTTL: time.Hour}, store: sessions, identities: identities}
+
+	if _, err := svc.newOAuthState(ctx, "youtube", model.OAuthStartRequest{UserID: "u-alice"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("state for an existing user without a session: got %v, want ErrForbidden", err)
+	}
+
+	complete := func(account, userID, token string) error {
+		_, err := svc.completeOAuth(ctx, auth.Challenge{Platform: "youtube", UserID: userID, SessionToken: token},
+			auth.OAuthToken{Platform: "youtube", Account: account, AccessToken: "t", ExpiresAt: time.Now().Add(time.Hour)})
+		return err
+	}
+	if err := complete("UC1", "u-mallory", "alice"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("link into a user other than the session's: got %v, want ErrForbidden", err)
+	}
+	if err := complete("UC2", "u-alice", "alice"); err != nil {
+		t.Fatalf("link into the session's own user: %v", err)
+	}
+	if owner, err := identities.FindUser(ctx, "youtube", "UC2"); err != nil || owner != "u-alice" {
+		t.Fatalf("owner of youtube account = %q, %v; want u-alice", owner, err)
+	}
+}
+
+func TestLoadAuthConfigSIWFDomain(t *testing.T) {
+	cases := []struct {
+		name, domain, requireFID string
+		wantErr, wantSIWF, want  bool
+	}{
+		{name: "siwf disabled", domain: "", requireFID: "", wantSIWF: false, want: false},
+		{name: "proven fid without domain", domain: "", requireFID: "true", wantErr: true},
+		{name: "siwf enabled", domain: "app.example.com", requireFID: "", wantSIWF: true, want: true},
+		{name: "siwf without proven fid", domain: "app.example.com", requireFID: "false", wantSIWF: true, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("SIWF_DOMAIN", tc.domain)
+			t.Setenv("REQUIRE_PROVEN_FID", tc.requireFID)
+			cfg, err := LoadAuthConfig()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("got nil error, want REQUIRE_PROVEN_FID without SIWF_DOMAIN rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if cfg.SIWFEnabled() != tc.wantSIWF || cfg.RequireProvenFID != tc.want {
+				t.Fatalf("SIWFEnabled=%v RequireProvenFID=%v, want %v and %v", cfg.SIWFEnabled(), cfg.RequireProvenFID, tc.wantSIWF, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadAuthConfigProvenX(t *testing.T) {
+	cases := []struct {
+		name, provider, requireX string
+		wantErr, want            bool
+	}{
+		{name: "apify only", provider: "", requireX: "", want: false},
+		{name: "proven x with apify only", provider: "apify", requireX: "true", wantErr: true},
+		{name: "api provider", provider: "apify,api", requireX: "", want: true},
+		{name: "api provider, proven x off", provider: "api", requireX: "false", want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("SIWF_DOMAIN", "app.example.com")
+			t.Setenv("X_PROVIDER", tc.provider)
+			t.Setenv("REQUIRE_PROVEN_X", tc.requireX)
+			cfg, err := LoadAuthConfig()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("got nil error, want REQUIRE_PROVEN_X without an id-capable provider rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if cfg.RequireProvenX != tc.want {
+				t.Fatalf("RequireProvenX = %v, want %v", cfg.RequireProvenX, tc.want)
+			}
+		})
+	}
+}
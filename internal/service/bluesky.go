@@ -0,0 +1,60 @@
+package service
+
+import (
+	"checkingsocial/bluesky"
+	"checkingsocial/internal/model"
+	"errors"
+	"fmt"
+)
+
+// checkBlueskyFollow kiểm tra IDUser (handle, DID hoặc URL profile) có follow req.Target (mặc định BLUESKY_TARGET).
+func checkBlueskyFollow(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	res, err := bluesky.CheckFollow(req.IDUser, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapBlueskyError(err)
+	}
+	return model.SocialActionResponse{
+		Result: res.Following,
+		Source: res.Source,
+		Relationship: &model.Relationship{
+			Following:  res.Following,
+			FollowedBy: res.FollowedBy,
+			Blocked:    &res.Blocked,
+			BlockedBy:  &res.BlockedBy,
+		},
+	}, nil
+}
+
+// checkBlueskyEngagement kiểm tra IDUser đã like hoặc repost bài req.Target (at:// URI hoặc URL bsky.app).
+// Bài có quá nhiều like/repost để quét hết trả về Status "unverifiable".
+func checkBlueskyEngagement(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if req.Target == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: target post is required", ErrInvalidTarget)
+	}
+	res, err := bluesky.CheckEngagement(req.IDUser, req.Target, req.Action)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapBlueskyError(err)
+	}
+	resp := model.SocialActionResponse{Result: res.Done, Source: res.Source}
+	if !res.Verifiable {
+		resp.Status, resp.Reason = model.StatusUnverifiable, res.Reason
+	}
+	return resp, nil
+}
+
+// wrapBlueskyError chuyển lỗi của package bluesky sang lỗi của service để handler map status code.
+func wrapBlueskyError(err error) error {
+	switch {
+	case errors.Is(err, bluesky.ErrInvalidTarget):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, bluesky.ErrTargetNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, bluesky.ErrInvalidIdentifier):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, bluesky.ErrUserNotFound):
+		return fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	case errors.Is(err, bluesky.ErrRateLimited):
+		return wrapRateLimit(err)
+	}
+	return err
+}
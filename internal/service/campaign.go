@@ -0,0 +1,194 @@
+package service
+
+import (
+	"checkingsocial/internal/campaign"
+	"checkingsocial/internal/model"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidCampaign được trả về khi định nghĩa campaign không hợp lệ.
+	ErrInvalidCampaign = errors.New("invalid campaign")
+	// ErrCampaignNotFound được trả về khi không tìm thấy campaign.
+	ErrCampaignNotFound = errors.New("campaign not found")
+	// ErrCampaignExists được trả về khi tạo campaign với ID đã tồn tại.
+	ErrCampaignExists = errors.New("campaign already exists")
+	// ErrCampaignInactive được trả về khi verify ngoài khoảng start_at/end_at của campaign.
+	ErrCampaignInactive = errors.New("campaign is not active")
+)
+
+// CampaignService định nghĩa interface quản lý và verify campaign.
+type CampaignService interface {
+	CreateCampaign(c model.Campaign) (model.Campaign, error)
+	GetCampaign(id string) (model.Campaign, error)
+	ListCampaigns() ([]model.Campaign, error)
+	VerifyCampaign(id string, req model.CampaignVerifyRequest) (model.CampaignVerifyResponse, error)
+}
+
+// campaignService là implementation của CampaignService.
+type campaignService struct {
+	checker Checker
+	store   campaign.Store
+}
+
+// NewCampaignService tạo CampaignService. Các task được kiểm tra qua checker nên cũng đi qua ledger/tracking.
+func NewCampaignService(checker Checker, store campaign.Store) CampaignService {
+	return &campaignService{checker: checker, store: store}
+}
+
+// CreateCampaign kiểm tra và lưu campaign; ID campaign và task được tự sinh nếu để trống.
+// Campaign đã tồn tại không bị ghi đè.
+func (s *campaignService) CreateCampaign(c model.Campaign) (model.Campaign, error) {
+	if c.ID == "" {
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		c.ID = hex.EncodeToString(b)
+	}
+	if len(c.Tasks) == 0 {
+		return model.Campaign{}, fmt.Errorf("%w: at least one task is required", ErrInvalidCampaign)
+	}
+	if c.StartAt != nil && c.EndAt != nil && !c.EndAt.After(*c.StartAt) {
+		return model.Campaign{}, fmt.Errorf("%w: end_at must be after start_at", ErrInvalidCampaign)
+	}
+
+	seen := map[string]bool{}
+	tasks := make([]model.CampaignTask, len(c.Tasks))
+	for i, t := range c.Tasks {
+		if t.ID == "" {
+			t.ID = strconv.Itoa(i + 1)
+		}
+		if t.Social == "" || t.Action == "" {
+			return model.Campaign{}, fmt.Errorf("%w: task %s needs social and action", ErrInvalidCampaign, t.ID)
+		}
+		if _, ok := socialActions[t.Social][t.Action]; !ok {
+			return model.Campaign{}, fmt.Errorf("%w: task %s: unsupported social or action %s/%s", ErrInvalidCampaign, t.ID, t.Social, t.Action)
+		}
+		if seen[t.ID] {
+			return model.Campaign{}, fmt.Errorf("%w: duplicate task id %s", ErrInvalidCampaign, t.ID)
+		}
+		seen[t.ID] = true
+		tasks[i] = t
+	}
+	c.Tasks = tasks
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := s.store.Create(ctx, c)
+	if errors.Is(err, campaign.ErrConflict) {
+		return model.Campaign{}, fmt.Errorf("%w: %s", ErrCampaignExists, c.ID)
+	}
+	if err != nil {
+		return model.Campaign{}, err
+	}
+	return c, nil
+}
+
+// GetCampaign trả về campaign theo ID.
+func (s *campaignService) GetCampaign(id string) (model.Campaign, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := s.store.Get(ctx, id)
+	if errors.Is(err, campaign.ErrNotFound) {
+		return model.Campaign{}, fmt.Errorf("%w: %s", ErrCampaignNotFound, id)
+	}
+	return c, err
+}
+
+// ListCampaigns trả về mọi campaign.
+func (s *campaignService) ListCampaigns() ([]model.Campaign, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.store.List(ctx)
+}
+
+// VerifyCampaign kiểm tra mọi task của campaign cho người dùng có định danh req.Identities
+// (hoặc các tài khoản đã liên kết với req.UserID).
+// Campaign không có thứ tự được kiểm tra song song; campaign có thứ tự dừng ở task đầu tiên chưa hoàn thành.
+func (s *campaignService) VerifyCampaign(id string, req model.CampaignVerifyRequest) (model.CampaignVerifyResponse, error) {
+	c, err := s.GetCampaign(id)
+	if err != nil {
+		return model.CampaignVerifyResponse{}, err
+	}
+
+	now := time.Now()
+	if c.StartAt != nil && now.Before(*c.StartAt) {
+		return model.CampaignVerifyResponse{}, fmt.Errorf("%w: starts at %s", ErrCampaignInactive, c.StartAt.Format(time.RFC3339))
+	}
+	if c.EndAt != nil && now.After(*c.EndAt) {
+		return model.CampaignVerifyResponse{}, fmt.Errorf("%w: ended at %s", ErrCampaignInactive, c.EndAt.Format(time.RFC3339))
+	}
+
+	results := make([]model.CampaignTaskResult, len(c.Tasks))
+	if c.Ordered {
+		for i, t := range c.Tasks {
+			if i > 0 && results[i-1].Status != model.TaskStatusCompleted {
+				results[i] = taskResult(t, model.TaskStatusLocked)
+				continue
+			}
+			results[i] = s.verifyTask(c.ID, t, req)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i, t := range c.Tasks {
+			wg.Add(1)
+			go func(i int, t model.CampaignTask) {
+				defer wg.Done()
+				results[i] = s.verifyTask(c.ID, t, req)
+			}(i, t)
+		}
+		wg.Wait()
+	}
+
+	resp := model.CampaignVerifyResponse{CampaignID: c.ID, Total: len(results), Tasks: results}
+	for _, r := range results {
+		if r.Status == model.TaskStatusCompleted {
+			resp.Done++
+		}
+	}
+	resp.Completed = resp.Done == resp.Total
+	return resp, nil
+}
+
+// verifyTask kiểm tra một task bằng định danh của người dùng trên nền tảng của task.
+// IDUser bỏ trống khi chỉ có UserID để checker điền từ tài khoản đã liên kết.
+func (s *campaignService) verifyTask(campaignID string, t model.CampaignTask, req model.CampaignVerifyRequest) model.CampaignTaskResult {
+	idUser := req.Identities[t.Social]
+	if fallback, ok := accountFallbacks[t.Social]; ok && idUser == "" && req.Identities[fallback] != "" {
+		idUser = fallback + ":" + req.Identities[fallback]
+	}
+	if idUser == "" && req.UserID == "" {
+		res := taskResult(t, model.TaskStatusError)
+		res.Error = fmt.Sprintf("no iduser provided for %s", t.Social)
+		return res
+	}
+
+	actionReq := t.Request(idUser, campaignID)
+	actionReq.UserID = req.UserID
+	actionReq.SessionToken = req.SessionToken
+	resp, err := s.checker.CheckSocialAction(actionReq)
+	if err != nil {
+		res := taskResult(t, model.TaskStatusError)
+		res.Error = err.Error()
+		return res
+	}
+
+	res := taskResult(t, model.TaskStatusIncomplete)
+	if resp.Result {
+		res.Status = model.TaskStatusCompleted
+	}
+	res.Result = resp.Result
+	res.Detail = &resp
+	return res
+}
+
+// taskResult tạo kết quả rỗng của task với trạng thái status.
+func taskResult(t model.CampaignTask, status string) model.CampaignTaskResult {
+	return model.CampaignTaskResult{TaskID: t.ID, Social: t.Social, Action: t.Action, Target: t.Target, Status: status}
+}
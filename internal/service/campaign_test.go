@@ -0,0 +1,35 @@
+package service
+
+import (
+	"checkingsocial/internal/campaign"
+	"checkingsocial/internal/model"
+	"errors"
+	"testing"
+)
+
+func TestCreateCampaign(t *testing.T) {
+	svc := NewCampaignService(stubChecker{}, campaign.NewMemoryStore())
+	follow := model.CampaignTask{Social: "github", Action: "follow", Target: "octocat"}
+
+	if _, err := svc.CreateCampaign(model.Campaign{ID: "launch", Tasks: []model.CampaignTask{follow}}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	_, err := svc.CreateCampaign(model.Campaign{ID: "launch", Tasks: []model.CampaignTask{{Social: "github", Action: "star", Target: "octocat/hello"}}})
+	if !errors.Is(err, ErrCampaignExists) {
+		t.Fatalf("create with an existing id: got %v, want ErrCampaignExists", err)
+	}
+	got, err := svc.GetCampaign("launch")
+	if err != nil || got.Tasks[0].Action != "follow" {
+		t.Fatalf("existing campaign was overwritten: %+v, %v", got, err)
+	}
+
+	for _, task := range []model.CampaignTask{
+		{Social: "myspace", Action: "follow"},
+		{Social: "github", Action: "retweet"},
+	} {
+		_, err := svc.CreateCampaign(model.Campaign{Tasks: []model.CampaignTask{task}})
+		if !errors.Is(err, ErrInvalidCampaign) {
+			t.Fatalf("task %s/%s: got %v, want ErrInvalidCampaign", task.Social, task.Action, err)
+		}
+	}
+}
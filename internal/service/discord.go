@@ -0,0 +1,37 @@
+package service
+
+import (
+	"checkingsocial/discord"
+	"checkingsocial/internal/model"
+	"errors"
+	"fmt"
+)
+
+// discordSource là nguồn dữ liệu của các kiểm tra trên Discord.
+const discordSource = "discord_api"
+
+// checkDiscordMember kiểm tra người dùng (Discord user ID) đã vào guild (join_guild) hoặc có role (has_role).
+// Target của join_guild là guild ID; của has_role là role ID hoặc "<guild_id>/<role_id>".
+// Guild mặc định là DISCORD_GUILD_ID.
+func checkDiscordMember(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	res, err := discord.CheckMember(req.IDUser, req.Action, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapDiscordError(err)
+	}
+	return model.SocialActionResponse{Result: res.Done, Source: discordSource, Status: res.Status}, nil
+}
+
+// wrapDiscordError chuyển lỗi của package discord sang lỗi của service để handler map status code.
+func wrapDiscordError(err error) error {
+	switch {
+	case errors.Is(err, discord.ErrInvalidUserID):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, discord.ErrInvalidTarget):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, discord.ErrGuildNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, discord.ErrRateLimited):
+		return wrapRateLimit(err)
+	}
+	return err
+}
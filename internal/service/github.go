@@ -0,0 +1,42 @@
+package service
+
+import (
+	"checkingsocial/github"
+	"checkingsocial/internal/model"
+	"errors"
+	"fmt"
+)
+
+// githubSource là nguồn dữ liệu của các kiểm tra trên GitHub.
+const githubSource = "github_rest_api"
+
+// checkGitHubAction kiểm tra login IDUser đã star/fork repo req.Target (mặc định GITHUB_REPO) hoặc follow
+// user/org req.Target (mặc định GITHUB_ORG). Danh sách quá dài để quét hết trả về Status "unverifiable".
+func checkGitHubAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	res, err := github.CheckAction(req.IDUser, req.Action, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapGitHubError(err)
+	}
+	resp := model.SocialActionResponse{Result: res.Done, Source: githubSource}
+	if !res.Verifiable {
+		resp.Status, resp.Reason = model.StatusUnverifiable, res.Reason
+	}
+	return resp, nil
+}
+
+// wrapGitHubError chuyển lỗi của package github sang lỗi của service để handler map status code.
+func wrapGitHubError(err error) error {
+	switch {
+	case errors.Is(err, github.ErrInvalidLogin):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, github.ErrUserNotFound):
+		return fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	case errors.Is(err, github.ErrInvalidTarget):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, github.ErrTargetNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, github.ErrRateLimited):
+		return wrapRateLimit(err)
+	}
+	return err
+}
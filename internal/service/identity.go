@@ -0,0 +1,315 @@
+package service
+
+import (
+	"checkingsocial/bluesky"
+	"checkingsocial/farcaster"
+	"checkingsocial/github"
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/identity"
+	"checkingsocial/internal/model"
+	"checkingsocial/lens"
+	"checkingsocial/mastodon"
+	"checkingsocial/nostr"
+	"checkingsocial/onchain"
+	"checkingsocial/reddit"
+	"checkingsocial/twitter"
+	"checkingsocial/youtube"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrIdentityNotFound được trả về khi user ID chưa liên kết tài khoản nào.
+	ErrIdentityNotFound = errors.New("identity not found")
+	// ErrIdentityConflict được trả về khi tài khoản đã được liên kết với user ID khác.
+	ErrIdentityConflict = errors.New("identity conflict")
+)
+
+// IdentityService định nghĩa interface quản lý liên kết tài khoản giữa các nền tảng.
+type IdentityService interface {
+	GetIdentity(userID string) (model.Identity, error)
+	FindIdentity(platform string, accountID string) (model.Identity, error)
+	LinkAccount(userID string, req model.LinkAccountRequest) (model.Identity, error)
+	UnlinkAccount(userID string, platform string, sessionToken string) (model.Identity, error)
+}
+
+// identityService là implementation của IdentityService.
+type identityService struct {
+	store identity.Store
+	// sessions chứa các phiên đã chứng minh quyền sở hữu tài khoản; chỉ tài khoản đã chứng minh mới được liên kết
+	sessions auth.Store
+}
+
+// NewIdentityService tạo một instance mới của identityService.
+func NewIdentityService(store identity.Store, sessions auth.Store) IdentityService {
+	return &identityService{store: store, sessions: sessions}
+}
+
+// GetIdentity trả về các tài khoản đã liên kết với userID.
+func (s *identityService) GetIdentity(userID string) (model.Identity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	accounts, err := s.store.Accounts(ctx, userID)
+	if err != nil {
+		return model.Identity{}, wrapIdentityError(err)
+	}
+	return model.Identity{UserID: userID, Accounts: accounts}, nil
+}
+
+// FindIdentity trả về hồ sơ của user đã liên kết tài khoản accountID trên platform.
+func (s *identityService) FindIdentity(platform string, accountID string) (model.Identity, error) {
+	platform = normalizePlatform(platform)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	accountID, err := canonicalAccountID(ctx, platform, accountID)
+	if err != nil {
+		return model.Identity{}, err
+	}
+	userID, err := s.store.FindUser(ctx, platform, accountID)
+	if err != nil {
+		return model.Identity{}, wrapIdentityError(err)
+	}
+	return s.GetIdentity(userID)
+}
+
+// LinkAccount liên kết một tài khoản với userID. Tài khoản phải đã được chứng minh trong phiên
+// (SIWF, challenge X hoặc OAuth) và userID phải là identity của phiên (xem linkTarget).
+func (s *identityService) LinkAccount(userID string, req model.LinkAccountRequest) (model.Identity, error) {
+	platform := normalizePlatform(req.Platform)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	session, err := loadSession(ctx, s.sessions, req.SessionToken)
+	if err != nil {
+		return model.Identity{}, err
+	}
+	accountID, err := canonicalAccountID(ctx, platform, req.AccountID)
+	if err != nil {
+		return model.Identity{}, err
+	}
+	if session.Accounts[platform] != accountID {
+		return model.Identity{}, fmt.Errorf("%w: prove ownership of %s account %s first", ErrForbidden, platform, req.AccountID)
+	}
+	if _, err := linkTarget(ctx, s.store, session, userID); err != nil {
+		return model.Identity{}, err
+	}
+	if err := s.store.Link(ctx, userID, platform, accountID); err != nil {
+		return model.Identity{}, wrapIdentityError(err)
+	}
+	return s.GetIdentity(userID)
+}
+
+// UnlinkAccount xoá liên kết của userID trên platform. Chỉ phiên thuộc identity userID mới được xoá.
+func (s *identityService) UnlinkAccount(userID string, platform string, sessionToken string) (model.Identity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := loadSession(ctx, s.sessions, sessionToken)
+	if err != nil {
+		return model.Identity{}, err
+	}
+	owner, err := sessionIdentity(ctx, s.store, session)
+	if err != nil {
+		return model.Identity{}, err
+	}
+	if owner != userID {
+		return model.Identity{}, fmt.Errorf("%w: session does not belong to user %s", ErrForbidden, userID)
+	}
+	if err := s.store.Unlink(ctx, userID, normalizePlatform(platform)); err != nil {
+		return model.Identity{}, wrapIdentityError(err)
+	}
+	accounts, err := s.store.Accounts(ctx, userID)
+	if errors.Is(err, identity.ErrNotFound) {
+		return model.Identity{UserID: userID, Accounts: map[string]string{}}, nil
+	}
+	if err != nil {
+		return model.Identity{}, err
+	}
+	return model.Identity{UserID: userID, Accounts: accounts}, nil
+}
+
+// sessionIdentity trả về user ID mà các tài khoản đã chứng minh trong phiên đang được liên kết tới,
+// hoặc "" nếu chưa tài khoản nào được liên kết.
+func sessionIdentity(ctx context.Context, store identity.Store, session model.Session) (string, error) {
+	owner := ""
+	for platform, account := range session.Accounts {
+		userID, err := store.FindUser(ctx, platform, account)
+		if errors.Is(err, identity.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if owner != "" && owner != userID {
+			return "", fmt.Errorf("%w: session accounts are linked to users %s and %s", ErrIdentityConflict, owner, userID)
+		}
+		owner = userID
+	}
+	return owner, nil
+}
+
+// linkTarget chọn user ID mà phiên được phép liên kết tài khoản vào. Phiên đã có identity chỉ được liên kết
+// vào identity đó (userID rỗng nghĩa là identity của phiên); phiên chưa có identity chỉ được tạo userID mới
+// chưa liên kết tài khoản nào. Trả về "" khi không có gì để liên kết.
+func linkTarget(ctx context.Context, store identity.Store, session model.Session, userID string) (string, error) {
+	owner, err := sessionIdentity(ctx, store, session)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case owner != "" && (userID == "" || userID == owner):
+		return owner, nil
+	case owner != "":
+		return "", fmt.Errorf("%w: session belongs to user %s, not %s", ErrForbidden, owner, userID)
+	case userID == "":
+		return "", nil
+	}
+	_, err = store.Accounts(ctx, userID)
+	if errors.Is(err, identity.ErrNotFound) {
+		return userID, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%w: user %s already has accounts not proven by this session", ErrForbidden, userID)
+}
+
+// identityChecker bọc một Checker và điền IDUser từ tài khoản đã liên kết với UserID.
+type identityChecker struct {
+	Checker
+	store identity.Store
+}
+
+// NewIdentityChecker tạo Checker chấp nhận request chỉ có UserID nội bộ.
+func NewIdentityChecker(inner Checker, store identity.Store) Checker {
+	return &identityChecker{Checker: inner, store: store}
+}
+
+// CheckSocialAction điền IDUser (nếu trống) rồi gọi Checker bên trong.
+func (c *identityChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if req.IDUser == "" {
+		if req.UserID == "" {
+			return model.SocialActionResponse{}, fmt.Errorf("%w: iduser or user_id is required", ErrInvalidUser)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		accounts, err := c.store.Accounts(ctx, req.UserID)
+		cancel()
+		if errors.Is(err, identity.ErrNotFound) {
+			return model.SocialActionResponse{}, fmt.Errorf("%w: no accounts linked to user %s", ErrUserNotFound, req.UserID)
+		}
+		if err != nil {
+			return model.SocialActionResponse{}, err
+		}
+		platform := normalizePlatform(req.Social)
+		req.IDUser = accounts[platform]
+		if fallback, ok := accountFallbacks[platform]; ok && req.IDUser == "" && accounts[fallback] != "" {
+			req.IDUser = fallback + ":" + accounts[fallback]
+		}
+		if req.IDUser == "" {
+			return model.SocialActionResponse{}, fmt.Errorf("%w: no %s account linked to user %s", ErrInvalidUser, req.Social, req.UserID)
+		}
+	}
+	return c.Checker.CheckSocialAction(req)
+}
+
+// accountFallbacks: nền tảng -> nền tảng có tài khoản dùng thay khi user chưa liên kết tài khoản trên nền tảng
+// đầu. IDUser khi đó có dạng "<fallback>:<account>" (ví dụ "farcaster:<fid>" cho kiểm tra on-chain).
+var accountFallbacks = map[string]string{
+	string(model.Onchain): "farcaster",
+}
+
+// normalizePlatform chuẩn hoá tên nền tảng dùng làm khoá liên kết.
+func normalizePlatform(platform string) string {
+	return strings.ToLower(strings.TrimSpace(platform))
+}
+
+// canonicalAccountID chuẩn hoá định danh để một tài khoản chỉ có một dạng lưu trữ.
+func canonicalAccountID(ctx context.Context, platform string, accountID string) (string, error) {
+	accountID = strings.TrimSpace(accountID)
+	switch platform {
+	case "farcaster":
+		fid, err := farcaster.ResolveFID(ctx, accountID)
+		if err != nil {
+			return "", wrapFarcasterError(err)
+		}
+		return strconv.FormatInt(fid, 10), nil
+	case "x":
+		// Lưu user ID dạng số để liên kết không hỏng khi người dùng đổi handle; cần provider resolve được ID
+		account, err := twitter.CanonicalAccount(ctx, accountID)
+		if err != nil {
+			return "", wrapTwitterError(err)
+		}
+		return account, nil
+	case "bluesky":
+		// Lưu DID vì handle Bluesky có thể đổi
+		did, err := bluesky.ResolveDID(ctx, accountID)
+		if err != nil {
+			return "", wrapBlueskyError(err)
+		}
+		return did, nil
+	case "github":
+		// Login GitHub không phân biệt hoa thường
+		login, err := github.ParseLogin(accountID)
+		if err != nil {
+			return "", wrapGitHubError(err)
+		}
+		return login, nil
+	case "lens":
+		profileID, err := lens.ResolveProfileID(ctx, accountID)
+		if err != nil {
+			return "", wrapLensError(err)
+		}
+		return profileID, nil
+	case "onchain":
+		address, err := onchain.NormalizeAddress(accountID)
+		if err != nil {
+			return "", wrapOnchainError(err)
+		}
+		return address, nil
+	case "mastodon":
+		acct, err := mastodon.ParseAcct(accountID)
+		if err != nil {
+			return "", wrapMastodonError(err)
+		}
+		return acct, nil
+	case "nostr":
+		// Lưu public key dạng hex; npub và hex là cùng một tài khoản
+		pubKey, err := nostr.ParsePubKey(accountID)
+		if err != nil {
+			return "", wrapNostrError(err)
+		}
+		return pubKey, nil
+	case "reddit":
+		// Username Reddit không phân biệt hoa thường; chấp nhận "u/name" và link hồ sơ
+		username, err := reddit.ParseUsername(accountID)
+		if err != nil {
+			return "", wrapRedditError(err)
+		}
+		return username, nil
+	case "youtube":
+		channelID, err := youtube.ResolveChannelID(ctx, accountID)
+		switch {
+		case errors.Is(err, youtube.ErrInvalidChannel):
+			return "", fmt.Errorf("%w: %v", ErrInvalidUser, err)
+		case errors.Is(err, youtube.ErrChannelNotFound):
+			return "", fmt.Errorf("%w: %v", ErrUserNotFound, err)
+		case err != nil:
+			return "", err
+		}
+		return channelID, nil
+	}
+	return accountID, nil
+}
+
+// wrapIdentityError chuyển lỗi của package identity sang lỗi của service.
+func wrapIdentityError(err error) error {
+	switch {
+	case errors.Is(err, identity.ErrNotFound):
+		return fmt.Errorf("%w: %v", ErrIdentityNotFound, err)
+	case errors.Is(err, identity.ErrConflict):
+		return fmt.Errorf("%w: %v", ErrIdentityConflict, err)
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/identity"
+	"checkingsocial/internal/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestSession(t *testing.T, sessions auth.Store, token string, accounts map[string]string) {
+	t.Helper()
+	err := sessions.SaveSession(context.Background(), model.Session{
+		Token:     token,
+		Accounts:  accounts,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+}
+
+func TestLinkAccountRequiresProvenSession(t *testing.T) {
+	sessions := auth.NewMemoryStore()
+	svc := NewIdentityService(identity.NewMemoryStore(), sessions)
+	newTestSession(t, sessions, "alice", map[string]string{"github": "alice"})
+	newTestSession(t, sessions, "mallory", map[string]string{"github": "mallory"})
+
+	link := func(userID, account, token string) error {
+		_, err := svc.LinkAccount(userID, model.LinkAccountRequest{Platform: "github", AccountID: account, SessionToken: token})
+		return err
+	}
+
+	if err := link("u-alice", "alice", ""); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("link without session: got %v, want ErrUnauthenticated", err)
+	}
+	if err := link("u-mallory", "alice", "mallory"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("link of an unproven account: got %v, want ErrForbidden", err)
+	}
+	if err := link("u-alice", "Alice", "alice"); err != nil {
+		t.Fatalf("link of a proven account: %v", err)
+	}
+	if err := link("u-alice", "mallory", "mallory"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("link into another session's user: got %v, want ErrForbidden", err)
+	}
+	if err := link("u-other", "alice", "alice"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("re-link to a second user: got %v, want ErrForbidden", err)
+	}
+
+	if _, err := svc.UnlinkAccount("u-alice", "github", "mallory"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("unlink by another session: got %v, want ErrForbidden", err)
+	}
+	got, err := svc.UnlinkAccount("u-alice", "github", "alice")
+	if err != nil {
+		t.Fatalf("unlink by owner: %v", err)
+	}
+	if len(got.Accounts) != 0 {
+		t.Fatalf("accounts after unlink = %v, want none", got.Accounts)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/identity"
+	"checkingsocial/internal/ledger"
+	"checkingsocial/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Backend lưu ledger, chọn bằng LEDGER_BACKEND.
+const (
+	LedgerBackendSQLite = "sqlite"
+	LedgerBackendRedis  = "redis"
+)
+
+const defaultLedgerSQLitePath = "ledger.db"
+
+// LedgerConfig là cấu hình ledger, đọc từ env:
+//   - LEDGER_BACKEND: "sqlite" (mặc định) hoặc "redis"
+//   - LEDGER_SQLITE_PATH: đường dẫn file SQLite (mặc định "ledger.db")
+type LedgerConfig struct {
+	Backend    string
+	SQLitePath string
+}
+
+// LoadLedgerConfig đọc LedgerConfig từ env.
+func LoadLedgerConfig() (LedgerConfig, error) {
+	cfg := LedgerConfig{Backend: LedgerBackendSQLite, SQLitePath: defaultLedgerSQLitePath}
+	if v := strings.ToLower(strings.TrimSpace(os.Getenv("LEDGER_BACKEND"))); v != "" {
+		cfg.Backend = v
+	}
+	if v := strings.TrimSpace(os.Getenv("LEDGER_SQLITE_PATH")); v != "" {
+		cfg.SQLitePath = v
+	}
+	switch cfg.Backend {
+	case LedgerBackendSQLite, LedgerBackendRedis:
+		return cfg, nil
+	}
+	return cfg, fmt.Errorf("unknown LEDGER_BACKEND %q", cfg.Backend)
+}
+
+// ledgerChecker bọc một Checker và ghi mọi kết quả CheckSocialAction (kể cả lỗi) vào ledger.
+type ledgerChecker struct {
+	Checker
+	repo       ledger.Repository
+	identities identity.Store
+}
+
+// NewLedgerChecker tạo Checker ghi lại user, tài khoản, nền tảng, hành động, kết quả, provider và độ trễ của mỗi
+// lần kiểm tra. User nội bộ được tra từ identities theo tài khoản đã kiểm tra.
+func NewLedgerChecker(inner Checker, repo ledger.Repository, identities identity.Store) Checker {
+	return &ledgerChecker{Checker: inner, repo: repo, identities: identities}
+}
+
+// CheckSocialAction gọi Checker bên trong và ghi kết quả vào ledger.
+func (l *ledgerChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	start := time.Now()
+	resp, err := l.Checker.CheckSocialAction(req)
+
+	entry := ledger.Entry{
+		Account:    req.IDUser,
+		CampaignID: req.CampaignID,
+		Platform:   req.Social,
+		Action:     req.Action,
+		Target:     req.Target,
+		Result:     resp.Result,
+		Provider:   resp.Source,
+		LatencyMS:  time.Since(start).Milliseconds(),
+		CreatedAt:  start,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	entry.UserID = l.ledgerUser(ctx, req)
+	if recErr := l.repo.Record(ctx, entry); recErr != nil {
+		log.Printf("[Ledger][ERROR] record %s/%s iduser=%s: %v", req.Social, req.Action, req.IDUser, recErr)
+	}
+	return resp, err
+}
+
+// ledgerUser trả về user ID nội bộ sở hữu tài khoản của request, rỗng khi tài khoản chưa được liên kết.
+// Chủ tài khoản luôn được tra từ identities; UserID của request không được tin và chỉ được ghi log khi lệch.
+func (l *ledgerChecker) ledgerUser(ctx context.Context, req model.SocialActionRequest) string {
+	platform, account := normalizePlatform(req.Social), req.IDUser
+	if fallback, ok := accountFallbacks[platform]; ok && strings.HasPrefix(account, fallback+":") {
+		platform, account = fallback, strings.TrimPrefix(account, fallback+":")
+	}
+	account, err := canonicalAccountID(ctx, platform, account)
+	if err != nil {
+		return ""
+	}
+	owner, err := l.identities.FindUser(ctx, platform, account)
+	if err != nil && !errors.Is(err, identity.ErrNotFound) {
+		log.Printf("[Ledger][ERROR] find user of %s account %s: %v", platform, account, err)
+	}
+	if req.UserID != "" && req.UserID != owner {
+		log.Printf("[Ledger][WARN] user_id %s does not own %s account %s, recording owner %q", req.UserID, platform, account, owner)
+	}
+	return owner
+}
+
+// LedgerQuerier định nghĩa interface truy vấn ledger cho handler; mọi ledger.Repository đều thoả mãn.
+type LedgerQuerier interface {
+	Query(ctx context.Context, f ledger.Filter) ([]ledger.Entry, error)
+}
+
+// LedgerService định nghĩa interface truy vấn ledger thay mặt một phiên đăng nhập.
+type LedgerService interface {
+	// ListEntries trả về các entry của user sở hữu phiên sessionToken, lọc thêm theo f.
+	ListEntries(sessionToken string, f ledger.Filter) ([]ledger.Entry, error)
+}
+
+// ledgerService là implementation của LedgerService.
+type ledgerService struct {
+	querier    LedgerQuerier
+	identities identity.Store
+	sessions   auth.Store
+}
+
+// NewLedgerService tạo LedgerService. Người gọi chỉ xem được lịch sử của user mà phiên đã chứng minh.
+func NewLedgerService(querier LedgerQuerier, identities identity.Store, sessions auth.Store) LedgerService {
+	return &ledgerService{querier: querier, identities: identities, sessions: sessions}
+}
+
+// ListEntries implements LedgerService.
+func (s *ledgerService) ListEntries(sessionToken string, f ledger.Filter) ([]ledger.Entry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := loadSession(ctx, s.sessions, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := sessionIdentity(ctx, s.identities, session)
+	if err != nil {
+		return nil, err
+	}
+	if owner == "" {
+		return nil, fmt.Errorf("%w: no user is linked to the accounts of this session", ErrForbidden)
+	}
+	if f.UserID != "" && f.UserID != owner {
+		return nil, fmt.Errorf("%w: session belongs to user %s, not %s", ErrForbidden, owner, f.UserID)
+	}
+	f.UserID = owner
+	return s.querier.Query(ctx, f)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/identity"
+	"checkingsocial/internal/ledger"
+	"checkingsocial/internal/model"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// stubChecker trả về kết quả cố định cho CheckSocialAction.
+type stubChecker struct {
+	Checker
+	resp model.SocialActionResponse
+}
+
+func (s stubChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	return s.resp, nil
+}
+
+func TestLedgerRecordsInternalUser(t *testing.T) {
+	ctx := context.Background()
+	repo, err := ledger.NewSQLiteRepository(filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+	defer repo.Close()
+	identities := identity.NewMemoryStore()
+	if err := identities.Link(ctx, "u-alice", "github", "alice"); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+
+	checker := NewLedgerChecker(stubChecker{resp: model.SocialActionResponse{Result: true}}, repo, identities)
+	for _, idUser := range []string{"Alice", "bob"} {
+		if _, err := checker.CheckSocialAction(model.SocialActionRequest{Social: "github", Action: "follow", Target: "octocat", IDUser: idUser}); err != nil {
+			t.Fatalf("check %s: %v", idUser, err)
+		}
+	}
+	// user_id không sở hữu iduser: không được ghi check của bob vào lịch sử của u-alice
+	if _, err := checker.CheckSocialAction(model.SocialActionRequest{Social: "github", Action: "follow", Target: "octocat", IDUser: "bob", UserID: "u-alice"}); err != nil {
+		t.Fatalf("check bob as u-alice: %v", err)
+	}
+
+	entries, err := repo.Query(ctx, ledger.Filter{})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	for _, e := range entries {
+		if want := map[string]string{"Alice": "u-alice"}[e.Account]; e.UserID != want {
+			t.Fatalf("entry of %s recorded for user %q, want %q", e.Account, e.UserID, want)
+		}
+	}
+
+	sessions := auth.NewMemoryStore()
+	newTestSession(t, sessions, "alice", map[string]string{"github": "alice"})
+	newTestSession(t, sessions, "bob", map[string]string{"github": "bob"})
+	svc := NewLedgerService(repo, identities, sessions)
+
+	if _, err := svc.ListEntries("", ledger.Filter{}); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("without session: got %v, want ErrUnauthenticated", err)
+	}
+	if _, err := svc.ListEntries("bob", ledger.Filter{}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("session without a user: got %v, want ErrForbidden", err)
+	}
+	if _, err := svc.ListEntries("alice", ledger.Filter{UserID: "u-bob"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("another user's history: got %v, want ErrForbidden", err)
+	}
+	got, err := svc.ListEntries("alice", ledger.Filter{})
+	if err != nil {
+		t.Fatalf("own history: %v", err)
+	}
+	if len(got) != 1 || got[0].UserID != "u-alice" {
+		t.Fatalf("own history = %+v, want the u-alice entry only", got)
+	}
+}
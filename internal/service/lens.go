@@ -0,0 +1,50 @@
+package service
+
+import (
+	"checkingsocial/internal/model"
+	"checkingsocial/lens"
+	"errors"
+	"fmt"
+)
+
+// checkLensFollow kiểm tra profile IDUser có follow profile req.Target (mặc định LENS_TARGET_PROFILE).
+func checkLensFollow(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	res, err := lens.CheckFollow(req.IDUser, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapLensError(err)
+	}
+	return model.SocialActionResponse{
+		Result:       res.Following,
+		Source:       res.Source,
+		Relationship: &model.Relationship{Following: res.Following, FollowedBy: res.FollowedBy},
+	}, nil
+}
+
+// checkLensPublication kiểm tra profile IDUser đã mirror hoặc collect publication req.Target.
+func checkLensPublication(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if req.Target == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: target publication id is required", ErrInvalidTarget)
+	}
+	res, err := lens.CheckPublication(req.IDUser, req.Target, req.Action)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapLensError(err)
+	}
+	return model.SocialActionResponse{Result: res.Done, Source: res.Source}, nil
+}
+
+// wrapLensError chuyển lỗi của package lens sang lỗi của service, giống wrapFarcasterError.
+func wrapLensError(err error) error {
+	switch {
+	case errors.Is(err, lens.ErrInvalidIdentifier):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, lens.ErrUserNotFound):
+		return fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	case errors.Is(err, lens.ErrInvalidTarget), errors.Is(err, lens.ErrInvalidPublicationID):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, lens.ErrTargetNotFound), errors.Is(err, lens.ErrPublicationNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, lens.ErrRateLimited):
+		return wrapRateLimit(err)
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"checkingsocial/internal/model"
+	"checkingsocial/mastodon"
+	"errors"
+	"fmt"
+)
+
+// checkMastodonFollow kiểm tra IDUser (user@instance) đã follow req.Target (mặc định MASTODON_TARGET).
+// Instance ẩn danh sách following trả về Result=false với Status "unverifiable" thay vì một kết quả false.
+func checkMastodonFollow(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	res, err := mastodon.CheckFollow(req.IDUser, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapMastodonError(err)
+	}
+	resp := model.SocialActionResponse{Result: res.Following, Source: res.Source}
+	if !res.Verifiable {
+		resp.Result, resp.Status, resp.Reason = false, model.StatusUnverifiable, res.Reason
+	}
+	return resp, nil
+}
+
+// wrapMastodonError chuyển lỗi của package mastodon sang lỗi của service để handler map status code.
+func wrapMastodonError(err error) error {
+	switch {
+	case errors.Is(err, mastodon.ErrInvalidAcct):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, mastodon.ErrUserNotFound):
+		return fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	case errors.Is(err, mastodon.ErrInvalidTarget):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, mastodon.ErrTargetNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, mastodon.ErrRateLimited):
+		return wrapRateLimit(err)
+	}
+	return err
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"checkingsocial/internal/model"
+	"checkingsocial/nostr"
+	"errors"
+	"fmt"
+)
+
+// nostrSource là nguồn dữ liệu của các kiểm tra trên Nostr.
+const nostrSource = "nostr_relays"
+
+// checkNostrFollow kiểm tra contact list (kind 3) mới nhất của IDUser trên các relay có chứa req.Target
+// (mặc định NOSTR_TARGET_PUBKEY) hay không. Event sai chữ ký bị bỏ qua.
+func checkNostrFollow(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	res, err := nostr.CheckFollow(req.IDUser, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapNostrError(err)
+	}
+	resp := model.SocialActionResponse{Result: res.Following, Source: nostrSource}
+	if res.ContactListAt.IsZero() {
+		resp.Reason = fmt.Sprintf("no contact list found on %d relays", res.Relays)
+	}
+	return resp, nil
+}
+
+// wrapNostrError chuyển lỗi của package nostr sang lỗi của service để handler map status code.
+func wrapNostrError(err error) error {
+	switch {
+	case errors.Is(err, nostr.ErrInvalidTarget):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, nostr.ErrInvalidPubKey):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, nostr.ErrRelaysUnavailable):
+		return fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)
+	}
+	return err
+}
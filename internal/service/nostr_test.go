@@ -0,0 +1,25 @@
+package service
+
+import (
+	"checkingsocial/internal/model"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestNostrRelaysUnavailable(t *testing.T) {
+	// Một cổng vừa đóng: mọi relay đều từ chối kết nối
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	t.Setenv("NOSTR_RELAYS", "ws://"+addr)
+
+	pubkey := "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	_, err = checkNostrFollow(model.SocialActionRequest{Social: "nostr", Action: "follow", IDUser: pubkey, Target: pubkey})
+	if !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("got %v, want ErrUpstreamUnavailable", err)
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	oauthStateLength = 24
+	// tokenRefreshLeeway: access token sắp hết hạn trong khoảng này sẽ được refresh trước khi dùng
+	tokenRefreshLeeway = time.Minute
+)
+
+// ErrOAuthUnsupported được trả về khi nền tảng không hỗ trợ cấp quyền OAuth.
+var ErrOAuthUnsupported = errors.New("oauth not supported for platform")
+
+// tokenRefresher lấy access token mới từ refresh token. Trả về lỗi bọc ErrUnauthenticated khi
+// người dùng đã thu hồi quyền.
+type tokenRefresher func(ctx context.Context, refreshToken string) (auth.OAuthToken, error)
+
+// oauthProvider là luồng cấp quyền OAuth của một nền tảng.
+type oauthProvider struct {
+	// authCodeURL trả về URL cấp quyền chứa state
+	authCodeURL func(state string) (string, error)
+	// exchange đổi code lấy token và xác định tài khoản (Account) của người cấp quyền
+	exchange func(ctx context.Context, code string) (auth.OAuthToken, error)
+	refresh  tokenRefresher
+}
+
+// oauthProviders là registry platform -> luồng OAuth. Chỉ các nền tảng có trong đây mới có route
+// /auth/:platform/authorize và được oauthChecker điền AccessToken.
+var oauthProviders = map[string]oauthProvider{
+	"youtube": {authCodeURL: youtubeAuthCodeURL, exchange: exchangeYouTubeCode, refresh: refreshYouTubeToken},
+	"reddit":  {authCodeURL: redditAuthCodeURL, exchange: exchangeRedditCode, refresh: refreshRedditToken},
+}
+
+// StartOAuth tạo URL để chủ tài khoản trên platform cấp quyền đọc cho server.
+func (s *authService) StartOAuth(platform string, req model.OAuthStartRequest) (model.OAuthStartResponse, error) {
+	platform = normalizePlatform(platform)
+	provider, ok := oauthProviders[platform]
+	if !ok {
+		return model.OAuthStartResponse{}, fmt.Errorf("%w: %s", ErrOAuthUnsupported, platform)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	challenge, err := s.newOAuthState(ctx, platform, req)
+	if err != nil {
+		return model.OAuthStartResponse{}, err
+	}
+	authURL, err := provider.authCodeURL(challenge.Code)
+	if err != nil {
+		return model.OAuthStartResponse{}, err
+	}
+	return model.OAuthStartResponse{URL: authURL, State: challenge.Code, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// CompleteOAuth đổi code lấy token, xác định tài khoản của người dùng và lưu token cho các kiểm tra sau.
+func (s *authService) CompleteOAuth(platform string, req model.OAuthCallbackRequest) (model.Session, error) {
+	platform = normalizePlatform(platform)
+	provider, ok := oauthProviders[platform]
+	if !ok {
+		return model.Session{}, fmt.Errorf("%w: %s", ErrOAuthUnsupported, platform)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	challenge, err := s.consumeOAuthState(ctx, platform, req.State)
+	if err != nil {
+		return model.Session{}, err
+	}
+	if req.Error != "" || req.Code == "" {
+		return model.Session{}, fmt.Errorf("%w: authorization was not granted: %s", ErrInvalidProof, req.Error)
+	}
+
+	token, err := provider.exchange(ctx, req.Code)
+	if err != nil {
+		return model.Session{}, err
+	}
+	token.Platform = platform
+	return s.completeOAuth(ctx, challenge, token)
+}
+
+// newOAuthState tạo challenge dùng làm state của luồng cấp quyền OAuth trên platform.
+func (s *authService) newOAuthState(ctx context.Context, platform string, req model.OAuthStartRequest) (auth.Challenge, error) {
+	var session model.Session
+	if req.SessionToken != "" {
+		var err error
+		if session, err = s.GetSession(req.SessionToken); err != nil {
+			return auth.Challenge{}, err
+		}
+	}
+	if req.UserID != "" {
+		// Kiểm tra sớm; completeOAuth kiểm tra lại với tài khoản đã cấp quyền
+		if _, err := linkTarget(ctx, s.identities, session, req.UserID); err != nil {
+			return auth.Challenge{}, err
+		}
+	}
+	state, err := randomAlphanumeric(oauthStateLength)
+	if err != nil {
+		return auth.Challenge{}, err
+	}
+	challenge := auth.Challenge{
+		Code:         state,
+		Platform:     platform,
+		UserID:       req.UserID,
+		SessionToken: req.SessionToken,
+		ExpiresAt:    time.Now().Add(s.cfg.OAuthStateTTL),
+	}
+	if err := s.store.SaveChallenge(ctx, challenge); err != nil {
+		return auth.Challenge{}, err
+	}
+	return challenge, nil
+}
+
+// consumeOAuthState lấy và xoá challenge của state; state chỉ dùng được một lần.
+func (s *authService) consumeOAuthState(ctx context.Context, platform string, state string) (auth.Challenge, error) {
+	challenge, err := s.store.GetChallenge(ctx, state)
+	if errors.Is(err, auth.ErrChallengeNotFound) || (err == nil && challenge.Platform != platform) {
+		return auth.Challenge{}, fmt.Errorf("%w: unknown or expired oauth state", ErrInvalidProof)
+	}
+	if err != nil {
+		return auth.Challenge{}, err
+	}
+	if err := s.store.DeleteChallenge(ctx, state); err != nil {
+		return auth.Challenge{}, err
+	}
+	return challenge, nil
+}
+
+// completeOAuth lưu token của tài khoản đã cấp quyền, gắn tài khoản vào phiên của state (tạo phiên mới
+// nếu người gọi chưa đăng nhập) và vào user_id của state nếu có, khi user_id đó thuộc về phiên.
+func (s *authService) completeOAuth(ctx context.Context, challenge auth.Challenge, token auth.OAuthToken) (model.Session, error) {
+	if err := s.store.SaveOAuthToken(ctx, token); err != nil {
+		return model.Session{}, err
+	}
+
+	var session model.Session
+	var err error
+	if challenge.SessionToken != "" {
+		if session, err = s.GetSession(challenge.SessionToken); err != nil {
+			return model.Session{}, err
+		}
+	} else if session, err = s.newSession(time.Now()); err != nil {
+		return model.Session{}, err
+	}
+	session.Accounts[token.Platform] = token.Account
+	if err := s.store.SaveSession(ctx, session); err != nil {
+		return model.Session{}, err
+	}
+
+	if err := s.linkSessionAccount(ctx, session, challenge.UserID, token.Platform, token.Account); err != nil {
+		return model.Session{}, err
+	}
+	return session, nil
+}
+
+// oauthChecker bọc một Checker và điền AccessToken từ token OAuth mà chủ IDUser đã cấp, refresh khi
+// access token sắp hết hạn.
+type oauthChecker struct {
+	Checker
+	store auth.Store
+}
+
+// NewOAuthChecker tạo Checker điền AccessToken cho các nền tảng trong oauthProviders. Tài khoản chưa
+// cấp quyền vẫn được chuyển tiếp (không có token); kiểm tra nào bắt buộc token sẽ trả về ErrUnauthenticated.
+func NewOAuthChecker(inner Checker, store auth.Store) Checker {
+	return &oauthChecker{Checker: inner, store: store}
+}
+
+// CheckSocialAction điền AccessToken rồi gọi Checker bên trong.
+func (o *oauthChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	provider, ok := oauthProviders[req.Social]
+	if !ok || req.IDUser == "" {
+		return o.Checker.CheckSocialAction(req)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	account, err := canonicalAccountID(ctx, req.Social, req.IDUser)
+	if err != nil {
+		return model.SocialActionResponse{}, err
+	}
+	req.IDUser = account
+
+	token, err := o.store.GetOAuthToken(ctx, req.Social, account)
+	if errors.Is(err, auth.ErrTokenNotFound) {
+		return o.Checker.CheckSocialAction(req)
+	}
+	if err != nil {
+		return model.SocialActionResponse{}, err
+	}
+
+	if time.Until(token.ExpiresAt) < tokenRefreshLeeway && token.RefreshToken != "" {
+		fresh, err := provider.refresh(ctx, token.RefreshToken)
+		if errors.Is(err, ErrUnauthenticated) {
+			// Quyền đã bị thu hồi: xoá token để người dùng cấp quyền lại
+			log.Printf("OAuth token of %s account %s was revoked: %v", req.Social, account, err)
+			if err := o.store.DeleteOAuthToken(ctx, req.Social, account); err != nil {
+				return model.SocialActionResponse{}, err
+			}
+			return o.Checker.CheckSocialAction(req)
+		}
+		if err != nil {
+			return model.SocialActionResponse{}, err
+		}
+		fresh.Platform, fresh.Account = token.Platform, token.Account
+		if err := o.store.SaveOAuthToken(ctx, fresh); err != nil {
+			return model.SocialActionResponse{}, err
+		}
+		token = fresh
+	}
+	req.AccessToken = token.AccessToken
+	return o.Checker.CheckSocialAction(req)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/model"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testChannel = "UCabcdefghijklmnopqrstuv"
+
+// recordingChecker ghi lại AccessToken mà oauthChecker chuyển tiếp.
+type recordingChecker struct {
+	Checker
+	accessToken *string
+}
+
+func (r recordingChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	*r.accessToken = req.AccessToken
+	return model.SocialActionResponse{Result: true}, nil
+}
+
+// fakeGoogleToken giả lập token endpoint của Google: refresh token "revoked" bị từ chối (invalid_grant),
+// các refresh token khác nhận access token "fresh" và không được xoay vòng.
+func fakeGoogleToken(t *testing.T) *atomic.Int32 {
+	t.Helper()
+	var refreshes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("client_id") != "client" {
+			t.Errorf("unexpected token request %v", r.PostForm)
+		}
+		refreshes.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("refresh_token") == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"fresh","expires_in":3599,"token_type":"Bearer"}`))
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("YOUTUBE_OAUTH_CLIENT_ID", "client")
+	t.Setenv("YOUTUBE_OAUTH_CLIENT_SECRET", "secret")
+	t.Setenv("YOUTUBE_OAUTH_TOKEN_URL", srv.URL)
+	return &refreshes
+}
+
+func TestOAuthCheckerRefreshesYouTubeToken(t *testing.T) {
+	ctx := context.Background()
+	refreshes := fakeGoogleToken(t)
+
+	tests := []struct {
+		name          string
+		token         auth.OAuthToken
+		wantAccess    string
+		wantRefreshes int32
+		wantStored    bool
+	}{
+		{"valid token is used as is", auth.OAuthToken{AccessToken: "current", RefreshToken: "r1", ExpiresAt: time.Now().Add(time.Hour)}, "current", 0, true},
+		{"expiring token is refreshed", auth.OAuthToken{AccessToken: "stale", RefreshToken: "r1", ExpiresAt: time.Now().Add(10 * time.Second)}, "fresh", 1, true},
+		{"revoked grant drops the token", auth.OAuthToken{AccessToken: "stale", RefreshToken: "revoked", ExpiresAt: time.Now().Add(-time.Minute)}, "", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshes.Store(0)
+			store := auth.NewMemoryStore()
+			tt.token.Platform, tt.token.Account = "youtube", testChannel
+			if err := store.SaveOAuthToken(ctx, tt.token); err != nil {
+				t.Fatal(err)
+			}
+
+			var forwarded string
+			checker := NewOAuthChecker(recordingChecker{accessToken: &forwarded}, store)
+			if _, err := checker.CheckSocialAction(model.SocialActionRequest{Social: "youtube", Action: "subscribe", IDUser: testChannel}); err != nil {
+				t.Fatal(err)
+			}
+			if forwarded != tt.wantAccess {
+				t.Errorf("forwarded access token %q, want %q", forwarded, tt.wantAccess)
+			}
+			if n := refreshes.Load(); n != tt.wantRefreshes {
+				t.Errorf("token endpoint called %d times, want %d", n, tt.wantRefreshes)
+			}
+
+			stored, err := store.GetOAuthToken(ctx, "youtube", testChannel)
+			if !tt.wantStored {
+				if !errors.Is(err, auth.ErrTokenNotFound) {
+					t.Fatalf("revoked token still stored: %+v, %v", stored, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Google does not rotate refresh tokens, the stored one must survive a refresh
+			if stored.AccessToken != tt.wantAccess || stored.RefreshToken != tt.token.RefreshToken || stored.Account != testChannel {
+				t.Errorf("stored token %+v", stored)
+			}
+			if tt.wantRefreshes > 0 && time.Until(stored.ExpiresAt) < 50*time.Minute {
+				t.Errorf("refreshed token expires at %v", stored.ExpiresAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"checkingsocial/farcaster"
+	"checkingsocial/internal/model"
+	"checkingsocial/onchain"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// onchainSource là nguồn dữ liệu của các kiểm tra on-chain.
+	onchainSource = "eth_rpc"
+	// farcasterAccountPrefix đánh dấu IDUser là định danh Farcaster thay vì địa chỉ ví
+	farcasterAccountPrefix = "farcaster:"
+)
+
+// checkOnchainHolding kiểm tra ví của IDUser giữ đủ token ERC-20/721/1155 req.Target (xem onchain.ParseTarget).
+// IDUser là địa chỉ ví, hoặc "farcaster:<fid|username>" để cộng số dư của các địa chỉ đã xác minh trên profile Farcaster.
+func checkOnchainHolding(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if req.Target == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: target token contract is required", ErrInvalidTarget)
+	}
+
+	var fid int64
+	addresses := []string{req.IDUser}
+	if !onchain.IsAddress(req.IDUser) {
+		user, err := farcaster.GetUser(strings.TrimPrefix(req.IDUser, farcasterAccountPrefix))
+		if err != nil {
+			return model.SocialActionResponse{}, wrapFarcasterError(err)
+		}
+		fid = user.Fid
+		addresses = user.VerifiedAddresses.EthAddresses
+		if len(addresses) == 0 {
+			return model.SocialActionResponse{}, fmt.Errorf("%w: farcaster user %d has no verified eth address", ErrInvalidUser, fid)
+		}
+	}
+
+	res, err := onchain.CheckHolding(addresses, req.Action, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapOnchainError(err)
+	}
+	return model.SocialActionResponse{Result: res.Done, FID: fid, Source: onchainSource, Balance: res.Balance.String()}, nil
+}
+
+// wrapOnchainError chuyển lỗi của package onchain sang lỗi của service để handler map status code.
+func wrapOnchainError(err error) error {
+	switch {
+	case errors.Is(err, onchain.ErrInvalidAddress):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, onchain.ErrInvalidTarget), errors.Is(err, onchain.ErrUnknownChain), errors.Is(err, onchain.ErrCallReverted):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, onchain.ErrNotAContract):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	}
+	return err
+}
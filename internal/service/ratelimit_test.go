@@ -0,0 +1,46 @@
+package service
+
+import (
+	"checkingsocial/bluesky"
+	"checkingsocial/discord"
+	"checkingsocial/github"
+	"checkingsocial/lens"
+	"checkingsocial/mastodon"
+	"checkingsocial/pkg/ratelimit"
+	"checkingsocial/reddit"
+	"checkingsocial/telegram"
+	"checkingsocial/twitter"
+	"checkingsocial/youtube"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWrapRateLimitedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		sentinel error
+		wrap     func(error) error
+	}{
+		{"twitter", twitter.ErrRateLimited, wrapTwitterError},
+		{"github", github.ErrRateLimited, wrapGitHubError},
+		{"reddit", reddit.ErrRateLimited, wrapRedditError},
+		{"lens", lens.ErrRateLimited, wrapLensError},
+		{"bluesky", bluesky.ErrRateLimited, wrapBlueskyError},
+		{"mastodon", mastodon.ErrRateLimited, wrapMastodonError},
+		{"discord", discord.ErrRateLimited, wrapDiscordError},
+		{"telegram", telegram.ErrRateLimited, wrapTelegramError},
+		{"youtube", youtube.ErrQuotaExceeded, wrapYouTubeError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.wrap(ratelimit.New(tt.sentinel, 42*time.Second, "test"))
+			if !errors.Is(err, ErrRateLimited) || !errors.Is(err, tt.sentinel) {
+				t.Fatalf("%v: want ErrRateLimited wrapping the platform sentinel", err)
+			}
+			if got := RetryAfter(err); got != 42*time.Second {
+				t.Fatalf("RetryAfter = %s, want 42s", got)
+			}
+		})
+	}
+}
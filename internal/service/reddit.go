@@ -0,0 +1,95 @@
+package service
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/model"
+	"checkingsocial/reddit"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// redditSource là nguồn dữ liệu của các kiểm tra trên Reddit.
+const redditSource = "reddit_api"
+
+// checkRedditAction kiểm tra IDUser đã tham gia subreddit, upvote hoặc bình luận bài viết req.Target.
+// Reddit chỉ cho chính chủ tài khoản xem các thông tin này nên cần token OAuth đã cấp qua /auth/reddit/authorize.
+// Lịch sử bình luận quá dài để quét hết trả về Status "unverifiable".
+func checkRedditAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if req.Action != reddit.ActionJoinSubreddit && req.Target == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: target post is required", ErrInvalidTarget)
+	}
+	if req.AccessToken == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: connect Reddit account %s via /api/v1/auth/reddit/authorize first", ErrUnauthenticated, req.IDUser)
+	}
+	res, err := reddit.CheckAction(req.AccessToken, req.IDUser, req.Action, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapRedditError(err)
+	}
+	resp := model.SocialActionResponse{Result: res.Done, Source: redditSource}
+	if !res.Verifiable {
+		resp.Status, resp.Reason = model.StatusUnverifiable, res.Reason
+	}
+	return resp, nil
+}
+
+// redditAuthCodeURL trả về URL để chủ tài khoản Reddit cấp quyền cho server.
+func redditAuthCodeURL(state string) (string, error) {
+	cfg, err := reddit.LoadOAuthConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.AuthCodeURL(state), nil
+}
+
+// exchangeRedditCode đổi code lấy token; tài khoản là username (chữ thường) của người cấp quyền.
+func exchangeRedditCode(ctx context.Context, code string) (auth.OAuthToken, error) {
+	cfg, err := reddit.LoadOAuthConfig()
+	if err != nil {
+		return auth.OAuthToken{}, err
+	}
+	token, err := cfg.Exchange(ctx, code)
+	if err != nil {
+		return auth.OAuthToken{}, wrapRedditError(err)
+	}
+	client, err := reddit.NewClient(token.AccessToken)
+	if err != nil {
+		return auth.OAuthToken{}, wrapRedditError(err)
+	}
+	username, err := client.Me(ctx)
+	if err != nil {
+		return auth.OAuthToken{}, wrapRedditError(err)
+	}
+	return auth.OAuthToken{
+		Account:      username,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.Expiry,
+	}, nil
+}
+
+// refreshRedditToken implements tokenRefresher cho Reddit.
+func refreshRedditToken(ctx context.Context, refreshToken string) (auth.OAuthToken, error) {
+	token, err := reddit.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		return auth.OAuthToken{}, wrapRedditError(err)
+	}
+	return auth.OAuthToken{AccessToken: token.AccessToken, RefreshToken: token.RefreshToken, ExpiresAt: token.Expiry}, nil
+}
+
+// wrapRedditError chuyển lỗi của package reddit sang lỗi của service để handler map status code.
+func wrapRedditError(err error) error {
+	switch {
+	case errors.Is(err, reddit.ErrInvalidUsername):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, reddit.ErrInvalidTarget):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, reddit.ErrTargetNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, reddit.ErrUnauthorized), errors.Is(err, reddit.ErrTokenRevoked):
+		return fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	case errors.Is(err, reddit.ErrRateLimited):
+		return wrapRateLimit(err)
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"checkingsocial/internal/model"
+	"checkingsocial/internal/reverify"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultReverifySchedule = "@every 1h"
+	defaultReverifyWindow   = 30 * 24 * time.Hour
+)
+
+// ReverifyConfig là cấu hình kiểm tra lại định kỳ, đọc từ env:
+//   - REVERIFY_ENABLED: bật kiểm tra lại
+//   - REVERIFY_SCHEDULE: cron spec (mặc định "@every 1h")
+//   - REVERIFY_WINDOW: chỉ kiểm tra lại các verification trong khoảng này (mặc định 720h)
+//   - REVERIFY_WEBHOOK_URL: URL nhận event unfollowed/revoked/restored (tuỳ chọn)
+type ReverifyConfig struct {
+	Enabled    bool
+	Schedule   string
+	Window     time.Duration
+	WebhookURL string
+}
+
+// LoadReverifyConfig đọc ReverifyConfig từ env.
+func LoadReverifyConfig() (ReverifyConfig, error) {
+	cfg := ReverifyConfig{
+		Schedule:   defaultReverifySchedule,
+		Window:     defaultReverifyWindow,
+		WebhookURL: os.Getenv("REVERIFY_WEBHOOK_URL"),
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("REVERIFY_ENABLED"))) {
+	case "1", "true", "yes", "y", "on":
+		cfg.Enabled = true
+	}
+	if v := strings.TrimSpace(os.Getenv("REVERIFY_SCHEDULE")); v != "" {
+		cfg.Schedule = v
+	}
+	if v := strings.TrimSpace(os.Getenv("REVERIFY_WINDOW")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid REVERIFY_WINDOW: %w", err)
+		}
+		cfg.Window = d
+	}
+	return cfg, nil
+}
+
+// trackingChecker bọc một Checker và ghi lại các lần xác minh thành công để kiểm tra lại sau.
+type trackingChecker struct {
+	Checker
+	store reverify.Store
+}
+
+// NewTrackingChecker tạo Checker ghi mọi kết quả CheckSocialAction thành công vào store.
+func NewTrackingChecker(inner Checker, store reverify.Store) Checker {
+	return &trackingChecker{Checker: inner, store: store}
+}
+
+// CheckSocialAction gọi Checker bên trong và ghi nhận kết quả thành công.
+func (t *trackingChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	resp, err := t.Checker.CheckSocialAction(req)
+	if err != nil || !resp.Result {
+		return resp, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	id := reverify.VerificationID(req)
+	v, ok, getErr := t.store.Get(ctx, id)
+	if getErr != nil {
+		log.Printf("[Reverify][ERROR] load verification %s: %v", id, getErr)
+		return resp, nil
+	}
+	if !ok || v.Status != reverify.StatusVerified {
+		v = reverify.Verification{ID: id, Request: req, Status: reverify.StatusVerified, VerifiedAt: now, StatusChangedAt: now}
+	}
+	v.LastCheckedAt = now
+	if saveErr := t.store.Save(ctx, v); saveErr != nil {
+		log.Printf("[Reverify][ERROR] save verification %s: %v", id, saveErr)
+	}
+	return resp, nil
+}
+
+// Reverifier kiểm tra lại các verification đã thành công và phát event khi trạng thái thay đổi.
+type Reverifier struct {
+	checker  Checker
+	store    reverify.Store
+	notifier reverify.Notifier
+	window   time.Duration
+}
+
+// NewReverifier tạo Reverifier. checker nên là Checker gốc (không bọc tracking) để việc kiểm tra lại
+// không ghi đè VerifiedAt.
+func NewReverifier(checker Checker, store reverify.Store, notifier reverify.Notifier, window time.Duration) *Reverifier {
+	return &Reverifier{checker: checker, store: store, notifier: notifier, window: window}
+}
+
+// Run kiểm tra lại mọi verification trong window, trả về số verification đổi trạng thái.
+func (r *Reverifier) Run(ctx context.Context) (int, error) {
+	items, err := r.store.ListVerifiedSince(ctx, time.Now().Add(-r.window))
+	if err != nil {
+		return 0, fmt.Errorf("list verifications: %w", err)
+	}
+
+	changed := 0
+	for _, v := range items {
+		if ctx.Err() != nil {
+			return changed, ctx.Err()
+		}
+
+		resp, err := r.checker.CheckSocialAction(v.Request)
+		if err != nil {
+			// Lỗi tạm thời của provider không được coi là unfollow
+			log.Printf("[Reverify][ERROR] recheck %s: %v", v.ID, err)
+			continue
+		}
+		if resp.Status == model.StatusUnverifiable {
+			// Không kiểm tra được (ví dụ người dùng vừa ẩn danh sách following) cũng không phải unfollow
+			log.Printf("[Reverify] recheck %s unverifiable: %s", v.ID, resp.Reason)
+			continue
+		}
+
+		now := time.Now()
+		previous := v.Status
+		v.LastCheckedAt = now
+
+		var eventType string
+		switch {
+		case resp.Result && previous != reverify.StatusVerified:
+			v.Status = reverify.StatusVerified
+			eventType = reverify.EventRestored
+		case !resp.Result && previous == reverify.StatusVerified:
+			v.Status, eventType = lostStatus(v.Request.Action)
+		}
+		if eventType != "" {
+			v.StatusChangedAt = now
+			changed++
+		}
+
+		if err := r.store.Save(ctx, v); err != nil {
+			log.Printf("[Reverify][ERROR] save verification %s: %v", v.ID, err)
+			continue
+		}
+		if eventType == "" {
+			continue
+		}
+		event := reverify.Event{Type: eventType, Verification: v, PreviousStatus: previous, DetectedAt: now}
+		if err := r.notifier.Notify(ctx, event); err != nil {
+			log.Printf("[Reverify][ERROR] notify %s %s: %v", eventType, v.ID, err)
+		}
+	}
+
+	log.Printf("[Reverify] rechecked %d verifications, %d changed", len(items), changed)
+	return changed, nil
+}
+
+// lostStatus trả về trạng thái và event khi một verification không còn đúng nữa.
+func lostStatus(action string) (status string, event string) {
+	switch action {
+	case "follow", "followed_by", "mutual_follow":
+		return reverify.StatusUnfollowed, reverify.EventUnfollowed
+	}
+	return reverify.StatusRevoked, reverify.EventRevoked
+}
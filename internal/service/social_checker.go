@@ -1,19 +1,61 @@
 package service
 
 import (
+	"checkingsocial/bluesky"
+	"checkingsocial/discord"
 	"checkingsocial/farcaster"
+	"checkingsocial/github"
 	"checkingsocial/internal/model"
+	"checkingsocial/lens"
+	"checkingsocial/mastodon"
+	"checkingsocial/nostr"
+	"checkingsocial/onchain"
+	"checkingsocial/pkg/ratelimit"
+	"checkingsocial/reddit"
+	"checkingsocial/telegram"
 	"checkingsocial/twitter"
+	"checkingsocial/youtube"
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // Checker định nghĩa interface cho việc kiểm tra tài khoản mạng xã hội.
 type Checker interface {
 	Check(req model.CheckRequest) model.CheckResponse
 	BatchCheck(req model.BatchCheckRequest) model.BatchCheckResponse
-	CheckSocialAction(req model.SocialActionRequest) (bool, error)
+	CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error)
+	GetFarcasterProfile(identifier string) (model.FarcasterProfile, error)
+}
+
+var (
+	// ErrInvalidUser được trả về khi IDUser không hợp lệ.
+	ErrInvalidUser = errors.New("invalid iduser")
+	// ErrUserNotFound được trả về khi không tìm thấy người dùng tương ứng.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrInvalidTarget được trả về khi target của hành động không hợp lệ.
+	ErrInvalidTarget = errors.New("invalid target")
+	// ErrTargetNotFound được trả về khi không tìm thấy target (ví dụ cast đã bị xoá).
+	ErrTargetNotFound = errors.New("target not found")
+	// ErrRateLimited được trả về khi nền tảng từ chối vì vượt giới hạn request; xem RetryAfter.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstreamUnavailable được trả về khi không nguồn dữ liệu nào của nền tảng trả lời (ví dụ mọi relay Nostr đều lỗi).
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+)
+
+// RetryAfter trả về thời gian nên chờ trước khi thử lại một lỗi ErrRateLimited, 0 khi nền tảng không cho biết.
+func RetryAfter(err error) time.Duration {
+	var rl *ratelimit.Error
+	if errors.As(err, &rl) {
+		return rl.RetryAfter
+	}
+	return 0
+}
+
+// wrapRateLimit bọc lỗi rate limit của nền tảng vào ErrRateLimited; lỗi gốc được giữ (%w) để RetryAfter đọc được.
+func wrapRateLimit(err error) error {
+	return fmt.Errorf("%w: %w", ErrRateLimited, err)
 }
 
 // socialChecker là implementation của Checker.
@@ -24,15 +66,185 @@ func NewSocialChecker() Checker {
 	return &socialChecker{}
 }
 
+// actionFunc kiểm tra một hành động trên một nền tảng.
+type actionFunc func(req model.SocialActionRequest) (model.SocialActionResponse, error)
+
+// socialActions là registry social -> action -> hàm kiểm tra. Nền tảng mới được thêm vào đây.
+var socialActions = map[string]map[string]actionFunc{
+	"farcaster": {
+		farcaster.RelationFollow:     checkFarcasterFollow,
+		farcaster.RelationFollowedBy: checkFarcasterFollow,
+		farcaster.RelationMutual:     checkFarcasterFollow,
+		farcaster.ActionLike:         checkFarcasterEngagement,
+		farcaster.ActionRecast:       checkFarcasterEngagement,
+		farcaster.ActionReply:        checkFarcasterEngagement,
+	},
+	"x": {
+		twitter.ActionFollow:     checkXAction,
+		twitter.ActionFollowedBy: checkXAction,
+		twitter.ActionMutual:     checkXAction,
+		twitter.ActionLike:       checkXAction,
+		twitter.ActionRetweet:    checkXAction,
+	},
+	string(model.Telegram): {
+		telegram.ActionJoin: checkTelegramJoin,
+	},
+	string(model.Discord): {
+		discord.ActionJoinGuild: checkDiscordMember,
+		discord.ActionHasRole:   checkDiscordMember,
+	},
+	string(model.YouTube): {
+		youtube.ActionSubscribe: checkYouTubeSubscribe,
+		youtube.ActionComment:   checkYouTubeComment,
+	},
+	string(model.GitHub): {
+		github.ActionStar:   checkGitHubAction,
+		github.ActionFollow: checkGitHubAction,
+		github.ActionFork:   checkGitHubAction,
+	},
+	string(model.Bluesky): {
+		bluesky.ActionFollow: checkBlueskyFollow,
+		bluesky.ActionLike:   checkBlueskyEngagement,
+		bluesky.ActionRepost: checkBlueskyEngagement,
+	},
+	string(model.Lens): {
+		lens.ActionFollow:  checkLensFollow,
+		lens.ActionMirror:  checkLensPublication,
+		lens.ActionCollect: checkLensPublication,
+	},
+	string(model.Onchain): {
+		onchain.ActionHoldERC20:   checkOnchainHolding,
+		onchain.ActionHoldERC721:  checkOnchainHolding,
+		onchain.ActionHoldERC1155: checkOnchainHolding,
+	},
+	string(model.Reddit): {
+		reddit.ActionJoinSubreddit: checkRedditAction,
+		reddit.ActionUpvote:        checkRedditAction,
+		reddit.ActionComment:       checkRedditAction,
+	},
+	string(model.Mastodon): {
+		mastodon.ActionFollow: checkMastodonFollow,
+	},
+	string(model.Nostr): {
+		nostr.ActionFollow: checkNostrFollow,
+	},
+}
+
 // CheckSocialAction thực hiện kiểm tra một hành động xã hội.
-func (s *socialChecker) CheckSocialAction(req model.SocialActionRequest) (bool, error) {
-	if req.Social == "farcaster" && req.Action == "follow" {
-		return farcaster.CheckFollow(req.IDUser)
+func (s *socialChecker) CheckSocialAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	check, ok := socialActions[req.Social][req.Action]
+	if !ok {
+		return model.SocialActionResponse{}, errors.New("unsupported social or action")
+	}
+	return check(req)
+}
+
+// checkFarcasterFollow kiểm tra quan hệ follow (follow, followed_by, mutual_follow) trên Farcaster.
+// Kết quả từ Redis (Source "redis") không có Status và Relationship vì cache chỉ biết chiều follow; kết quả từ
+// hub không có Status và trường mute/block. Danh sách follow quá dài để quét hết trả về Status "unverifiable".
+func checkFarcasterFollow(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	res, err := farcaster.CheckFollowDetailed(req.IDUser, req.Target, req.Action)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapFarcasterError(err)
+	}
+	resp := model.SocialActionResponse{
+		Result: res.Verified(req.Action),
+		FID:    res.UserFID,
+		Source: res.Source,
+		Status: res.Status(),
+	}
+	if !res.Verifiable {
+		resp.Result, resp.Status, resp.Reason = false, model.StatusUnverifiable, res.Reason
+		return resp, nil
+	}
+	if res.Source != farcaster.SourceCache {
+		resp.Relationship = &model.Relationship{Following: res.Following, FollowedBy: res.FollowedBy}
+	}
+	if res.KnowsMuteBlock() {
+		resp.Relationship.Blocked, resp.Relationship.BlockedBy = &res.Blocked, &res.BlockedBy
+		resp.Relationship.Muted, resp.Relationship.MutedBy = &res.Muted, &res.MutedBy
+	}
+	if res.Ineligible != nil {
+		resp.FailedRule = res.Ineligible.Rule
+		resp.Reason = res.Ineligible.Reason
+	}
+	return resp, nil
+}
+
+// checkFarcasterEngagement kiểm tra người dùng đã like, recast hoặc reply cast req.Target hay chưa.
+// Cast có quá nhiều reply/reaction để quét hết trả về Status "unverifiable".
+func checkFarcasterEngagement(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if req.Target == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: target cast hash is required", ErrInvalidTarget)
+	}
+	res, err := farcaster.CheckEngagement(req.IDUser, req.Target, req.Action)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapFarcasterError(err)
+	}
+	resp := model.SocialActionResponse{Result: res.Done, FID: res.UserFID, Source: res.Source}
+	if !res.Verifiable {
+		resp.Status, resp.Reason = model.StatusUnverifiable, res.Reason
+	}
+	return resp, nil
+}
+
+// checkXAction kiểm tra follow, like hoặc retweet trên X qua các provider trong X_PROVIDER.
+// Danh sách quá dài để quét hết trả về Status "unverifiable".
+func checkXAction(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if (req.Action == twitter.ActionLike || req.Action == twitter.ActionRetweet) && req.Target == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: target tweet id is required", ErrInvalidTarget)
+	}
+	res, err := twitter.CheckAction(req.IDUser, req.Action, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapTwitterError(err)
 	}
-	if req.Social == "x" && req.Action == "follow" {
-		return twitter.CheckFollow(req.IDUser)
+	resp := model.SocialActionResponse{Result: res.Done, Source: res.Source}
+	if !res.Verifiable {
+		resp.Status, resp.Reason = model.StatusUnverifiable, res.Reason
 	}
-	return false, errors.New("unsupported social or action")
+	return resp, nil
+}
+
+// GetFarcasterProfile lấy hồ sơ Farcaster đầy đủ (follower, pfp, bio, địa chỉ đã xác minh, score...).
+func (s *socialChecker) GetFarcasterProfile(identifier string) (model.FarcasterProfile, error) {
+	user, err := farcaster.GetUser(identifier)
+	if err != nil {
+		return model.FarcasterProfile{}, wrapFarcasterError(err)
+	}
+
+	verified := user.VerifiedAddresses.EthAddresses
+	if verified == nil {
+		verified = []string{}
+	}
+	return model.FarcasterProfile{
+		FID:               user.Fid,
+		Username:          user.Username,
+		DisplayName:       user.DisplayName,
+		PfpURL:            user.PfpURL,
+		Bio:               user.Profile.Bio.Text,
+		FollowerCount:     user.FollowerCount,
+		FollowingCount:    user.FollowingCount,
+		CustodyAddress:    user.CustodyAddress,
+		VerifiedAddresses: verified,
+		VerifiedSolAddrs:  user.VerifiedAddresses.SolAddresses,
+		PowerBadge:        user.PowerBadge,
+		NeynarUserScore:   user.NeynarScore(),
+	}, nil
+}
+
+// wrapFarcasterError chuyển lỗi của package farcaster sang lỗi của service để handler map status code.
+func wrapFarcasterError(err error) error {
+	switch {
+	case errors.Is(err, farcaster.ErrInvalidIdentifier):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, farcaster.ErrUserNotFound):
+		return fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	case errors.Is(err, farcaster.ErrInvalidCastHash):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, farcaster.ErrCastNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	}
+	return err
 }
 
 // Check thực hiện kiểm tra một tài khoản mạng xã hội.
@@ -83,3 +295,20 @@ func (s *socialChecker) BatchCheck(req model.BatchCheckRequest) model.BatchCheck
 		Failed:  len(req.Checks) - successCount,
 	}
 }
+
+// wrapTwitterError chuyển lỗi của package twitter sang lỗi của service để handler map status code.
+func wrapTwitterError(err error) error {
+	switch {
+	case errors.Is(err, twitter.ErrInvalidHandle):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, twitter.ErrUserNotFound):
+		return fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	case errors.Is(err, twitter.ErrInvalidTweetID):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, twitter.ErrTweetNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, twitter.ErrRateLimited):
+		return wrapRateLimit(err)
+	}
+	return err
+}
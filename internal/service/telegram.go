@@ -0,0 +1,38 @@
+package service
+
+import (
+	"checkingsocial/internal/model"
+	"checkingsocial/telegram"
+	"errors"
+	"fmt"
+)
+
+// telegramSource là nguồn dữ liệu của các kiểm tra trên Telegram.
+const telegramSource = "telegram_bot_api"
+
+// checkTelegramJoin kiểm tra người dùng (Telegram user ID dạng số) có trong group/channel req.Target
+// (mặc định TELEGRAM_CHAT_ID) hay không.
+func checkTelegramJoin(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	res, err := telegram.CheckMembership(req.IDUser, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapTelegramError(err)
+	}
+	return model.SocialActionResponse{Result: res.Member, Source: telegramSource, Status: res.Status}, nil
+}
+
+// wrapTelegramError chuyển lỗi của package telegram sang lỗi của service để handler map status code.
+func wrapTelegramError(err error) error {
+	switch {
+	case errors.Is(err, telegram.ErrInvalidUserID):
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	case errors.Is(err, telegram.ErrUserNotFound):
+		return fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	case errors.Is(err, telegram.ErrInvalidChat):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, telegram.ErrChatNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, telegram.ErrRateLimited):
+		return wrapRateLimit(err)
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"checkingsocial/farcaster"
+	"checkingsocial/pkg/cache"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature được trả về khi chữ ký webhook không hợp lệ.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrInvalidPayload được trả về khi body webhook không đọc được.
+	ErrInvalidPayload = errors.New("invalid payload")
+	// ErrUnavailable được trả về khi thiếu dependency (Redis chưa được khởi tạo...).
+	ErrUnavailable = errors.New("service unavailable")
+)
+
+// WebhookProcessor định nghĩa interface xử lý webhook từ các nhà cung cấp.
+type WebhookProcessor interface {
+	HandleNeynarWebhook(body []byte, signature string) (string, error)
+}
+
+// webhookProcessor là implementation của WebhookProcessor.
+type webhookProcessor struct{}
+
+// NewWebhookProcessor tạo một instance mới của webhookProcessor.
+func NewWebhookProcessor() WebhookProcessor {
+	return &webhookProcessor{}
+}
+
+// HandleNeynarWebhook xác thực chữ ký và cập nhật trạng thái Redis từ event của Neynar.
+// Trả về loại event đã xử lý.
+func (p *webhookProcessor) HandleNeynarWebhook(body []byte, signature string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event, err := farcaster.HandleWebhook(ctx, body, signature)
+	if err != nil {
+		switch {
+		case errors.Is(err, farcaster.ErrInvalidSignature):
+			return "", fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		case errors.Is(err, farcaster.ErrInvalidEvent):
+			return "", fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		case errors.Is(err, cache.ErrNotInitialized):
+			return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		return "", err
+	}
+	return event.Type, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"checkingsocial/internal/auth"
+	"checkingsocial/internal/model"
+	"checkingsocial/youtube"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// youtubeSource là nguồn dữ liệu của các kiểm tra trên YouTube.
+const youtubeSource = "youtube_data_api"
+
+// checkYouTubeSubscribe kiểm tra kênh IDUser đã subscribe kênh req.Target (mặc định YOUTUBE_CHANNEL_ID).
+// Cần token OAuth mà chủ kênh đã cấp qua /auth/youtube/authorize.
+func checkYouTubeSubscribe(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if req.AccessToken == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: connect YouTube channel %s via /api/v1/auth/youtube/authorize first", ErrUnauthenticated, req.IDUser)
+	}
+	res, err := youtube.CheckSubscription(req.AccessToken, req.IDUser, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapYouTubeError(err)
+	}
+	return model.SocialActionResponse{Result: res.Done, Source: youtubeSource}, nil
+}
+
+// checkYouTubeComment kiểm tra kênh IDUser đã bình luận (hoặc trả lời bình luận) video req.Target.
+// Video có quá nhiều bình luận để quét hết trả về Status "unverifiable".
+func checkYouTubeComment(req model.SocialActionRequest) (model.SocialActionResponse, error) {
+	if req.Target == "" {
+		return model.SocialActionResponse{}, fmt.Errorf("%w: target video is required", ErrInvalidTarget)
+	}
+	res, err := youtube.CheckComment(req.AccessToken, req.IDUser, req.Target)
+	if err != nil {
+		return model.SocialActionResponse{}, wrapYouTubeError(err)
+	}
+	resp := model.SocialActionResponse{Result: res.Done, Source: youtubeSource}
+	if !res.Verifiable {
+		resp.Status, resp.Reason = model.StatusUnverifiable, res.Reason
+	}
+	return resp, nil
+}
+
+// youtubeAuthCodeURL trả về URL để chủ kênh cấp quyền đọc (youtube.readonly) cho server.
+func youtubeAuthCodeURL(state string) (string, error) {
+	cfg, err := youtube.LoadOAuthConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.AuthCodeURL(state), nil
+}
+
+// exchangeYouTubeCode đổi code lấy token; tài khoản là channel ID của người cấp quyền.
+func exchangeYouTubeCode(ctx context.Context, code string) (auth.OAuthToken, error) {
+	cfg, err := youtube.LoadOAuthConfig()
+	if err != nil {
+		return auth.OAuthToken{}, err
+	}
+	token, err := cfg.Exchange(ctx, code)
+	if err != nil {
+		return auth.OAuthToken{}, wrapYouTubeError(err)
+	}
+	channelID, err := youtube.NewClient().MyChannelID(ctx, token.AccessToken)
+	if err != nil {
+		return auth.OAuthToken{}, wrapYouTubeError(err)
+	}
+	return auth.OAuthToken{
+		Account:      channelID,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.Expiry,
+	}, nil
+}
+
+// refreshYouTubeToken implements tokenRefresher cho YouTube.
+func refreshYouTubeToken(ctx context.Context, refreshToken string) (auth.OAuthToken, error) {
+	token, err := youtube.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		return auth.OAuthToken{}, wrapYouTubeError(err)
+	}
+	return auth.OAuthToken{AccessToken: token.AccessToken, RefreshToken: token.RefreshToken, ExpiresAt: token.Expiry}, nil
+}
+
+// wrapYouTubeError chuyển lỗi của package youtube sang lỗi của service để handler map status code.
+func wrapYouTubeError(err error) error {
+	switch {
+	case errors.Is(err, youtube.ErrInvalidChannel), errors.Is(err, youtube.ErrInvalidVideoID):
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, youtube.ErrChannelNotFound), errors.Is(err, youtube.ErrVideoNotFound):
+		return fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	case errors.Is(err, youtube.ErrUnauthorized), errors.Is(err, youtube.ErrTokenRevoked):
+		return fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	case errors.Is(err, youtube.ErrQuotaExceeded):
+		return wrapRateLimit(err)
+	}
+	return err
+}
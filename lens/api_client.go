@@ -0,0 +1,277 @@
+package lens
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// defaultAPIURL is the Lens API v2 GraphQL endpoint
+const defaultAPIURL = "https://api-v2.lens.dev"
+
+var (
+	// errNotFound is a NOT_FOUND GraphQL error; the caller knows which input it refers to (see inputError)
+	errNotFound = errors.New("not found")
+	// errBadInput is a BAD_USER_INPUT or GRAPHQL_VALIDATION_FAILED GraphQL error
+	errBadInput = errors.New("bad input")
+)
+
+// APIClient queries the Lens GraphQL API
+type APIClient struct {
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewAPIClient creates a Lens API client.
+// Config via ENV:
+//   - LENS_API_URL (optional): GraphQL endpoint (defaults to https://api-v2.lens.dev)
+func NewAPIClient() *APIClient {
+	apiURL := strings.TrimRight(os.Getenv("LENS_API_URL"), "/")
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	return &APIClient{apiURL: apiURL, httpClient: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Name implements Provider
+func (c *APIClient) Name() string {
+	return ProviderAPI
+}
+
+// graphQLError is an entry of the GraphQL "errors" array
+type graphQLError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+// query posts a GraphQL query and decodes "data" into out. GraphQL answers partial data next to errors,
+// so out is filled even when the first error is returned.
+func (c *APIClient) query(ctx context.Context, query string, variables map[string]any, out any) error {
+	payload, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := ratelimit.FromSeconds(resp.Header.Get("Retry-After"))
+		if retryAfter == 0 {
+			return ratelimit.New(ErrRateLimited, 0, "")
+		}
+		return ratelimit.New(ErrRateLimited, retryAfter, "retry after "+retryAfter.String())
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("lens api: status %d: failed to unmarshal response: %w", resp.StatusCode, err)
+	}
+	var dataErr error
+	if len(envelope.Data) > 0 && string(envelope.Data) != "null" {
+		dataErr = json.Unmarshal(envelope.Data, out)
+	}
+	if len(envelope.Errors) > 0 {
+		return graphQLErrorToSentinel(envelope.Errors[0])
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("lens api failed with status %d", resp.StatusCode)
+	}
+	if dataErr != nil {
+		return fmt.Errorf("failed to unmarshal data: %w", dataErr)
+	}
+	return nil
+}
+
+// graphQLErrorToSentinel maps a GraphQL error by its extensions.code, never by the (English, unstable) message
+func graphQLErrorToSentinel(e graphQLError) error {
+	switch e.Extensions.Code {
+	case "TOO_MANY_REQUESTS":
+		return fmt.Errorf("%w: %s", ErrRateLimited, e.Message)
+	case "NOT_FOUND":
+		return fmt.Errorf("%w: %s", errNotFound, e.Message)
+	case "BAD_USER_INPUT", "GRAPHQL_VALIDATION_FAILED":
+		return fmt.Errorf("%w: %s", errBadInput, e.Message)
+	}
+	return fmt.Errorf("lens api error %s: %s", e.Extensions.Code, e.Message)
+}
+
+// inputError turns errNotFound and errBadInput of a query about a single input into that input's errors
+func inputError(err error, notFound error, invalid error) error {
+	switch {
+	case errors.Is(err, errNotFound):
+		return fmt.Errorf("%w: %v", notFound, err)
+	case errors.Is(err, errBadInput):
+		return fmt.Errorf("%w: %v", invalid, err)
+	}
+	return err
+}
+
+const profileQuery = `query Profile($request: ProfileRequest!) {
+  profile(request: $request) { id }
+}`
+
+const defaultProfileQuery = `query DefaultProfile($for: EvmAddress!) {
+  defaultProfile(request: { for: $for }) { id }
+}`
+
+// LookupProfile implements Provider
+func (c *APIClient) LookupProfile(ctx context.Context, kind string, value string) (string, error) {
+	var out struct {
+		Profile *struct {
+			ID string `json:"id"`
+		} `json:"profile"`
+		DefaultProfile *struct {
+			ID string `json:"id"`
+		} `json:"defaultProfile"`
+	}
+	var err error
+	switch kind {
+	case kindProfileID:
+		err = c.query(ctx, profileQuery, map[string]any{"request": map[string]any{"forProfileId": value}}, &out)
+	case kindHandle:
+		err = c.query(ctx, profileQuery, map[string]any{"request": map[string]any{"forHandle": value}}, &out)
+	case kindAddress:
+		err = c.query(ctx, defaultProfileQuery, map[string]any{"for": value}, &out)
+		out.Profile = out.DefaultProfile
+	default:
+		return "", fmt.Errorf("%w: lookup by %s", ErrNotSupported, kind)
+	}
+	if err != nil {
+		return "", inputError(err, ErrUserNotFound, ErrInvalidIdentifier)
+	}
+	if out.Profile == nil {
+		return "", fmt.Errorf("%w: %s", ErrUserNotFound, value)
+	}
+	return out.Profile.ID, nil
+}
+
+const followStatusQuery = `query FollowStatus($request: FollowStatusBulkRequest!, $profile: ProfileId!, $target: ProfileId!) {
+  profile(request: { forProfileId: $profile }) { id }
+  target: profile(request: { forProfileId: $target }) { id }
+  followStatusBulk(request: $request) { follower profileId status { value } }
+}`
+
+// FetchRelationship implements Provider with one followStatusBulk call for both directions. Both profiles
+// are fetched in the same query so a missing target is not reported as a missing user.
+func (c *APIClient) FetchRelationship(ctx context.Context, profileID string, targetID string) (Relationship, error) {
+	var out struct {
+		Profile *struct {
+			ID string `json:"id"`
+		} `json:"profile"`
+		Target *struct {
+			ID string `json:"id"`
+		} `json:"target"`
+		FollowStatusBulk []struct {
+			Follower  string `json:"follower"`
+			ProfileID string `json:"profileId"`
+			Status    struct {
+				Value bool `json:"value"`
+			} `json:"status"`
+		} `json:"followStatusBulk"`
+	}
+	request := map[string]any{"followInfos": []map[string]string{
+		{"follower": profileID, "profileId": targetID},
+		{"follower": targetID, "profileId": profileID},
+	}}
+	err := c.query(ctx, followStatusQuery, map[string]any{"request": request, "profile": profileID, "target": targetID}, &out)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return Relationship{}, err
+	}
+	switch {
+	case out.Profile == nil:
+		return Relationship{}, fmt.Errorf("%w: %s", ErrUserNotFound, profileID)
+	case out.Target == nil:
+		return Relationship{}, fmt.Errorf("%w: %s", ErrTargetNotFound, targetID)
+	case err != nil:
+		return Relationship{}, err
+	}
+	var rel Relationship
+	for _, s := range out.FollowStatusBulk {
+		switch {
+		case s.Follower == profileID && s.ProfileID == targetID:
+			rel.Following = s.Status.Value
+		case s.Follower == targetID && s.ProfileID == profileID:
+			rel.FollowedBy = s.Status.Value
+		}
+	}
+	return rel, nil
+}
+
+const mirroredQuery = `query Mirrored($pub: PublicationId!, $profile: ProfileId!) {
+  profile(request: { forProfileId: $profile }) { id }
+  publication(request: { forId: $pub }) { __typename }
+  matches: publications(request: { where: { from: [$profile], mirrorOn: $pub }, limit: Ten }) { items { __typename } }
+}`
+
+const collectedQuery = `query Collected($pub: PublicationId!, $profile: ProfileId!) {
+  profile(request: { forProfileId: $profile }) { id }
+  publication(request: { forId: $pub }) { __typename }
+  matches: publications(request: { where: { publicationIds: [$pub], actedBy: $profile }, limit: Ten }) { items { __typename } }
+}`
+
+// HasMirrored implements Provider
+func (c *APIClient) HasMirrored(ctx context.Context, profileID string, publicationID string) (bool, error) {
+	return c.matchPublication(ctx, mirroredQuery, profileID, publicationID)
+}
+
+// HasCollected implements Provider; collects are open actions, so any action by the profile counts
+func (c *APIClient) HasCollected(ctx context.Context, profileID string, publicationID string) (bool, error) {
+	return c.matchPublication(ctx, collectedQuery, profileID, publicationID)
+}
+
+// matchPublication runs a query that fetches the profile, the publication and the profile's matching publications
+func (c *APIClient) matchPublication(ctx context.Context, query string, profileID string, publicationID string) (bool, error) {
+	var out struct {
+		Profile *struct {
+			ID string `json:"id"`
+		} `json:"profile"`
+		Publication *struct {
+			Typename string `json:"__typename"`
+		} `json:"publication"`
+		Matches struct {
+			Items []json.RawMessage `json:"items"`
+		} `json:"matches"`
+	}
+	err := c.query(ctx, query, map[string]any{"pub": publicationID, "profile": profileID}, &out)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return false, inputError(err, ErrPublicationNotFound, ErrInvalidPublicationID)
+	}
+	// A missing publication would otherwise read as "not mirrored"
+	switch {
+	case out.Profile == nil:
+		return false, fmt.Errorf("%w: %s", ErrUserNotFound, profileID)
+	case out.Publication == nil:
+		return false, fmt.Errorf("%w: %s", ErrPublicationNotFound, publicationID)
+	case err != nil:
+		return false, err
+	}
+	return len(out.Matches.Items) > 0, nil
+}
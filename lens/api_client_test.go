@@ -0,0 +1,196 @@
+package lens
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// fakeProfiles maps the profile IDs known to fakeLensAPI to their handles
+var fakeProfiles = map[string]string{"0x01": "lens/alice", "0x02": "lens/bob"}
+
+// fakeLensAPI is a GraphQL endpoint answering the operations of APIClient from fakeProfiles. alice follows
+// bob and mirrored publication 0x02-0x01. Handles named after an error code answer that GraphQL error
+// with a message that is not English, so only extensions.code can map it.
+func fakeLensAPI(t *testing.T) {
+	t.Helper()
+	profile := func(id string) any {
+		if _, ok := fakeProfiles[id]; !ok {
+			return nil
+		}
+		return map[string]string{"id": id}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		str := func(name string) string { s, _ := req.Variables[name].(string); return s }
+		answer := map[string]any{}
+		data := map[string]any{}
+
+		switch {
+		case strings.HasPrefix(req.Query, "query Profile("):
+			request, _ := req.Variables["request"].(map[string]any)
+			handle, _ := request["forHandle"].(string)
+			if code, ok := strings.CutPrefix(handle, "lens/code_"); ok {
+				answer["errors"] = []any{map[string]any{"message": "lỗi", "extensions": map[string]string{"code": strings.ToUpper(code)}}}
+				break
+			}
+			data["profile"] = nil
+			for id, h := range fakeProfiles {
+				if h == handle {
+					data["profile"] = map[string]string{"id": id}
+				}
+			}
+		case strings.HasPrefix(req.Query, "query FollowStatus("):
+			data["profile"], data["target"] = profile(str("profile")), profile(str("target"))
+			if data["profile"] == nil || data["target"] == nil {
+				// followStatusBulk fails as a whole on an unknown profile, the aliases answer null
+				answer["errors"] = []any{map[string]any{"message": "không tồn tại", "extensions": map[string]string{"code": "NOT_FOUND"}}}
+				data["followStatusBulk"] = nil
+				break
+			}
+			data["followStatusBulk"] = []any{
+				map[string]any{"follower": "0x01", "profileId": "0x02", "status": map[string]bool{"value": str("profile") == "0x01"}},
+				map[string]any{"follower": "0x02", "profileId": "0x01", "status": map[string]bool{"value": false}},
+			}
+		case strings.HasPrefix(req.Query, "query Mirrored("):
+			data["profile"] = profile(str("profile"))
+			data["publication"], data["matches"] = nil, map[string]any{"items": []any{}}
+			if str("pub") == "0x02-0x01" {
+				data["publication"] = map[string]string{"__typename": "Post"}
+				if str("profile") == "0x01" {
+					data["matches"] = map[string]any{"items": []any{map[string]string{"__typename": "Mirror"}}}
+				}
+			}
+		default:
+			t.Errorf("unexpected query %q", req.Query)
+		}
+		if len(data) > 0 {
+			answer["data"] = data
+		}
+		json.NewEncoder(w).Encode(answer)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("LENS_API_URL", srv.URL)
+	t.Setenv("LENS_PROVIDER", "")
+}
+
+func TestCheckFollow(t *testing.T) {
+	fakeLensAPI(t)
+
+	res, err := CheckFollow("lens/alice", "0x02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Following || res.FollowedBy {
+		t.Errorf("got following=%v followed_by=%v, want true false", res.Following, res.FollowedBy)
+	}
+
+	errTests := []struct {
+		name   string
+		user   string
+		target string
+		want   error
+	}{
+		{"missing target profile id", "0x01", "0x99", ErrTargetNotFound},
+		{"missing target handle", "0x01", "lens/nobody", ErrTargetNotFound},
+		{"invalid target", "0x01", "not a profile!", ErrInvalidTarget},
+		{"missing user profile id", "0x98", "0x02", ErrUserNotFound},
+		{"missing user handle", "lens/ghost", "0x02", ErrUserNotFound},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CheckFollow(tt.user, tt.target)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want == ErrTargetNotFound && errors.Is(err, ErrUserNotFound) {
+				t.Fatalf("target error %v also matches ErrUserNotFound", err)
+			}
+		})
+	}
+}
+
+func TestCheckPublication(t *testing.T) {
+	fakeLensAPI(t)
+
+	res, err := CheckPublication("0x01", "0x02-0x01", ActionMirror)
+	if err != nil || !res.Done {
+		t.Fatalf("mirrored: %+v, %v", res, err)
+	}
+	if res, err = CheckPublication("0x02", "0x02-0x01", ActionMirror); err != nil || res.Done {
+		t.Fatalf("not mirrored: %+v, %v", res, err)
+	}
+	if _, err := CheckPublication("0x01", "0x02-0x99", ActionMirror); !errors.Is(err, ErrPublicationNotFound) {
+		t.Fatalf("missing publication: got %v, want ErrPublicationNotFound", err)
+	}
+	if _, err := CheckPublication("0x97", "0x02-0x01", ActionMirror); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("missing profile: got %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestGraphQLErrorCodes(t *testing.T) {
+	fakeLensAPI(t)
+
+	tests := []struct {
+		handle string
+		want   error
+	}{
+		{"lens/code_not_found", ErrUserNotFound},
+		{"lens/code_bad_user_input", ErrInvalidIdentifier},
+		{"lens/code_graphql_validation_failed", ErrInvalidIdentifier},
+		{"lens/code_too_many_requests", ErrRateLimited},
+	}
+	for _, tt := range tests {
+		if _, err := ResolveProfileID(t.Context(), tt.handle); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.handle, err, tt.want)
+		}
+	}
+
+	_, err := ResolveProfileID(t.Context(), "lens/code_internal_server_error")
+	for _, sentinel := range []error{ErrUserNotFound, ErrInvalidIdentifier, ErrRateLimited} {
+		if errors.Is(err, sentinel) {
+			t.Errorf("unknown code mapped to %v: %v", sentinel, err)
+		}
+	}
+	if err == nil {
+		t.Error("unknown code: got no error")
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	for _, retryAfter := range []string{"12", ""} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		t.Setenv("LENS_API_URL", srv.URL)
+
+		// A handle no other test resolved, so the resolve cache cannot answer
+		_, err := ResolveProfileID(t.Context(), "lens/limited")
+		srv.Close()
+		var rl *ratelimit.Error
+		if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) {
+			t.Fatalf("Retry-After %q: got %v, want ErrRateLimited", retryAfter, err)
+		}
+		if want := 12 * time.Second; retryAfter != "" && rl.RetryAfter != want {
+			t.Errorf("retry after %s, want %s", rl.RetryAfter, want)
+		}
+		if retryAfter == "" && (rl.RetryAfter != 0 || rl.Detail != "") {
+			t.Errorf("without Retry-After: got %s %q, want no hint", rl.RetryAfter, rl.Detail)
+		}
+	}
+}
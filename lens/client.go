@@ -0,0 +1,116 @@
+package lens
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Actions supported by CheckFollow and CheckPublication
+const (
+	ActionFollow  = "follow"
+	ActionMirror  = "mirror"
+	ActionCollect = "collect"
+)
+
+// Relationship is the profile's relationship to a target profile
+type Relationship struct {
+	// Following: the profile follows the target
+	Following bool
+	// FollowedBy: the target follows the profile
+	FollowedBy bool
+}
+
+// FollowResult is the outcome of CheckFollow together with the resolved profile IDs
+type FollowResult struct {
+	ProfileID string
+	TargetID  string
+	// Source is the Provider name
+	Source string
+	Relationship
+}
+
+// CheckFollow checks if the profile follows target; empty target uses LENS_TARGET_PROFILE
+func CheckFollow(userID string, target string) (*FollowResult, error) {
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if strings.TrimSpace(target) == "" {
+		target = os.Getenv("LENS_TARGET_PROFILE")
+	}
+	targetID, err := resolveTargetProfileID(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	profileID, err := ResolveProfileID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	rel, err := provider.FetchRelationship(ctx, profileID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &FollowResult{ProfileID: profileID, TargetID: targetID, Source: provider.Name(), Relationship: rel}
+	log.Printf("[Lens][DEBUG] CheckFollow profile=%s target=%s following=%v followed_by=%v", profileID, targetID, res.Following, res.FollowedBy)
+	return res, nil
+}
+
+// PublicationResult is the outcome of CheckPublication
+type PublicationResult struct {
+	ProfileID     string
+	PublicationID string
+	Done          bool
+	Source        string
+}
+
+// CheckPublication checks if the profile mirrored or collected the publication
+func CheckPublication(userID string, publication string, action string) (*PublicationResult, error) {
+	_ = godotenv.Load()
+
+	publicationID, err := ParsePublicationID(publication)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	profileID, err := ResolveProfileID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	res := &PublicationResult{ProfileID: profileID, PublicationID: publicationID, Source: provider.Name()}
+
+	switch action {
+	case ActionMirror:
+		res.Done, err = provider.HasMirrored(ctx, profileID, publicationID)
+	case ActionCollect:
+		res.Done, err = provider.HasCollected(ctx, profileID, publicationID)
+	default:
+		return nil, fmt.Errorf("unsupported lens publication action %q", action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Lens][DEBUG] CheckPublication profile=%s publication=%s action=%s done=%v", profileID, publicationID, action, res.Done)
+	return res, nil
+}
@@ -0,0 +1,58 @@
+package lens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Provider names selectable with LENS_PROVIDER
+const (
+	ProviderAPI = "lens_api"
+)
+
+// Errors mirror the farcaster package: identifier/target/publication validation, not found and rate limiting
+var (
+	// ErrInvalidIdentifier is returned when an identifier is neither a profile ID, handle, profile URL nor ETH address
+	ErrInvalidIdentifier = errors.New("invalid lens identifier")
+	// ErrUserNotFound is returned when no profile matches the identifier
+	ErrUserNotFound = errors.New("lens profile not found")
+	// ErrInvalidPublicationID is returned when a publication is neither "0x..-0x.." nor a publication URL
+	ErrInvalidPublicationID = errors.New("invalid lens publication id")
+	// ErrInvalidTarget is returned when the target of a follow check is not a valid identifier
+	ErrInvalidTarget = errors.New("invalid lens target")
+	// ErrTargetNotFound is returned when no profile matches the target of a follow check
+	ErrTargetNotFound = errors.New("lens target profile not found")
+	// ErrPublicationNotFound is returned when the publication does not exist or was hidden
+	ErrPublicationNotFound = errors.New("lens publication not found")
+	// ErrRateLimited is returned when the API answered 429
+	ErrRateLimited = errors.New("lens rate limited")
+	// ErrNotSupported is returned when the configured provider cannot answer a lookup
+	ErrNotSupported = errors.New("not supported by lens provider")
+)
+
+// Provider is a Lens data backend used by the follow and publication checks
+type Provider interface {
+	// Name identifies the provider in results (see FollowResult.Source)
+	Name() string
+	// LookupProfile resolves a handle ("lens/alice") or profile ID to a profile ID
+	LookupProfile(ctx context.Context, kind string, value string) (string, error)
+	// FetchRelationship returns whether the profile follows the target and is followed by it
+	FetchRelationship(ctx context.Context, profileID string, targetID string) (Relationship, error)
+	// HasMirrored checks if the profile mirrored the publication
+	HasMirrored(ctx context.Context, profileID string, publicationID string) (bool, error)
+	// HasCollected checks if the profile collected (acted on) the publication
+	HasCollected(ctx context.Context, profileID string, publicationID string) (bool, error)
+}
+
+// NewProvider creates the provider selected by LENS_PROVIDER ("lens_api" by default)
+func NewProvider() (Provider, error) {
+	switch name := strings.ToLower(strings.TrimSpace(os.Getenv("LENS_PROVIDER"))); name {
+	case "", ProviderAPI:
+		return NewAPIClient(), nil
+	default:
+		return nil, fmt.Errorf("unknown LENS_PROVIDER %q", name)
+	}
+}
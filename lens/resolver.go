@@ -0,0 +1,194 @@
+package lens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultResolveCacheTTL = 24 * time.Hour
+
+// Identifier kinds returned by parseIdentifier
+const (
+	kindProfileID = "profile_id"
+	kindHandle    = "handle"
+	kindAddress   = "address"
+)
+
+var (
+	ethAddressPattern    = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	profileIDPattern     = regexp.MustCompile(`^0x[0-9a-f]{1,39}$`)
+	localNamePattern     = regexp.MustCompile(`^[a-z0-9][a-z0-9_]{0,30}$`)
+	publicationIDPattern = regexp.MustCompile(`^0x[0-9a-f]+-0x[0-9a-f]+(-da-[0-9a-f-]+)?$`)
+
+	// profileHosts are the web clients whose URLs carry a handle (/u/<name>) or profile ID (/profile/<id>)
+	profileHosts = map[string]bool{
+		"hey.xyz":     true,
+		"www.hey.xyz": true,
+	}
+)
+
+// resolveCacheEntry is a cached identifier -> profile ID mapping
+type resolveCacheEntry struct {
+	profileID string
+	expiresAt time.Time
+}
+
+// resolveCache keeps resolved identifier mappings in memory so repeated checks don't hit the API
+var resolveCache = struct {
+	sync.RWMutex
+	entries map[string]resolveCacheEntry
+}{entries: map[string]resolveCacheEntry{}}
+
+// ResolveProfileID turns a user supplied identifier into a profile ID.
+// Accepted inputs:
+//   - a profile ID ("0x01a6")
+//   - a handle ("lens/alice", "alice.lens", "@alice" or "alice")
+//   - a hey.xyz URL ("https://hey.xyz/u/alice" or "https://hey.xyz/profile/0x01a6")
+//   - an ETH address owning a default profile ("0xabc...")
+//
+// Handles and addresses are resolved through the configured provider and cached (LENS_RESOLVE_CACHE_TTL, default 24h).
+func ResolveProfileID(ctx context.Context, identifier string) (string, error) {
+	kind, value, err := parseIdentifier(identifier)
+	if err != nil {
+		return "", err
+	}
+	if kind == kindProfileID {
+		return value, nil
+	}
+
+	cacheKey := kind + ":" + value
+	if id, ok := cachedProfileID(cacheKey); ok {
+		return id, nil
+	}
+
+	provider, err := NewProvider()
+	if err != nil {
+		return "", err
+	}
+	id, err := provider.LookupProfile(ctx, kind, value)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s %q: %w", kind, value, err)
+	}
+
+	log.Printf("[Lens][DEBUG] ResolveProfileID %s=%s profile=%s", kind, value, id)
+	storeProfileID(cacheKey, id)
+	return id, nil
+}
+
+// resolveTargetProfileID resolves a profile used as a follow target, reporting failures as target errors
+func resolveTargetProfileID(ctx context.Context, identifier string) (string, error) {
+	id, err := ResolveProfileID(ctx, identifier)
+	switch {
+	case errors.Is(err, ErrInvalidIdentifier):
+		return "", fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, ErrUserNotFound):
+		return "", fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	}
+	return id, err
+}
+
+// parseIdentifier classifies an identifier and returns its normalized value
+func parseIdentifier(identifier string) (kind string, value string, err error) {
+	s := strings.TrimSpace(identifier)
+	if s == "" {
+		return "", "", ErrInvalidIdentifier
+	}
+
+	if strings.Contains(s, "/") && !strings.HasPrefix(strings.ToLower(s), "lens/") {
+		segments, ok := heyPath(s)
+		if !ok || len(segments) < 2 {
+			return "", "", fmt.Errorf("%w: unsupported profile URL %q", ErrInvalidIdentifier, identifier)
+		}
+		switch segments[0] {
+		case "u":
+			s = segments[1]
+		case "profile":
+			s = segments[1]
+		default:
+			return "", "", fmt.Errorf("%w: unsupported profile URL %q", ErrInvalidIdentifier, identifier)
+		}
+	}
+
+	if ethAddressPattern.MatchString(s) {
+		return kindAddress, strings.ToLower(s), nil
+	}
+	lower := strings.ToLower(s)
+	if profileIDPattern.MatchString(lower) {
+		return kindProfileID, normalizeProfileID(lower), nil
+	}
+
+	name := strings.TrimPrefix(lower, "@")
+	name = strings.TrimPrefix(name, "lens/")
+	name = strings.TrimSuffix(name, ".lens")
+	if !localNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidIdentifier, identifier)
+	}
+	return kindHandle, "lens/" + name, nil
+}
+
+// normalizeProfileID pads the hex digits to an even length ("0x5" -> "0x05") as the API returns them
+func normalizeProfileID(id string) string {
+	if len(id)%2 == 1 {
+		return "0x0" + id[2:]
+	}
+	return id
+}
+
+// ParsePublicationID accepts a publication ID ("0x01-0x2a") or a hey.xyz post URL
+func ParsePublicationID(publication string) (string, error) {
+	s := strings.TrimSpace(publication)
+	if strings.Contains(s, "/") {
+		segments, ok := heyPath(s)
+		if !ok || len(segments) != 2 || segments[0] != "posts" {
+			return "", fmt.Errorf("%w: %q", ErrInvalidPublicationID, publication)
+		}
+		s = segments[1]
+	}
+	s = strings.ToLower(s)
+	if !publicationIDPattern.MatchString(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPublicationID, publication)
+	}
+	return s, nil
+}
+
+// heyPath returns the path segments of raw when it is a hey.xyz URL
+func heyPath(raw string) ([]string, bool) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !profileHosts[strings.ToLower(u.Host)] {
+		return nil, false
+	}
+	return strings.Split(strings.Trim(u.Path, "/"), "/"), true
+}
+
+func cachedProfileID(key string) (string, bool) {
+	resolveCache.RLock()
+	defer resolveCache.RUnlock()
+	entry, ok := resolveCache.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", false
+	}
+	return entry.profileID, true
+}
+
+func storeProfileID(key string, profileID string) {
+	ttl := defaultResolveCacheTTL
+	if v := os.Getenv("LENS_RESOLVE_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			ttl = d
+		}
+	}
+	resolveCache.Lock()
+	defer resolveCache.Unlock()
+	resolveCache.entries[key] = resolveCacheEntry{profileID: profileID, expiresAt: time.Now().Add(ttl)}
+}
@@ -1,9 +1,10 @@
 package main
 
 import (
-	"checkingsocial/internal/handler"
-	"checkingsocial/internal/service"
+	"checkingsocial/internal/app"
+	"checkingsocial/pkg/cache"
 	"log"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -13,6 +14,14 @@ func main() {
 	// Load environment variables from .env file
 	_ = godotenv.Load()
 
+	// Connect to Redis when configured; webhook-fed follow state lives there
+	if os.Getenv("REDIS_ADDR") != "" {
+		if err := cache.InitRedis(); err != nil {
+			log.Fatalf("Failed to init Redis: %v", err)
+		}
+		defer cache.Close()
+	}
+
 	// Set Gin to release mode for production
 	gin.SetMode(gin.ReleaseMode)
 
@@ -20,12 +29,12 @@ func main() {
 	router := gin.New()
 	router.Use(gin.Recovery()) // Add recovery middleware to catch panics
 
-	// Dependency Injection: Create instances
-	socialCheckerService := service.NewSocialChecker()
-	socialHandler := handler.NewSocialHandler(socialCheckerService)
-
-	// Register routes
-	socialHandler.RegisterRoutes(router)
+	// Build the services with the full decorator chain and register their routes
+	cleanup, err := app.Setup(router)
+	if err != nil {
+		log.Fatalf("Failed to set up services: %v", err)
+	}
+	defer cleanup()
 
 	// Configure server address and port
 	serverAddr := ":8080"
@@ -0,0 +1,167 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// activityJSON is the Accept header for ActivityPub objects
+const activityJSON = `application/activity+json, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+// defaultMaxPages bounds how many pages of a following collection are scanned
+const defaultMaxPages = 50
+
+// errHidden is returned by the collection walk when the instance does not disclose the collection
+var errHidden = errors.New("collection hidden")
+
+// collection is an (Ordered)Collection or (Ordered)CollectionPage. first, next and items may be
+// links or embedded objects.
+type collection struct {
+	Type         string            `json:"type"`
+	TotalItems   *int64            `json:"totalItems"`
+	First        json.RawMessage   `json:"first"`
+	Next         json.RawMessage   `json:"next"`
+	OrderedItems []json.RawMessage `json:"orderedItems"`
+	Items        []json.RawMessage `json:"items"`
+}
+
+// items returns the IDs of the items on this page
+func (c *collection) items() []string {
+	raw := c.OrderedItems
+	if raw == nil {
+		raw = c.Items
+	}
+	ids := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if id := objectID(item); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// objectID returns the ID of a link ("https://...") or an embedded object ({"id": "https://..."})
+func objectID(raw json.RawMessage) string {
+	var id string
+	if json.Unmarshal(raw, &id) == nil {
+		return id
+	}
+	var obj struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(raw, &obj) == nil {
+		return obj.ID
+	}
+	return ""
+}
+
+// fetchObject fetches an ActivityPub object; 401 and 403 (authorized fetch, private profiles) are errHidden
+func fetchObject(ctx context.Context, id string, out any) error {
+	status, err := getJSON(ctx, id, activityJSON, out)
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: the instance requires authorization", errHidden)
+	case http.StatusNotFound, http.StatusGone:
+		return fmt.Errorf("%w: object not served", ErrUserNotFound)
+	}
+	return err
+}
+
+// FollowingContains walks the actor's following collection looking for targetActorID.
+// Only https URLs on the actor's own instance are fetched; embedded pages are read in place.
+// It returns errHidden when the instance hides the list, does not serve it, links it elsewhere, or the list
+// is longer than MASTODON_MAX_PAGES pages (default 50).
+func FollowingContains(ctx context.Context, actorID string, targetActorID string) (bool, error) {
+	u, err := url.Parse(actorID)
+	if err != nil || !onInstance(actorID, u.Host) {
+		return false, fmt.Errorf("%w: actor is not an https url", ErrInvalidAcct)
+	}
+	domain := u.Host
+
+	var actor struct {
+		Following string `json:"following"`
+	}
+	if err := fetchObject(ctx, actorID, &actor); err != nil {
+		return false, err
+	}
+	if actor.Following == "" {
+		return false, fmt.Errorf("%w: the actor has no following collection", errHidden)
+	}
+	if !onInstance(actor.Following, domain) {
+		log.Printf("[Mastodon][WARN] FollowingContains actor=%s following=%s is not on the instance", actorID, actor.Following)
+		return false, fmt.Errorf("%w: the following collection is not on the user's instance", errHidden)
+	}
+
+	var root collection
+	if err := fetchObject(ctx, actor.Following, &root); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return false, fmt.Errorf("%w: the following collection is not served", errHidden)
+		}
+		return false, err
+	}
+
+	page := &root
+	if root.OrderedItems == nil && root.Items == nil {
+		// Mastodon serves only totalItems when the user hides their follows
+		if len(root.First) == 0 || string(root.First) == "null" {
+			if root.TotalItems != nil && *root.TotalItems == 0 {
+				return false, nil
+			}
+			return false, fmt.Errorf("%w: the following collection lists no items", errHidden)
+		}
+		// The walk starts at the first page, which may be a link or embedded
+		page = &collection{Next: root.First}
+	}
+
+	maxPages := defaultMaxPages
+	if v, err := strconv.Atoi(os.Getenv("MASTODON_MAX_PAGES")); err == nil && v > 0 {
+		maxPages = v
+	}
+	for i := 0; i < maxPages; i++ {
+		for _, id := range page.items() {
+			if id == targetActorID {
+				return true, nil
+			}
+		}
+		if embedded, err := embeddedPage(page.Next); err == nil {
+			page = embedded
+			continue
+		}
+		next := objectID(page.Next)
+		if next == "" {
+			return false, nil
+		}
+		if !onInstance(next, domain) {
+			log.Printf("[Mastodon][WARN] FollowingContains actor=%s page=%s is not on the instance", actorID, next)
+			return false, fmt.Errorf("%w: a following page is not on the user's instance", errHidden)
+		}
+		page = &collection{}
+		if err := fetchObject(ctx, next, page); err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				return false, fmt.Errorf("%w: a following page is not served", errHidden)
+			}
+			return false, err
+		}
+	}
+	log.Printf("[Mastodon][WARN] FollowingContains actor=%s stopped after %d pages", actorID, maxPages)
+	return false, fmt.Errorf("%w: the following collection is longer than %d pages", errHidden, maxPages)
+}
+
+// embeddedPage decodes raw when it is an embedded page object rather than a link
+func embeddedPage(raw json.RawMessage) (*collection, error) {
+	var page collection
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return nil, err
+	}
+	if page.OrderedItems == nil && page.Items == nil {
+		return nil, errors.New("not an embedded page")
+	}
+	return &page, nil
+}
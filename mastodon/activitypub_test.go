@@ -0,0 +1,208 @@
+package mastodon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// fakeInstance serves WebFinger and ActivityPub actors over TLS for these users:
+//   - linked: following links its first page, which links a second page listing target
+//   - embedded: following embeds its first page, which embeds a second page listing target
+//   - unauthorized, forbidden: following answers 401 and 403
+//   - counted: following serves only totalItems; nobody follows no one (totalItems 0)
+//   - endless: every page links another page without target
+//   - offsite: following points at a link-local address
+//   - elsewhere: WebFinger names an actor on another host
+//   - huge: the actor document is larger than maxResponseBytes
+func fakeInstance(t *testing.T) string {
+	t.Helper()
+	var srv *httptest.Server
+	base := func() string { return srv.URL }
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/webfinger", func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Query().Get("resource"), "acct:"), "@")
+		actor := base() + "/users/" + user
+		if user == "elsewhere" {
+			actor = "https://other.example/users/elsewhere"
+		}
+		fmt.Fprintf(w, `{"links":[{"rel":"self","type":"application/activity+json","href":%q}]}`, actor)
+	})
+	mux.HandleFunc("GET /users/{name}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		switch name {
+		case "offsite":
+			fmt.Fprint(w, `{"following":"https://169.254.169.254/latest/meta-data"}`)
+		case "huge":
+			fmt.Fprintf(w, `{"following":%q}`, strings.Repeat("x", maxResponseBytes))
+		default:
+			fmt.Fprintf(w, `{"following":%q}`, base()+"/users/"+name+"/following")
+		}
+	})
+	mux.HandleFunc("GET /users/{name}/following", func(w http.ResponseWriter, r *http.Request) {
+		following := base() + "/users/" + r.PathValue("name") + "/following"
+		target := base() + "/users/target"
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		switch r.PathValue("name") {
+		case "linked":
+			switch page {
+			case 0:
+				fmt.Fprintf(w, `{"type":"OrderedCollection","totalItems":2,"first":%q}`, following+"?page=1")
+			case 1:
+				fmt.Fprintf(w, `{"type":"OrderedCollectionPage","orderedItems":[%q],"next":%q}`, base()+"/users/other", following+"?page=2")
+			default:
+				fmt.Fprintf(w, `{"type":"OrderedCollectionPage","orderedItems":[{"id":%q}]}`, target)
+			}
+		case "embedded":
+			fmt.Fprintf(w, `{"type":"Collection","first":{"type":"CollectionPage","items":[],"next":{"type":"CollectionPage","items":[%q]}}}`, target)
+		case "unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		case "counted":
+			fmt.Fprint(w, `{"type":"OrderedCollection","totalItems":12}`)
+		case "nobody":
+			fmt.Fprint(w, `{"type":"OrderedCollection","totalItems":0}`)
+		case "endless":
+			fmt.Fprintf(w, `{"type":"OrderedCollectionPage","orderedItems":[%q],"next":%q}`, base()+"/users/other", following+"?page="+strconv.Itoa(page+1))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	srv = httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	// The real client refuses the loopback address the fake listens on, which TestRemoteClientRefusesInternalAddresses covers
+	client := remoteClient
+	remoteClient = srv.Client()
+	t.Cleanup(func() { remoteClient = client })
+	t.Setenv("MASTODON_INSTANCE_URL", "")
+	t.Setenv("MASTODON_MAX_PAGES", "3")
+	return strings.TrimPrefix(srv.URL, "https://")
+}
+
+func TestCheckFollowOverActivityPub(t *testing.T) {
+	host := fakeInstance(t)
+	target := "target@" + host
+
+	for _, user := range []string{"linked", "embedded"} {
+		res, err := CheckFollow(user+"@"+host, target)
+		if err != nil {
+			t.Fatalf("%s: %v", user, err)
+		}
+		if !res.Following || !res.Verifiable || res.Source != SourceActivityPub {
+			t.Errorf("%s: got following=%v verifiable=%v source=%s, want a verified follow", user, res.Following, res.Verifiable, res.Source)
+		}
+	}
+
+	res, err := CheckFollow("nobody@"+host, target)
+	if err != nil {
+		t.Fatalf("nobody: %v", err)
+	}
+	if res.Following || !res.Verifiable {
+		t.Errorf("empty following: got following=%v verifiable=%v, want a verified false", res.Following, res.Verifiable)
+	}
+
+	for _, user := range []string{"unauthorized", "forbidden", "counted", "endless", "offsite"} {
+		res, err := CheckFollow(user+"@"+host, target)
+		if err != nil {
+			t.Fatalf("%s: %v", user, err)
+		}
+		if res.Following || res.Verifiable || res.Reason == "" {
+			t.Errorf("%s: got following=%v verifiable=%v reason=%q, want an unverifiable result", user, res.Following, res.Verifiable, res.Reason)
+		}
+		if strings.Contains(res.Reason, "https://") || strings.Contains(res.Reason, "40") {
+			t.Errorf("%s: reason %q leaks a remote url or status", user, res.Reason)
+		}
+	}
+	if res, _ := CheckFollow("endless@"+host, target); !strings.Contains(res.Reason, "3 pages") {
+		t.Errorf("endless: reason %q, want the page cap", res.Reason)
+	}
+
+	if _, err := CheckFollow("elsewhere@"+host, target); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("actor on another host: got %v, want ErrUserNotFound", err)
+	}
+	if _, err := CheckFollow("huge@"+host, target); err == nil || strings.Contains(err.Error(), "xxx") {
+		t.Errorf("oversized actor: got %v, want a bounded read error", err)
+	}
+}
+
+func TestRemoteClientRefusesInternalAddresses(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("internal address reached: %s", r.URL)
+	}))
+	defer srv.Close()
+
+	var out struct{}
+	if _, err := getJSON(t.Context(), srv.URL+"/users/alice", activityJSON, &out); !errors.Is(err, errInternalAddress) {
+		t.Fatalf("loopback instance: got %v, want errInternalAddress", err)
+	}
+	for _, addr := range []string{"10.0.0.1:443", "192.168.1.1:443", "169.254.169.254:80", "[::1]:443", "[fe80::1]:443", "100.64.0.1:443", "0.0.0.0:443"} {
+		if err := denyInternalAddress("tcp", addr, nil); !errors.Is(err, errInternalAddress) {
+			t.Errorf("%s: got %v, want errInternalAddress", addr, err)
+		}
+	}
+	if err := denyInternalAddress("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("public address refused: %v", err)
+	}
+}
+
+func TestResolveCacheEviction(t *testing.T) {
+	defer func(max int) { maxResolveCacheEntries = max }(maxResolveCacheEntries)
+	maxResolveCacheEntries = 2
+	resolveCache.Lock()
+	resolveCache.entries = map[string]resolveCacheEntry{
+		"stale@example.com": {actorID: "https://example.com/users/stale", expiresAt: time.Now().Add(-time.Minute)},
+	}
+	resolveCache.Unlock()
+
+	if _, ok := cachedActor("stale@example.com"); ok {
+		t.Fatalf("expired entry answered from the cache")
+	}
+	for i := 0; i < 5; i++ {
+		storeActor("u"+strconv.Itoa(i)+"@example.com", "https://example.com/users/u"+strconv.Itoa(i))
+	}
+	resolveCache.RLock()
+	size := len(resolveCache.entries)
+	resolveCache.RUnlock()
+	if size > maxResolveCacheEntries {
+		t.Fatalf("cache holds %d entries, want at most %d", size, maxResolveCacheEntries)
+	}
+	if actor, ok := cachedActor("u4@example.com"); !ok || actor != "https://example.com/users/u4" {
+		t.Fatalf("latest entry = %q, %v", actor, ok)
+	}
+}
+
+func TestAPIClientRateLimited(t *testing.T) {
+	reset := time.Now().Add(90 * time.Second).UTC().Format("2006-01-02T15:04:05.000000Z")
+	for _, header := range []string{reset, ""} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if header != "" {
+				w.Header().Set("X-RateLimit-Reset", header)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		t.Setenv("MASTODON_INSTANCE_URL", srv.URL)
+		t.Setenv("MASTODON_ACCESS_TOKEN", "limited-"+strconv.Itoa(len(header)))
+
+		_, err := NewAPIClient().Acct(t.Context())
+		srv.Close()
+		var rl *ratelimit.Error
+		if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) {
+			t.Fatalf("reset %q: got %v, want ErrRateLimited", header, err)
+		}
+		if header != "" && (rl.RetryAfter < 80*time.Second || rl.RetryAfter > 90*time.Second) {
+			t.Errorf("retry after %s, want about 90s", rl.RetryAfter)
+		}
+		if header == "" && (rl.RetryAfter != 0 || rl.Detail != "") {
+			t.Errorf("without reset: got %s %q, want no hint", rl.RetryAfter, rl.Detail)
+		}
+	}
+}
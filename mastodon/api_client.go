@@ -0,0 +1,147 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// ownAccts caches the account behind each access token; it does not change for the token's lifetime
+var ownAccts sync.Map
+
+// APIClient calls the Mastodon REST API of our own instance with our account's token. The relationships
+// API answers from the instance's own follow records, so it works even when the user hides their follows.
+type APIClient struct {
+	baseURL     string
+	host        string
+	accessToken string
+	httpClient  *http.Client
+}
+
+// NewAPIClient creates a client for our account, or returns nil when it is not configured.
+// Config via ENV:
+//   - MASTODON_INSTANCE_URL: base URL of the instance hosting our account (e.g. https://mastodon.social)
+//   - MASTODON_ACCESS_TOKEN: access token of our account with the read:accounts and read:search scopes
+func NewAPIClient() *APIClient {
+	baseURL := strings.TrimRight(os.Getenv("MASTODON_INSTANCE_URL"), "/")
+	token := os.Getenv("MASTODON_ACCESS_TOKEN")
+	if baseURL == "" || token == "" {
+		return nil
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	return &APIClient{baseURL: baseURL, host: strings.ToLower(u.Host), accessToken: token, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// get performs a GET on the instance API and decodes the JSON response into out
+func (c *APIClient) get(ctx context.Context, path string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		reset, err := time.Parse(time.RFC3339, resp.Header.Get("X-RateLimit-Reset"))
+		if err != nil {
+			return ratelimit.New(ErrRateLimited, 0, "")
+		}
+		return ratelimit.New(ErrRateLimited, time.Until(reset), "resets at "+reset.UTC().Format(time.RFC3339))
+	default:
+		return fmt.Errorf("mastodon %s failed with status %d: %s", path, resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// Acct returns our account as user@instance
+func (c *APIClient) Acct(ctx context.Context) (string, error) {
+	if acct, ok := ownAccts.Load(c.baseURL + " " + c.accessToken); ok {
+		return acct.(string), nil
+	}
+	var out struct {
+		Acct string `json:"acct"`
+	}
+	if err := c.get(ctx, "/api/v1/accounts/verify_credentials", url.Values{}, &out); err != nil {
+		return "", err
+	}
+	acct := c.fullAcct(out.Acct)
+	ownAccts.Store(c.baseURL+" "+c.accessToken, acct)
+	return acct, nil
+}
+
+// fullAcct adds our instance host to local accounts, which the API reports without a domain
+func (c *APIClient) fullAcct(acct string) string {
+	if !strings.Contains(acct, "@") {
+		acct += "@" + c.host
+	}
+	return strings.ToLower(acct)
+}
+
+// LookupAccountID resolves acct to its ID on our instance, fetching remote accounts the instance has not seen yet
+func (c *APIClient) LookupAccountID(ctx context.Context, acct string) (string, error) {
+	q := url.Values{}
+	q.Set("q", "@"+acct)
+	q.Set("type", "accounts")
+	q.Set("resolve", "true")
+	q.Set("limit", "5")
+	var out struct {
+		Accounts []struct {
+			ID   string `json:"id"`
+			Acct string `json:"acct"`
+		} `json:"accounts"`
+	}
+	if err := c.get(ctx, "/api/v2/search", q, &out); err != nil {
+		return "", err
+	}
+	for _, account := range out.Accounts {
+		if c.fullAcct(account.Acct) == acct {
+			return account.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUserNotFound, acct)
+}
+
+// FollowsUs reports whether the account follows our account (followed_by from our point of view)
+func (c *APIClient) FollowsUs(ctx context.Context, accountID string) (bool, error) {
+	q := url.Values{}
+	q.Set("id[]", accountID)
+	var out []struct {
+		ID         string `json:"id"`
+		FollowedBy bool   `json:"followed_by"`
+	}
+	if err := c.get(ctx, "/api/v1/accounts/relationships", q, &out); err != nil {
+		return false, err
+	}
+	for _, rel := range out {
+		if rel.ID == accountID {
+			return rel.FollowedBy, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,96 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// ActionFollow is the action supported by CheckFollow
+const ActionFollow = "follow"
+
+// Sources reported by FollowResult.Source
+const (
+	// SourceAPI: the relationships API of our own instance answered
+	SourceAPI = "mastodon_api"
+	// SourceActivityPub: the user's following collection was read from their instance
+	SourceActivityPub = "activitypub"
+)
+
+// FollowResult is the outcome of CheckFollow
+type FollowResult struct {
+	// User and Target are the accounts as user@instance
+	User   string
+	Target string
+	Source string
+	// Following: the user follows the target; only meaningful when Verifiable
+	Following bool
+	// Verifiable is false when the user's instance hides who they follow, so Following could not be checked
+	Verifiable bool
+	// Reason explains why the check was not verifiable
+	Reason string
+}
+
+// CheckFollow checks if the user follows target; empty target uses MASTODON_TARGET.
+// When target is the account behind MASTODON_ACCESS_TOKEN the relationships API of our instance answers;
+// otherwise the user's following collection is fetched over ActivityPub.
+func CheckFollow(userID string, target string) (*FollowResult, error) {
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if strings.TrimSpace(target) == "" {
+		target = os.Getenv("MASTODON_TARGET")
+	}
+	targetAcct, targetActor, err := resolveTarget(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	userAcct, err := ParseAcct(userID)
+	if err != nil {
+		return nil, err
+	}
+	res := &FollowResult{User: userAcct, Target: targetAcct, Verifiable: true}
+
+	if api := NewAPIClient(); api != nil {
+		ours, err := api.Acct(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ours == targetAcct {
+			accountID, err := api.LookupAccountID(ctx, userAcct)
+			if err != nil {
+				return nil, err
+			}
+			if res.Following, err = api.FollowsUs(ctx, accountID); err != nil {
+				return nil, err
+			}
+			res.Source = SourceAPI
+			log.Printf("[Mastodon][DEBUG] CheckFollow user=%s target=%s source=%s following=%v", userAcct, targetAcct, res.Source, res.Following)
+			return res, nil
+		}
+	}
+
+	_, userActor, err := ResolveActor(ctx, userAcct)
+	if err != nil {
+		return nil, err
+	}
+	res.Source = SourceActivityPub
+	res.Following, err = FollowingContains(ctx, userActor, targetActor)
+	if errors.Is(err, errHidden) {
+		res.Verifiable, res.Reason = false, err.Error()
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Mastodon][DEBUG] CheckFollow user=%s target=%s source=%s following=%v verifiable=%v", userAcct, targetAcct, res.Source, res.Following, res.Verifiable)
+	return res, nil
+}
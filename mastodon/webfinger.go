@@ -0,0 +1,310 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+const defaultResolveCacheTTL = 24 * time.Hour
+
+// maxResolveCacheEntries bounds the resolve cache; expired entries are swept first when it is full
+var maxResolveCacheEntries = 10000
+
+// maxResponseBytes bounds how much of a remote instance's answer is read
+const maxResponseBytes = 1 << 20
+
+var (
+	// ErrInvalidAcct is returned when an identifier is not a user@instance address or profile URL
+	ErrInvalidAcct = errors.New("invalid mastodon account")
+	// ErrUserNotFound is returned when WebFinger does not know the account
+	ErrUserNotFound = errors.New("mastodon user not found")
+	// ErrInvalidTarget is returned when the target account cannot be parsed
+	ErrInvalidTarget = errors.New("invalid mastodon target")
+	// ErrTargetNotFound is returned when WebFinger does not know the target account
+	ErrTargetNotFound = errors.New("mastodon target not found")
+	// ErrRateLimited is returned when an instance answered 429
+	ErrRateLimited = errors.New("mastodon rate limited")
+
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+	domainPattern   = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?(:[0-9]{1,5})?$`)
+
+	// errInternalAddress is returned by the dialer when a remote instance resolves to a non-public address
+	errInternalAddress = errors.New("instance resolves to a non-public address")
+	// errOffInstance is returned when a remote URL is not https on the account's instance
+	errOffInstance = errors.New("url is not on the account's instance")
+
+	// cgnatPrefix is the shared address space (RFC 6598), not routable on the internet
+	cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// remoteClient fetches WebFinger and ActivityPub documents from instances named by callers. Its dialer
+// refuses loopback, private and link-local addresses, and redirects must stay on the same instance.
+var remoteClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: 5 * time.Second, Control: denyInternalAddress}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 5 {
+			return errors.New("too many redirects")
+		}
+		if req.URL.Scheme != "https" || req.URL.Host != via[0].URL.Host {
+			return errOffInstance
+		}
+		return nil
+	},
+}
+
+// denyInternalAddress is the dialer Control hook of remoteClient; it runs on the resolved address, so a
+// hostname pointing at an internal address is refused too
+func denyInternalAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || cgnatPrefix.Contains(ip) {
+		return errInternalAddress
+	}
+	return nil
+}
+
+// onInstance reports whether rawURL is an https URL on domain (an acct's instance host)
+func onInstance(rawURL string, domain string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Scheme == "https" && strings.EqualFold(u.Host, domain) && u.User == nil
+}
+
+// resolveCacheEntry is a cached acct -> actor ID mapping
+type resolveCacheEntry struct {
+	actorID   string
+	expiresAt time.Time
+}
+
+// resolveCache keeps WebFinger answers in memory so repeated checks don't hit remote instances
+var resolveCache = struct {
+	sync.RWMutex
+	entries map[string]resolveCacheEntry
+}{entries: map[string]resolveCacheEntry{}}
+
+// ParseAcct turns a user supplied identifier into a lowercased "user@instance" address.
+// Accepted inputs:
+//   - "user@instance", "@user@instance" or "acct:user@instance"
+//   - a profile URL ("https://instance/@user") or an actor URL ("https://instance/users/user")
+func ParseAcct(identifier string) (string, error) {
+	s := strings.TrimSpace(identifier)
+	if strings.Contains(s, "/") {
+		acct, ok := acctFromURL(s)
+		if !ok {
+			return "", fmt.Errorf("%w: unsupported profile URL %q", ErrInvalidAcct, identifier)
+		}
+		s = acct
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "acct:"), "@")
+	user, domain, ok := strings.Cut(s, "@")
+	domain = strings.ToLower(domain)
+	if !ok || !usernamePattern.MatchString(user) || !domainPattern.MatchString(domain) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAcct, identifier)
+	}
+	return strings.ToLower(user) + "@" + domain, nil
+}
+
+// acctFromURL extracts user@instance from https://instance/@user or https://instance/users/user
+func acctFromURL(raw string) (string, bool) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(segments) >= 1 && strings.HasPrefix(segments[0], "@") && !strings.Contains(segments[0][1:], "@"):
+		return segments[0][1:] + "@" + u.Host, true
+	case len(segments) == 2 && segments[0] == "users":
+		return segments[1] + "@" + u.Host, true
+	}
+	return "", false
+}
+
+// instanceURL returns the base URL of an instance host
+func instanceURL(domain string) string {
+	return "https://" + domain
+}
+
+// ResolveActor resolves an account to its ActivityPub actor ID through WebFinger.
+// The actor must be served over https by the account's own instance.
+// Answers are cached (MASTODON_RESOLVE_CACHE_TTL, default 24h).
+func ResolveActor(ctx context.Context, identifier string) (acct string, actorID string, err error) {
+	acct, err = ParseAcct(identifier)
+	if err != nil {
+		return "", "", err
+	}
+	if actorID, ok := cachedActor(acct); ok {
+		return acct, actorID, nil
+	}
+
+	_, domain, _ := strings.Cut(acct, "@")
+	q := url.Values{}
+	q.Set("resource", "acct:"+acct)
+	var out struct {
+		Links []struct {
+			Rel  string `json:"rel"`
+			Type string `json:"type"`
+			Href string `json:"href"`
+		} `json:"links"`
+	}
+	status, err := getJSON(ctx, instanceURL(domain)+"/.well-known/webfinger?"+q.Encode(), "application/jrd+json", &out)
+	if status == http.StatusNotFound || status == http.StatusGone {
+		return "", "", fmt.Errorf("%w: %s", ErrUserNotFound, acct)
+	}
+	if errors.Is(err, errInternalAddress) || errors.Is(err, errOffInstance) {
+		return "", "", fmt.Errorf("%w: %s: %v", ErrUserNotFound, acct, err)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("webfinger %s: %w", acct, err)
+	}
+	for _, link := range out.Links {
+		if link.Rel == "self" && (strings.HasPrefix(link.Type, "application/activity+json") || strings.HasPrefix(link.Type, "application/ld+json")) {
+			actorID = link.Href
+			break
+		}
+	}
+	if actorID == "" {
+		return "", "", fmt.Errorf("%w: %s has no ActivityPub actor", ErrUserNotFound, acct)
+	}
+	if !onInstance(actorID, domain) {
+		log.Printf("[Mastodon][WARN] ResolveActor acct=%s actor=%s is not on the instance", acct, actorID)
+		return "", "", fmt.Errorf("%w: the actor of %s is not on its instance", ErrUserNotFound, acct)
+	}
+
+	log.Printf("[Mastodon][DEBUG] ResolveActor acct=%s actor=%s", acct, actorID)
+	storeActor(acct, actorID)
+	return acct, actorID, nil
+}
+
+// resolveTarget resolves the target account, reporting failures as target errors
+func resolveTarget(ctx context.Context, target string) (string, string, error) {
+	acct, actorID, err := ResolveActor(ctx, target)
+	switch {
+	case errors.Is(err, ErrInvalidAcct):
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	case errors.Is(err, ErrUserNotFound):
+		return "", "", fmt.Errorf("%w: %v", ErrTargetNotFound, err)
+	}
+	return acct, actorID, err
+}
+
+// getJSON fetches rawURL with the given Accept header and decodes a 200 response into out.
+// The status code is returned so callers can tell hidden (401/403) and missing (404/410) resources apart.
+// Returned errors name neither the remote URL nor its answer, since both are chosen by the remote instance;
+// details are logged instead.
+func getJSON(ctx context.Context, rawURL string, accept string, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return 0, errors.New("failed to create request")
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		log.Printf("[Mastodon][WARN] GET %s: %v", rawURL, err)
+		switch {
+		case errors.Is(err, errInternalAddress):
+			return 0, errInternalAddress
+		case errors.Is(err, errOffInstance):
+			return 0, errOffInstance
+		}
+		return 0, errors.New("instance unreachable")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		log.Printf("[Mastodon][WARN] GET %s: read body: %v", rawURL, err)
+		return resp.StatusCode, errors.New("failed to read instance response")
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return resp.StatusCode, ratelimit.New(ErrRateLimited, ratelimit.FromSeconds(resp.Header.Get("Retry-After")), "instance "+req.URL.Host)
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("[Mastodon][WARN] GET %s answered %d", rawURL, resp.StatusCode)
+		return resp.StatusCode, errors.New("instance request failed")
+	case len(body) > maxResponseBytes:
+		log.Printf("[Mastodon][WARN] GET %s: response larger than %d bytes", rawURL, maxResponseBytes)
+		return resp.StatusCode, errors.New("instance response too large")
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return resp.StatusCode, errors.New("failed to decode instance response")
+	}
+	return resp.StatusCode, nil
+}
+
+func cachedActor(acct string) (string, bool) {
+	resolveCache.Lock()
+	defer resolveCache.Unlock()
+	entry, ok := resolveCache.entries[acct]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(resolveCache.entries, acct)
+		return "", false
+	}
+	return entry.actorID, true
+}
+
+func storeActor(acct string, actorID string) {
+	ttl := defaultResolveCacheTTL
+	if v := os.Getenv("MASTODON_RESOLVE_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			ttl = d
+		}
+	}
+	resolveCache.Lock()
+	defer resolveCache.Unlock()
+	if _, ok := resolveCache.entries[acct]; !ok && len(resolveCache.entries) >= maxResolveCacheEntries {
+		evictResolveCache()
+	}
+	resolveCache.entries[acct] = resolveCacheEntry{actorID: actorID, expiresAt: time.Now().Add(ttl)}
+}
+
+// evictResolveCache makes room for one entry: expired entries go first, then arbitrary live ones.
+// The caller holds the write lock.
+func evictResolveCache() {
+	now := time.Now()
+	for acct, entry := range resolveCache.entries {
+		if now.After(entry.expiresAt) {
+			delete(resolveCache.entries, acct)
+		}
+	}
+	for acct := range resolveCache.entries {
+		if len(resolveCache.entries) < maxResolveCacheEntries {
+			return
+		}
+		delete(resolveCache.entries, acct)
+	}
+}
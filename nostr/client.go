@@ -0,0 +1,129 @@
+package nostr
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// ActionFollow is the action supported by CheckFollow
+const ActionFollow = "follow"
+
+// relayTimeout bounds the relay queries so a slow relay does not hold up the check
+const relayTimeout = 8 * time.Second
+
+// defaultRelays are queried when NOSTR_RELAYS is not set
+var defaultRelays = []string{"wss://relay.damus.io", "wss://nos.lol", "wss://relay.nostr.band"}
+
+// FollowResult is the outcome of CheckFollow
+type FollowResult struct {
+	// UserPubKey and TargetPubKey are hex public keys
+	UserPubKey   string
+	TargetPubKey string
+	Following    bool
+	// ContactListAt is the created_at of the contact list used; zero when no relay had one
+	ContactListAt time.Time
+	// Relays is how many relays answered
+	Relays int
+}
+
+// Relays returns the relay URLs to query.
+// Config via ENV:
+//   - NOSTR_RELAYS (optional): comma separated relay URLs (defaults to damus, nos.lol and nostr.band)
+func Relays() []string {
+	var relays []string
+	for _, r := range strings.Split(os.Getenv("NOSTR_RELAYS"), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			relays = append(relays, r)
+		}
+	}
+	if len(relays) == 0 {
+		return defaultRelays
+	}
+	return relays
+}
+
+// CheckFollow checks if the user's latest contact list (kind 3) contains target; empty target uses
+// NOSTR_TARGET_PUBKEY. Every relay is queried and only events with a valid ID and signature by the user count.
+func CheckFollow(userID string, target string) (*FollowResult, error) {
+	_ = godotenv.Load()
+
+	if strings.TrimSpace(target) == "" {
+		target = os.Getenv("NOSTR_TARGET_PUBKEY")
+	}
+	targetPubKey, err := ParsePubKey(target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+	userPubKey, err := ParsePubKey(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), relayTimeout)
+	defer cancel()
+
+	contacts, answered, err := LatestContactList(ctx, userPubKey)
+	if err != nil {
+		return nil, err
+	}
+	res := &FollowResult{UserPubKey: userPubKey, TargetPubKey: targetPubKey, Relays: answered}
+	if contacts != nil {
+		res.Following = contacts.Follows(targetPubKey)
+		res.ContactListAt = time.Unix(contacts.CreatedAt, 0)
+	}
+
+	log.Printf("[Nostr][DEBUG] CheckFollow user=%s target=%s relays=%d contact_list_at=%d following=%v", userPubKey, targetPubKey, answered, res.ContactListAt.Unix(), res.Following)
+	return res, nil
+}
+
+// LatestContactList queries every relay for the user's contact list and returns the newest event that
+// verifies, or nil when no relay has one. answered is the number of relays that responded.
+func LatestContactList(ctx context.Context, pubKey string) (latest *Event, answered int, err error) {
+	relays := Relays()
+	filter := Filter{Authors: []string{pubKey}, Kinds: []int{KindContactList}, Limit: 1}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, relay := range relays {
+		wg.Add(1)
+		go func(relay string) {
+			defer wg.Done()
+			events, err := QueryRelay(ctx, relay, filter)
+			if err != nil {
+				log.Printf("[Nostr][ERROR] %v", err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			answered++
+			for i := range events {
+				event := &events[i]
+				if event.PubKey != pubKey || event.Kind != KindContactList {
+					continue
+				}
+				if err := event.Verify(); err != nil {
+					log.Printf("[Nostr][WARN] relay %s served %v", relay, err)
+					continue
+				}
+				// NIP-01: of two replaceable events the newer one wins, ties go to the lowest ID
+				if latest == nil || event.CreatedAt > latest.CreatedAt || (event.CreatedAt == latest.CreatedAt && event.ID < latest.ID) {
+					latest = event
+				}
+			}
+		}(relay)
+	}
+	wg.Wait()
+
+	if answered == 0 {
+		return nil, 0, fmt.Errorf("%w: tried %d relays", ErrRelaysUnavailable, len(relays))
+	}
+	return latest, answered, nil
+}
@@ -0,0 +1,168 @@
+package nostr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/net/websocket"
+)
+
+// testKey is a secret key that signs test events with BIP-340
+type testKey struct {
+	d      secp256k1.ModNScalar
+	pubKey string
+}
+
+func newTestKey(seed string) *testKey {
+	secret := sha256.Sum256([]byte(seed))
+	priv := secp256k1.PrivKeyFromBytes(secret[:])
+	compressed := priv.PubKey().SerializeCompressed()
+	k := &testKey{d: priv.Key, pubKey: hex.EncodeToString(compressed[1:])}
+	// BIP-340 keys are x-only: sign with the secret whose point has an even y
+	if compressed[0] == secp256k1.PubKeyFormatCompressedOdd {
+		k.d.Negate()
+	}
+	return k
+}
+
+// sign sets the event's pubkey, ID and BIP-340 signature
+func (k *testKey) sign(e *Event) {
+	e.PubKey = k.pubKey
+	hash := sha256.Sum256(e.Serialize())
+	e.ID = hex.EncodeToString(hash[:])
+
+	// A deterministic nonce is enough for tests
+	dBytes := k.d.Bytes()
+	nonce := sha256.Sum256(append(dBytes[:], hash[:]...))
+	var nk secp256k1.ModNScalar
+	nk.SetByteSlice(nonce[:])
+	var r secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&nk, &r)
+	r.ToAffine()
+	if r.Y.IsOdd() {
+		nk.Negate()
+	}
+	rx := r.X.Bytes()
+	pubKey, _ := hex.DecodeString(k.pubKey)
+
+	var challenge secp256k1.ModNScalar
+	challenge.SetByteSlice(taggedHash("BIP0340/challenge", rx[:], pubKey, hash[:]))
+	s := new(secp256k1.ModNScalar).Mul2(&challenge, &k.d).Add(&nk).Bytes()
+	e.Sig = hex.EncodeToString(append(rx[:], s[:]...))
+}
+
+// contactList returns a kind-3 event following pubKeys
+func contactList(createdAt int64, pubKeys ...string) Event {
+	e := Event{CreatedAt: createdAt, Kind: KindContactList, Tags: [][]string{}}
+	for _, p := range pubKeys {
+		e.Tags = append(e.Tags, []string{"p", p})
+	}
+	return e
+}
+
+// fakeRelay is a NIP-01 relay that answers every REQ with events and EOSE
+func fakeRelay(t *testing.T, events ...Event) string {
+	t.Helper()
+	srv := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var msg string
+		if err := websocket.Message.Receive(ws, &msg); err != nil {
+			return
+		}
+		var req []json.RawMessage
+		if err := json.Unmarshal([]byte(msg), &req); err != nil || len(req) < 3 {
+			t.Errorf("bad REQ %s", msg)
+			return
+		}
+		var subID string
+		_ = json.Unmarshal(req[1], &subID)
+		for _, e := range events {
+			frame, _ := json.Marshal([]any{"EVENT", subID, e})
+			_ = websocket.Message.Send(ws, string(frame))
+		}
+		eose, _ := json.Marshal([]string{"EOSE", subID})
+		_ = websocket.Message.Send(ws, string(eose))
+		// Wait for CLOSE
+		_ = websocket.Message.Receive(ws, &msg)
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestLatestContactList(t *testing.T) {
+	user, forger := newTestKey("user"), newTestKey("forger")
+	target := newTestKey("target").pubKey
+
+	stale := contactList(100)
+	user.sign(&stale)
+	valid := contactList(200, target)
+	user.sign(&valid)
+
+	// Newer than valid but signed by another key under the user's pubkey
+	forged := contactList(300)
+	forger.sign(&forged)
+	forged.PubKey = user.pubKey
+	// Newer than valid, properly signed, then edited by the relay
+	tampered := contactList(400)
+	user.sign(&tampered)
+	tampered.Tags = [][]string{{"p", target}}
+
+	down := httptest.NewServer(nil)
+	downURL := "ws" + strings.TrimPrefix(down.URL, "http")
+	down.Close()
+
+	relays := []string{
+		fakeRelay(t, stale, forged),
+		fakeRelay(t, valid),
+		fakeRelay(t, tampered, stale),
+		downURL,
+	}
+	t.Setenv("NOSTR_RELAYS", strings.Join(relays, ","))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	latest, answered, err := LatestContactList(ctx, user.pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answered != 3 {
+		t.Errorf("answered = %d, want 3", answered)
+	}
+	if latest == nil || latest.ID != valid.ID {
+		t.Fatalf("latest = %+v, want the valid event created at 200", latest)
+	}
+
+	res, err := CheckFollow(user.pubKey, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Following || res.ContactListAt.Unix() != 200 {
+		t.Errorf("got following=%v at %d, want true at 200", res.Following, res.ContactListAt.Unix())
+	}
+}
+
+func TestCheckFollowUsesNewestList(t *testing.T) {
+	user := newTestKey("user")
+	target := newTestKey("target").pubKey
+
+	older := contactList(100, target)
+	user.sign(&older)
+	newer := contactList(150)
+	user.sign(&newer)
+	t.Setenv("NOSTR_RELAYS", fakeRelay(t, older)+","+fakeRelay(t, newer))
+
+	res, err := CheckFollow(user.pubKey, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The unfollow in the newer list wins over the older list that still has target
+	if res.Following || res.ContactListAt.Unix() != 150 {
+		t.Errorf("got following=%v at %d, want false at 150", res.Following, res.ContactListAt.Unix())
+	}
+}
@@ -0,0 +1,165 @@
+package nostr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// KindContactList is the NIP-02 replaceable event holding the accounts a user follows
+const KindContactList = 3
+
+// ErrInvalidEvent is returned when an event's ID or signature does not verify
+var ErrInvalidEvent = errors.New("invalid nostr event")
+
+// Event is a NIP-01 event
+type Event struct {
+	ID        string     `json:"id"`
+	PubKey    string     `json:"pubkey"`
+	CreatedAt int64      `json:"created_at"`
+	Kind      int        `json:"kind"`
+	Tags      [][]string `json:"tags"`
+	Content   string     `json:"content"`
+	Sig       string     `json:"sig"`
+}
+
+// Serialize returns the NIP-01 serialization [0,pubkey,created_at,kind,tags,content] the ID is hashed from
+func (e *Event) Serialize() []byte {
+	var b bytes.Buffer
+	b.WriteString(`[0,`)
+	writeJSONString(&b, e.PubKey)
+	b.WriteString(",")
+	b.WriteString(strconv.FormatInt(e.CreatedAt, 10))
+	b.WriteString(",")
+	b.WriteString(strconv.Itoa(e.Kind))
+	b.WriteString(",[")
+	for i, tag := range e.Tags {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("[")
+		for j, v := range tag {
+			if j > 0 {
+				b.WriteString(",")
+			}
+			writeJSONString(&b, v)
+		}
+		b.WriteString("]")
+	}
+	b.WriteString("],")
+	writeJSONString(&b, e.Content)
+	b.WriteString("]")
+	return b.Bytes()
+}
+
+// writeJSONString writes s the way NIP-01 (and JSON.stringify) does: only quotes, backslashes and
+// control characters are escaped, everything else is written as UTF-8
+func writeJSONString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
+
+// Verify checks that the ID is the hash of the event and that the signature is the author's
+func (e *Event) Verify() error {
+	hash := sha256.Sum256(e.Serialize())
+	if hex.EncodeToString(hash[:]) != e.ID {
+		return fmt.Errorf("%w: id %s does not match its content", ErrInvalidEvent, e.ID)
+	}
+	pubKey, err := hex.DecodeString(e.PubKey)
+	if err != nil || len(pubKey) != 32 {
+		return fmt.Errorf("%w: bad pubkey %q", ErrInvalidEvent, e.PubKey)
+	}
+	sig, err := hex.DecodeString(e.Sig)
+	if err != nil || len(sig) != 64 {
+		return fmt.Errorf("%w: bad signature on %s", ErrInvalidEvent, e.ID)
+	}
+	if !verifySchnorr(pubKey, hash[:], sig) {
+		return fmt.Errorf("%w: signature on %s does not verify", ErrInvalidEvent, e.ID)
+	}
+	return nil
+}
+
+// Follows reports whether the contact list has a "p" tag for pubKey
+func (e *Event) Follows(pubKey string) bool {
+	for _, tag := range e.Tags {
+		if len(tag) >= 2 && tag[0] == "p" && tag[1] == pubKey {
+			return true
+		}
+	}
+	return false
+}
+
+// verifySchnorr verifies a BIP-340 signature of msg by the x-only public key pubKey
+func verifySchnorr(pubKey []byte, msg []byte, sig []byte) bool {
+	var px, py secp256k1.FieldVal
+	if px.SetByteSlice(pubKey) || !secp256k1.DecompressY(&px, false, &py) {
+		return false
+	}
+	var r secp256k1.FieldVal
+	if r.SetByteSlice(sig[:32]) {
+		return false
+	}
+	var s secp256k1.ModNScalar
+	if s.SetByteSlice(sig[32:]) {
+		return false
+	}
+
+	// e = H(r || P || m) mod n; R = s*G - e*P must have an even y and x == r
+	var e secp256k1.ModNScalar
+	e.SetByteSlice(taggedHash("BIP0340/challenge", sig[:32], pubKey, msg))
+	e.Negate()
+
+	var one secp256k1.FieldVal
+	one.SetInt(1)
+	p := secp256k1.MakeJacobianPoint(&px, &py, &one)
+	var sG, eP, R secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&s, &sG)
+	secp256k1.ScalarMultNonConst(&e, &p, &eP)
+	secp256k1.AddNonConst(&sG, &eP, &R)
+	if R.Z.Normalize().IsZero() {
+		return false
+	}
+	R.ToAffine()
+	return !R.Y.IsOdd() && R.X.Equals(&r)
+}
+
+// taggedHash is the BIP-340 hash SHA256(SHA256(tag) || SHA256(tag) || data...)
+func taggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,108 @@
+package nostr
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// bech32Charset is the BIP-173 alphabet used by npub
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var (
+	// ErrInvalidPubKey is returned when an identifier is neither an npub nor a 64 character hex public key
+	ErrInvalidPubKey = errors.New("invalid nostr public key")
+	// ErrInvalidTarget is returned when the target public key cannot be parsed
+	ErrInvalidTarget = errors.New("invalid nostr target")
+)
+
+// ParsePubKey turns an npub ("npub1...", optionally "nostr:npub1...") or a hex public key into lowercase hex
+func ParsePubKey(identifier string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(identifier))
+	s = strings.TrimPrefix(s, "nostr:")
+	if strings.HasPrefix(s, "npub1") {
+		key, err := decodeNpub(s)
+		if err != nil {
+			return "", fmt.Errorf("%w: %q: %v", ErrInvalidPubKey, identifier, err)
+		}
+		return hex.EncodeToString(key), nil
+	}
+	if key, err := hex.DecodeString(s); err != nil || len(key) != 32 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPubKey, identifier)
+	}
+	return s, nil
+}
+
+// decodeNpub decodes a NIP-19 npub into the 32 byte public key
+func decodeNpub(s string) ([]byte, error) {
+	sep := strings.LastIndexByte(s, '1')
+	if s[:sep] != "npub" || len(s)-sep-1 < 6 {
+		return nil, errors.New("not an npub")
+	}
+	data := make([]byte, 0, len(s)-sep-1)
+	for _, c := range s[sep+1:] {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		data = append(data, byte(v))
+	}
+	if bech32Polymod(append(bech32HRPExpand(s[:sep]), data...)) != 1 {
+		return nil, errors.New("invalid checksum")
+	}
+	key, err := convertBits(data[:len(data)-6], 5, 8)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("npub holds %d bytes", len(key))
+	}
+	return key, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups 5 bit words into bytes; leftover padding must be zero
+func convertBits(data []byte, from uint, to uint) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	out := make([]byte, 0, len(data)*int(from)/int(to))
+	for _, v := range data {
+		acc = acc<<from | uint32(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&(1<<to-1)))
+		}
+	}
+	if bits >= from || acc&(1<<bits-1) != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
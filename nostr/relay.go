@@ -0,0 +1,102 @@
+package nostr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/net/websocket"
+)
+
+// ErrRelaysUnavailable is returned when no relay answered the query
+var ErrRelaysUnavailable = errors.New("no nostr relay answered")
+
+// Filter is a NIP-01 REQ filter
+type Filter struct {
+	Authors []string `json:"authors,omitempty"`
+	Kinds   []int    `json:"kinds,omitempty"`
+	Limit   int      `json:"limit,omitempty"`
+}
+
+// QueryRelay sends filter to the relay and collects the stored events it returns until EOSE.
+// The subscription is closed before returning.
+func QueryRelay(ctx context.Context, relayURL string, filter Filter) ([]Event, error) {
+	cfg, err := websocket.NewConfig(relayURL, "http://localhost/")
+	if err != nil {
+		return nil, fmt.Errorf("relay %s: %w", relayURL, err)
+	}
+	ws, err := cfg.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("relay %s: failed to connect: %w", relayURL, err)
+	}
+	defer ws.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = ws.SetDeadline(deadline)
+	}
+
+	subID, err := subscriptionID()
+	if err != nil {
+		return nil, err
+	}
+	req, err := json.Marshal([]any{"REQ", subID, filter})
+	if err != nil {
+		return nil, err
+	}
+	if err := websocket.Message.Send(ws, string(req)); err != nil {
+		return nil, fmt.Errorf("relay %s: failed to send REQ: %w", relayURL, err)
+	}
+	defer func() {
+		closeMsg, _ := json.Marshal([]string{"CLOSE", subID})
+		_ = websocket.Message.Send(ws, string(closeMsg))
+	}()
+
+	var events []Event
+	for {
+		var msg string
+		if err := websocket.Message.Receive(ws, &msg); err != nil {
+			return nil, fmt.Errorf("relay %s: failed to read: %w", relayURL, err)
+		}
+		var frame []json.RawMessage
+		if err := json.Unmarshal([]byte(msg), &frame); err != nil || len(frame) < 2 {
+			continue
+		}
+		var label, sub string
+		_ = json.Unmarshal(frame[0], &label)
+		_ = json.Unmarshal(frame[1], &sub)
+		switch label {
+		case "EVENT":
+			if sub != subID || len(frame) < 3 {
+				continue
+			}
+			var event Event
+			if err := json.Unmarshal(frame[2], &event); err != nil {
+				continue
+			}
+			events = append(events, event)
+		case "EOSE":
+			if sub == subID {
+				return events, nil
+			}
+		case "CLOSED":
+			if sub == subID {
+				var reason string
+				if len(frame) > 2 {
+					_ = json.Unmarshal(frame[2], &reason)
+				}
+				return nil, fmt.Errorf("relay %s closed the subscription: %s", relayURL, reason)
+			}
+		}
+	}
+}
+
+// subscriptionID returns a random subscription ID
+func subscriptionID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
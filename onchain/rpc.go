@@ -0,0 +1,164 @@
+package onchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrUnknownChain is returned when no RPC endpoint is configured for the chain
+	ErrUnknownChain = errors.New("no rpc endpoint for chain")
+	// ErrNotAContract is returned when eth_call hits an address without code
+	ErrNotAContract = errors.New("address is not a contract")
+	// ErrCallReverted is returned when the contract reverted, e.g. it does not implement the token standard
+	ErrCallReverted = errors.New("contract call reverted")
+)
+
+// Client is a minimal Ethereum JSON-RPC client
+type Client struct {
+	rpcURL     string
+	chainID    int64
+	httpClient *http.Client
+	nextID     atomic.Int64
+	// chainChecked is set once eth_chainId matched chainID
+	chainChecked atomic.Bool
+}
+
+// NewClient creates a client for chainID.
+// Config via ENV:
+//   - ETH_RPC_URL: JSON-RPC endpoint of the default chain (an anvil node works for local runs)
+//   - ETH_CHAIN_ID (optional): chain ID served by ETH_RPC_URL (defaults to 1); used when chainID is 0
+//   - ETH_RPC_URL_<chainID> (optional): endpoint for another chain, e.g. ETH_RPC_URL_8453 for Base
+func NewClient(chainID int64) (*Client, error) {
+	defaultChain := int64(1)
+	if v := strings.TrimSpace(os.Getenv("ETH_CHAIN_ID")); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ETH_CHAIN_ID %q", v)
+		}
+		defaultChain = id
+	}
+	if chainID == 0 {
+		chainID = defaultChain
+	}
+
+	rpcURL := os.Getenv(fmt.Sprintf("ETH_RPC_URL_%d", chainID))
+	if rpcURL == "" && chainID == defaultChain {
+		rpcURL = os.Getenv("ETH_RPC_URL")
+	}
+	if rpcURL == "" {
+		return nil, fmt.Errorf("%w %d: set ETH_RPC_URL_%d", ErrUnknownChain, chainID, chainID)
+	}
+	return &Client{rpcURL: rpcURL, chainID: chainID, httpClient: &http.Client{Timeout: 15 * time.Second}}, nil
+}
+
+// ChainID returns the chain the client talks to
+func (c *Client) ChainID() int64 {
+	return c.chainID
+}
+
+// rpcError is a JSON-RPC error object
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// call performs a JSON-RPC request and decodes the result into out
+func (c *Client) call(ctx context.Context, method string, params []any, out any) error {
+	payload, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      c.nextID.Add(1),
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.rpcURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// RPC URLs often embed an API key, so only the method is reported
+		return fmt.Errorf("%s request failed", method)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("%s: status %d: failed to unmarshal response: %w", method, resp.StatusCode, err)
+	}
+	if envelope.Error != nil {
+		if strings.Contains(strings.ToLower(envelope.Error.Message), "revert") {
+			return fmt.Errorf("%w: %s", ErrCallReverted, envelope.Error.Message)
+		}
+		return fmt.Errorf("%s failed with code %d: %s", method, envelope.Error.Code, envelope.Error.Message)
+	}
+	if err := json.Unmarshal(envelope.Result, out); err != nil {
+		return fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+	return nil
+}
+
+// checkChain verifies once that the endpoint serves the configured chain
+func (c *Client) checkChain(ctx context.Context) error {
+	if c.chainChecked.Load() {
+		return nil
+	}
+	var hexID string
+	if err := c.call(ctx, "eth_chainId", []any{}, &hexID); err != nil {
+		return err
+	}
+	id, ok := new(big.Int).SetString(strings.TrimPrefix(hexID, "0x"), 16)
+	if !ok {
+		return fmt.Errorf("invalid eth_chainId result %q", hexID)
+	}
+	if id.Int64() != c.chainID {
+		return fmt.Errorf("rpc endpoint serves chain %s, expected %d", id, c.chainID)
+	}
+	c.chainChecked.Store(true)
+	return nil
+}
+
+// Call performs eth_call of data against contract at the latest block and returns the raw return data
+func (c *Client) Call(ctx context.Context, contract string, data []byte) ([]byte, error) {
+	if err := c.checkChain(ctx); err != nil {
+		return nil, err
+	}
+	var result string
+	tx := map[string]string{"to": contract, "data": "0x" + hex.EncodeToString(data)}
+	if err := c.call(ctx, "eth_call", []any{tx, "latest"}, &result); err != nil {
+		return nil, err
+	}
+	out, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid eth_call result: %w", err)
+	}
+	if len(out) == 0 {
+		// Calls to an address without code succeed with empty return data
+		return nil, fmt.Errorf("%w: %s", ErrNotAContract, contract)
+	}
+	return out, nil
+}
@@ -0,0 +1,259 @@
+package onchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Actions supported by CheckHolding
+const (
+	ActionHoldERC20   = "hold_erc20"
+	ActionHoldERC721  = "hold_erc721"
+	ActionHoldERC1155 = "hold_erc1155"
+)
+
+// Function selectors (first 4 bytes of keccak256 of the signature)
+var (
+	// balanceOf(address)
+	selectorBalanceOf = []byte{0x70, 0xa0, 0x82, 0x31}
+	// balanceOf(address,uint256)
+	selectorBalanceOfID = []byte{0x00, 0xfd, 0xd5, 0x8e}
+	// decimals()
+	selectorDecimals = []byte{0x31, 0x3c, 0xe5, 0x67}
+)
+
+var (
+	// ErrInvalidAddress is returned when a wallet is not a 0x-prefixed 20 byte address
+	ErrInvalidAddress = errors.New("invalid wallet address")
+	// ErrInvalidTarget is returned when a token target cannot be parsed
+	ErrInvalidTarget = errors.New("invalid token target")
+
+	addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	amountPattern  = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+)
+
+// Target is a parsed holding requirement
+type Target struct {
+	// ChainID is 0 for the default chain (ETH_CHAIN_ID)
+	ChainID  int64
+	Contract string
+	// TokenID is the ERC-1155 token ID
+	TokenID *big.Int
+	// Min is the minimum balance: whole tokens (decimals applied) for ERC-20, a count for ERC-721 and ERC-1155
+	Min string
+}
+
+// ParseTarget parses a token target for action.
+// Formats ("eip155:<chainId>:" prefix optional, picks ETH_RPC_URL_<chainId>):
+//   - hold_erc20:   "0xToken" or "0xToken:100.5" (at least 100.5 tokens; default: any balance)
+//   - hold_erc721:  "0xCollection" or "0xCollection:3" (at least 3 NFTs; default 1)
+//   - hold_erc1155: "0xCollection:<tokenId>" or "0xCollection:<tokenId>:2"
+func ParseTarget(action string, target string) (*Target, error) {
+	parts := strings.Split(strings.TrimSpace(target), ":")
+	t := &Target{}
+	if len(parts) >= 2 && strings.EqualFold(parts[0], "eip155") {
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%w: chain %q", ErrInvalidTarget, parts[1])
+		}
+		t.ChainID = id
+		parts = parts[2:]
+	}
+	if len(parts) == 0 || !addressPattern.MatchString(parts[0]) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTarget, target)
+	}
+	t.Contract = strings.ToLower(parts[0])
+	parts = parts[1:]
+
+	if action == ActionHoldERC1155 {
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("%w: erc1155 target needs a token id", ErrInvalidTarget)
+		}
+		id, ok := new(big.Int).SetString(parts[0], 0)
+		// Token IDs are uint256
+		if !ok || id.Sign() < 0 || id.BitLen() > 256 {
+			return nil, fmt.Errorf("%w: token id %q", ErrInvalidTarget, parts[0])
+		}
+		t.TokenID = id
+		parts = parts[1:]
+	}
+
+	switch len(parts) {
+	case 0:
+		t.Min = "0"
+		if action != ActionHoldERC20 {
+			t.Min = "1"
+		}
+	case 1:
+		if !amountPattern.MatchString(parts[0]) || (action != ActionHoldERC20 && strings.Contains(parts[0], ".")) {
+			return nil, fmt.Errorf("%w: minimum %q", ErrInvalidTarget, parts[0])
+		}
+		t.Min = parts[0]
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTarget, target)
+	}
+	return t, nil
+}
+
+// NormalizeAddress validates a wallet address and lowercases it
+func NormalizeAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if !addressPattern.MatchString(address) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAddress, address)
+	}
+	return strings.ToLower(address), nil
+}
+
+// IsAddress reports whether s looks like a wallet address
+func IsAddress(s string) bool {
+	return addressPattern.MatchString(strings.TrimSpace(s))
+}
+
+// encodeAddress left-pads an address to a 32 byte ABI word
+func encodeAddress(address string) []byte {
+	word := make([]byte, 32)
+	b, _ := new(big.Int).SetString(strings.TrimPrefix(address, "0x"), 16)
+	b.FillBytes(word)
+	return word
+}
+
+// encodeUint encodes a uint256 ABI word
+func encodeUint(v *big.Int) []byte {
+	word := make([]byte, 32)
+	v.FillBytes(word)
+	return word
+}
+
+// BalanceOf returns the ERC-20 / ERC-721 balanceOf(owner)
+func (c *Client) BalanceOf(ctx context.Context, contract string, owner string) (*big.Int, error) {
+	data := append(append([]byte{}, selectorBalanceOf...), encodeAddress(owner)...)
+	out, err := c.Call(ctx, contract, data)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(out[:min(32, len(out))]), nil
+}
+
+// BalanceOfID returns the ERC-1155 balanceOf(owner, id)
+func (c *Client) BalanceOfID(ctx context.Context, contract string, owner string, id *big.Int) (*big.Int, error) {
+	data := append(append(append([]byte{}, selectorBalanceOfID...), encodeAddress(owner)...), encodeUint(id)...)
+	out, err := c.Call(ctx, contract, data)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(out[:min(32, len(out))]), nil
+}
+
+// Decimals returns the ERC-20 decimals()
+func (c *Client) Decimals(ctx context.Context, contract string) (int, error) {
+	out, err := c.Call(ctx, contract, selectorDecimals)
+	if err != nil {
+		return 0, err
+	}
+	d := new(big.Int).SetBytes(out[:min(32, len(out))])
+	if !d.IsInt64() || d.Int64() > 77 {
+		return 0, fmt.Errorf("%w: decimals() returned %s", ErrInvalidTarget, d)
+	}
+	return int(d.Int64()), nil
+}
+
+// scaleAmount converts a decimal amount ("100.5") to base units with the given decimals
+func scaleAmount(amount string, decimals int) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(amount, ".")
+	if len(frac) > decimals {
+		return nil, fmt.Errorf("%w: %s has more than %d decimals", ErrInvalidTarget, amount, decimals)
+	}
+	v, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: amount %q", ErrInvalidTarget, amount)
+	}
+	return v, nil
+}
+
+// HoldingResult is the outcome of CheckHolding
+type HoldingResult struct {
+	ChainID   int64
+	Contract  string
+	Addresses []string
+	// Balance is the summed balance of all addresses, in base units
+	Balance *big.Int
+	// Min is the required balance, in base units
+	Min  *big.Int
+	Done bool
+}
+
+// CheckHolding sums the balances of addresses for the target token and compares them to the minimum.
+// ERC-20 requires a balance > 0 when no minimum is given; otherwise balance >= minimum.
+func CheckHolding(addresses []string, action string, target string) (*HoldingResult, error) {
+	_ = godotenv.Load()
+
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%w: no wallet address", ErrInvalidAddress)
+	}
+	normalized := make([]string, len(addresses))
+	for i, a := range addresses {
+		var err error
+		if normalized[i], err = NormalizeAddress(a); err != nil {
+			return nil, err
+		}
+	}
+	switch action {
+	case ActionHoldERC20, ActionHoldERC721, ActionHoldERC1155:
+	default:
+		return nil, fmt.Errorf("unsupported onchain action %q", action)
+	}
+	t, err := ParseTarget(action, target)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient(t.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	res := &HoldingResult{ChainID: client.ChainID(), Contract: t.Contract, Addresses: normalized, Balance: new(big.Int)}
+	decimals := 0
+	if action == ActionHoldERC20 {
+		if decimals, err = client.Decimals(ctx, t.Contract); err != nil {
+			return nil, err
+		}
+	}
+	if res.Min, err = scaleAmount(t.Min, decimals); err != nil {
+		return nil, err
+	}
+
+	for _, owner := range normalized {
+		var balance *big.Int
+		if action == ActionHoldERC1155 {
+			balance, err = client.BalanceOfID(ctx, t.Contract, owner, t.TokenID)
+		} else {
+			balance, err = client.BalanceOf(ctx, t.Contract, owner)
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.Balance.Add(res.Balance, balance)
+	}
+
+	if res.Min.Sign() == 0 {
+		res.Done = res.Balance.Sign() > 0
+	} else {
+		res.Done = res.Balance.Cmp(res.Min) >= 0
+	}
+	log.Printf("[Onchain][DEBUG] CheckHolding chain=%d contract=%s action=%s addresses=%d balance=%s min=%s done=%v",
+		res.ChainID, t.Contract, action, len(normalized), res.Balance, res.Min, res.Done)
+	return res, nil
+}
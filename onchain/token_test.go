@@ -0,0 +1,141 @@
+package onchain
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testToken   = "0x1111111111111111111111111111111111111111"
+	testNoCode  = "0x2222222222222222222222222222222222222222"
+	testWalletA = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testWalletB = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+// fakeRPC serves eth_chainId and eth_call for testToken, which implements decimals() and both
+// balanceOf variants. balances maps the hex calldata after the selector to a balance.
+func fakeRPC(t *testing.T, chainID string, balances map[string]*big.Int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int64             `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		reply := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_chainId":
+			reply["result"] = chainID
+		case "eth_call":
+			var tx struct {
+				To   string `json:"to"`
+				Data string `json:"data"`
+			}
+			_ = json.Unmarshal(req.Params[0], &tx)
+			data := strings.TrimPrefix(tx.Data, "0x")
+			switch {
+			case tx.To == testNoCode:
+				reply["result"] = "0x"
+			case data == hex.EncodeToString(selectorDecimals):
+				reply["result"] = "0x" + hex.EncodeToString(encodeUint(big.NewInt(18)))
+			case balances[data[8:]] != nil:
+				reply["result"] = "0x" + hex.EncodeToString(encodeUint(balances[data[8:]]))
+			default:
+				reply["error"] = map[string]any{"code": 3, "message": "execution reverted"}
+			}
+		default:
+			reply["error"] = map[string]any{"code": -32601, "message": "method not found"}
+		}
+		_ = json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func balanceKey(owner string, id *big.Int) string {
+	key := hex.EncodeToString(encodeAddress(owner))
+	if id != nil {
+		key += hex.EncodeToString(encodeUint(id))
+	}
+	return key
+}
+
+func TestParseTargetTokenIDRange(t *testing.T) {
+	maxID := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	if _, err := ParseTarget(ActionHoldERC1155, testToken+":"+maxID.String()); err != nil {
+		t.Fatalf("2^256-1: %v", err)
+	}
+	tooBig := new(big.Int).Add(maxID, big.NewInt(1))
+	if _, err := ParseTarget(ActionHoldERC1155, testToken+":"+tooBig.String()); !errors.Is(err, ErrInvalidTarget) {
+		t.Fatalf("2^256: got %v, want ErrInvalidTarget", err)
+	}
+	if _, err := ParseTarget(ActionHoldERC1155, testToken+":-1"); !errors.Is(err, ErrInvalidTarget) {
+		t.Fatalf("-1: got %v, want ErrInvalidTarget", err)
+	}
+}
+
+func TestCheckHolding(t *testing.T) {
+	maxID := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	oneToken := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	srv := fakeRPC(t, "0x1", map[string]*big.Int{
+		balanceKey(testWalletA, nil):   oneToken,
+		balanceKey(testWalletB, nil):   new(big.Int).Div(oneToken, big.NewInt(2)),
+		balanceKey(testWalletA, maxID): big.NewInt(2),
+	})
+	t.Setenv("ETH_RPC_URL", srv.URL)
+	t.Setenv("ETH_CHAIN_ID", "1")
+
+	tests := []struct {
+		name      string
+		addresses []string
+		action    string
+		target    string
+		want      bool
+		wantErr   error
+	}{
+		{"erc20 summed over wallets", []string{testWalletA, testWalletB}, ActionHoldERC20, testToken + ":1.5", true, nil},
+		{"erc20 below minimum", []string{testWalletB}, ActionHoldERC20, testToken + ":1", false, nil},
+		{"erc721 default minimum", []string{testWalletA}, ActionHoldERC721, testToken, true, nil},
+		{"erc1155 max token id", []string{testWalletA}, ActionHoldERC1155, fmt.Sprintf("%s:%s:2", testToken, maxID), true, nil},
+		{"erc1155 unknown id reverts", []string{testWalletA}, ActionHoldERC1155, testToken + ":7", false, ErrCallReverted},
+		{"no contract code", []string{testWalletA}, ActionHoldERC721, testNoCode, false, ErrNotAContract},
+		{"unconfigured chain", []string{testWalletA}, ActionHoldERC721, "eip155:8453:" + testToken, false, ErrUnknownChain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := CheckHolding(tt.addresses, tt.action, tt.target)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckHolding: %v", err)
+			}
+			if res.Done != tt.want {
+				t.Fatalf("Done = %v (balance %s, min %s), want %v", res.Done, res.Balance, res.Min, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHoldingWrongChain(t *testing.T) {
+	srv := fakeRPC(t, "0x2105", nil)
+	t.Setenv("ETH_RPC_URL", srv.URL)
+	t.Setenv("ETH_CHAIN_ID", "1")
+	if _, err := CheckHolding([]string{testWalletA}, ActionHoldERC721, testToken); err == nil || !strings.Contains(err.Error(), "serves chain 8453") {
+		t.Fatalf("got %v, want chain mismatch", err)
+	}
+}
@@ -0,0 +1,223 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Key formats
+const (
+	followersKeyFmt   = "farcaster:followers:%s"   // Set of FIDs following the target
+	unfollowersKeyFmt = "farcaster:unfollowers:%s" // Set of FIDs seen unfollowing the target
+	syncKeyFmt        = "farcaster:sync:last:%s"   // Unix timestamp of the last full sync
+	reactionsKeyFmt   = "farcaster:%s:%s"          // Set of FIDs that liked/recasted a cast (likes|recasts, hash)
+	repliesKeyFmt     = "farcaster:replies:%s"     // Set of FIDs that replied to a cast
+)
+
+// ErrNotInitialized is returned when the cache is used before InitRedis
+var ErrNotInitialized = errors.New("redis not initialized")
+
+var rdb *redis.Client
+
+// InitRedis connects to Redis using REDIS_ADDR, REDIS_DB and REDIS_PASSWORD
+func InitRedis() error {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+
+	db := 0
+	if v := os.Getenv("REDIS_DB"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid REDIS_DB: %w", err)
+		}
+		db = n
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	rdb = client
+	log.Printf("[Redis] connected to %s db=%d", addr, db)
+	return nil
+}
+
+// GetRedisClient returns the Redis client, or nil if InitRedis has not been called
+func GetRedisClient() *redis.Client {
+	return rdb
+}
+
+// Enabled reports whether Redis has been initialized
+func Enabled() bool {
+	return rdb != nil
+}
+
+// Close closes the Redis connection
+func Close() error {
+	if rdb == nil {
+		return nil
+	}
+	err := rdb.Close()
+	rdb = nil
+	return err
+}
+
+// AddFollowerFID records that fid follows targetFID
+func AddFollowerFID(ctx context.Context, targetFID string, fid int64) error {
+	if rdb == nil {
+		return ErrNotInitialized
+	}
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, fmt.Sprintf(followersKeyFmt, targetFID), fid)
+		pipe.SRem(ctx, fmt.Sprintf(unfollowersKeyFmt, targetFID), fid)
+		return nil
+	})
+	return err
+}
+
+// AddFollowerFIDs records a batch of followers of targetFID
+func AddFollowerFIDs(ctx context.Context, targetFID string, fids []int64) error {
+	if rdb == nil {
+		return ErrNotInitialized
+	}
+	if len(fids) == 0 {
+		return nil
+	}
+	members := make([]any, len(fids))
+	for i, fid := range fids {
+		members[i] = fid
+	}
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, fmt.Sprintf(followersKeyFmt, targetFID), members...)
+		pipe.SRem(ctx, fmt.Sprintf(unfollowersKeyFmt, targetFID), members...)
+		return nil
+	})
+	return err
+}
+
+// RemoveFollowerFID records that fid unfollowed targetFID
+func RemoveFollowerFID(ctx context.Context, targetFID string, fid int64) error {
+	if rdb == nil {
+		return ErrNotInitialized
+	}
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SRem(ctx, fmt.Sprintf(followersKeyFmt, targetFID), fid)
+		pipe.SAdd(ctx, fmt.Sprintf(unfollowersKeyFmt, targetFID), fid)
+		return nil
+	})
+	return err
+}
+
+// IsFollower checks if fid is in the cached followers of targetFID
+func IsFollower(ctx context.Context, targetFID string, fid int64) (bool, error) {
+	if rdb == nil {
+		return false, ErrNotInitialized
+	}
+	return rdb.SIsMember(ctx, fmt.Sprintf(followersKeyFmt, targetFID), fid).Result()
+}
+
+// FollowState reports what the cache knows about fid following targetFID.
+// known is false when the pair has never been seen, so the caller must ask the API.
+func FollowState(ctx context.Context, targetFID string, fid int64) (known bool, following bool, err error) {
+	if rdb == nil {
+		return false, false, ErrNotInitialized
+	}
+	following, err = rdb.SIsMember(ctx, fmt.Sprintf(followersKeyFmt, targetFID), fid).Result()
+	if err != nil || following {
+		return following, following, err
+	}
+	unfollowed, err := rdb.SIsMember(ctx, fmt.Sprintf(unfollowersKeyFmt, targetFID), fid).Result()
+	if err != nil {
+		return false, false, err
+	}
+	return unfollowed, false, nil
+}
+
+// GetFollowerCount returns the number of cached followers of targetFID
+func GetFollowerCount(ctx context.Context, targetFID string) (int64, error) {
+	if rdb == nil {
+		return 0, ErrNotInitialized
+	}
+	return rdb.SCard(ctx, fmt.Sprintf(followersKeyFmt, targetFID)).Result()
+}
+
+// ClearFollowers removes all cached follow state of targetFID
+func ClearFollowers(ctx context.Context, targetFID string) error {
+	if rdb == nil {
+		return ErrNotInitialized
+	}
+	return rdb.Del(ctx, fmt.Sprintf(followersKeyFmt, targetFID), fmt.Sprintf(unfollowersKeyFmt, targetFID)).Err()
+}
+
+// SetLastSyncTime stores the time of the last full follower sync of targetFID
+func SetLastSyncTime(ctx context.Context, targetFID string, t time.Time) error {
+	if rdb == nil {
+		return ErrNotInitialized
+	}
+	return rdb.Set(ctx, fmt.Sprintf(syncKeyFmt, targetFID), t.Unix(), 0).Err()
+}
+
+// GetLastSyncTime returns the time of the last full follower sync of targetFID (zero if never synced)
+func GetLastSyncTime(ctx context.Context, targetFID string) (time.Time, error) {
+	if rdb == nil {
+		return time.Time{}, ErrNotInitialized
+	}
+	ts, err := rdb.Get(ctx, fmt.Sprintf(syncKeyFmt, targetFID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// AddReaction records that fid reacted to castHash; kind is "likes" or "recasts"
+func AddReaction(ctx context.Context, kind string, castHash string, fid int64) error {
+	if rdb == nil {
+		return ErrNotInitialized
+	}
+	return rdb.SAdd(ctx, fmt.Sprintf(reactionsKeyFmt, kind, castHash), fid).Err()
+}
+
+// HasReaction checks if fid is recorded as having reacted to castHash
+func HasReaction(ctx context.Context, kind string, castHash string, fid int64) (bool, error) {
+	if rdb == nil {
+		return false, ErrNotInitialized
+	}
+	return rdb.SIsMember(ctx, fmt.Sprintf(reactionsKeyFmt, kind, castHash), fid).Result()
+}
+
+// AddReply records that fid replied to parentHash
+func AddReply(ctx context.Context, parentHash string, fid int64) error {
+	if rdb == nil {
+		return ErrNotInitialized
+	}
+	return rdb.SAdd(ctx, fmt.Sprintf(repliesKeyFmt, parentHash), fid).Err()
+}
+
+// HasReplied checks if fid is recorded as having replied to parentHash
+func HasReplied(ctx context.Context, parentHash string, fid int64) (bool, error) {
+	if rdb == nil {
+		return false, ErrNotInitialized
+	}
+	return rdb.SIsMember(ctx, fmt.Sprintf(repliesKeyFmt, parentHash), fid).Result()
+}
@@ -0,0 +1,56 @@
+package cronjob
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	mu        sync.Mutex
+	scheduler *cron.Cron
+)
+
+// InitCronScheduler creates the scheduler (if needed), registers job under spec and starts it.
+// spec accepts standard 5-field cron expressions and descriptors such as "@every 1h".
+func InitCronScheduler(spec string, job func()) error {
+	if job == nil {
+		return errors.New("cron job is nil")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if scheduler == nil {
+		scheduler = cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+		scheduler.Start()
+	}
+	if _, err := scheduler.AddFunc(spec, job); err != nil {
+		return fmt.Errorf("invalid cron spec %q: %w", spec, err)
+	}
+	log.Printf("[Cron] scheduled job spec=%q", spec)
+	return nil
+}
+
+// StopCronScheduler stops the scheduler and waits for running jobs to finish
+func StopCronScheduler() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if scheduler == nil {
+		return
+	}
+	<-scheduler.Stop().Done()
+	scheduler = nil
+	log.Printf("[Cron] scheduler stopped")
+}
+
+// GetCronScheduler returns the running scheduler, or nil if none was initialized
+func GetCronScheduler() *cron.Cron {
+	mu.Lock()
+	defer mu.Unlock()
+	return scheduler
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error is a rate limit answer of a platform API. It wraps the platform's ErrRateLimited sentinel so
+// errors.Is keeps matching, and carries how long the caller should wait before retrying.
+type Error struct {
+	// Err is the platform sentinel, e.g. twitter.ErrRateLimited
+	Err error
+	// RetryAfter is how long to wait before retrying; 0 when the API did not say
+	RetryAfter time.Duration
+	// Detail describes the limit, e.g. "resets at 2024-10-15T13:46:40Z"
+	Detail string
+}
+
+// New returns an Error for sentinel; a negative retryAfter (a reset already in the past) becomes 0
+func New(sentinel error, retryAfter time.Duration, detail string) *Error {
+	return &Error{Err: sentinel, RetryAfter: max(retryAfter, 0), Detail: detail}
+}
+
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return e.Err.Error()
+	}
+	return e.Err.Error() + ": " + e.Detail
+}
+
+// Unwrap returns the platform sentinel
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// FromSeconds parses a delta-seconds header value such as "30" or "1.5"; 0 when missing or invalid
+func FromSeconds(v string) time.Duration {
+	sec, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || sec <= 0 {
+		return 0
+	}
+	return time.Duration(sec * float64(time.Second))
+}
+
+// FromUnix returns the time left until a unix-seconds timestamp header value; 0 when missing or invalid
+func FromUnix(v string) time.Duration {
+	sec, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return max(time.Until(time.Unix(sec, 0)), 0)
+}
@@ -0,0 +1,382 @@
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultAPIBaseURL is the OAuth API host
+const defaultAPIBaseURL = "https://oauth.reddit.com"
+
+// maxCommentPages bounds how many 100 item pages of the user's comments are scanned
+const maxCommentPages = 10
+
+// Actions supported by CheckAction
+const (
+	ActionJoinSubreddit = "join_subreddit"
+	ActionUpvote        = "upvote"
+	ActionComment       = "comment"
+)
+
+var (
+	// ErrInvalidUsername is returned when a user is not a valid Reddit username or u/ link
+	ErrInvalidUsername = errors.New("invalid reddit username")
+	// ErrInvalidTarget is returned when a subreddit or post cannot be parsed
+	ErrInvalidTarget = errors.New("invalid reddit target")
+	// ErrTargetNotFound is returned when the subreddit or post does not exist, is private or banned
+	ErrTargetNotFound = errors.New("reddit target not found")
+	// ErrUnauthorized is returned when the user's token is missing, expired or revoked
+	ErrUnauthorized = errors.New("reddit authorization required")
+	// ErrRateLimited is returned when the token ran out of requests for the current window
+	ErrRateLimited = errors.New("reddit rate limited")
+
+	usernamePattern  = regexp.MustCompile(`^[A-Za-z0-9_-]{3,20}$`)
+	subredditPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_]{1,20}$`)
+	postIDPattern    = regexp.MustCompile(`^[a-z0-9]{1,12}$`)
+
+	// errScanLimit is returned by HasCommented when the comment history is longer than maxCommentPages pages
+	errScanLimit = errors.New("comment history too long to scan")
+)
+
+// rateLimits remembers, per access token, when an exhausted X-Ratelimit window resets
+var rateLimits = struct {
+	sync.Mutex
+	resets map[string]time.Time
+}{resets: map[string]time.Time{}}
+
+// Client calls the Reddit OAuth API with a user's access token
+type Client struct {
+	baseURL     string
+	accessToken string
+	httpClient  *http.Client
+}
+
+// NewClient creates a client for accessToken.
+// Config via ENV:
+//   - REDDIT_API_BASE_URL (optional): override the API base URL (defaults to https://oauth.reddit.com)
+//   - REDDIT_USER_AGENT (optional): User-Agent sent to Reddit (defaults to web:checkingsocial:v1.0)
+func NewClient(accessToken string) (*Client, error) {
+	if accessToken == "" {
+		return nil, fmt.Errorf("%w: reddit checks need the user's token", ErrUnauthorized)
+	}
+	baseURL := strings.TrimRight(os.Getenv("REDDIT_API_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = defaultAPIBaseURL
+	}
+	return &Client{baseURL: baseURL, accessToken: accessToken, httpClient: &http.Client{Timeout: 15 * time.Second}}, nil
+}
+
+// waitRateLimit blocks until the token's window resets when it was exhausted; it fails fast when the
+// wait would outlast ctx
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	rateLimits.Lock()
+	reset, ok := rateLimits.resets[c.accessToken]
+	rateLimits.Unlock()
+	delay := time.Until(reset)
+	if !ok || delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return ratelimit.New(ErrRateLimited, delay, "window resets in "+delay.Round(time.Second).String())
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// recordRateLimit stores the window reset when X-Ratelimit-Remaining reached zero
+func (c *Client) recordRateLimit(h http.Header) {
+	remaining, err := strconv.ParseFloat(h.Get("X-Ratelimit-Remaining"), 64)
+	reset, resetErr := strconv.ParseFloat(h.Get("X-Ratelimit-Reset"), 64)
+	rateLimits.Lock()
+	defer rateLimits.Unlock()
+	if err != nil || resetErr != nil || remaining >= 1 {
+		delete(rateLimits.resets, c.accessToken)
+		return
+	}
+	rateLimits.resets[c.accessToken] = time.Now().Add(time.Duration(reset * float64(time.Second)))
+}
+
+// get performs a GET on path and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	if err := c.waitRateLimit(ctx); err != nil {
+		return err
+	}
+	query.Set("raw_json", "1")
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	c.recordRateLimit(resp.Header)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: token rejected", ErrUnauthorized)
+	case http.StatusTooManyRequests:
+		reset := ratelimit.FromSeconds(resp.Header.Get("X-Ratelimit-Reset"))
+		if reset == 0 {
+			return ratelimit.New(ErrRateLimited, 0, "")
+		}
+		return ratelimit.New(ErrRateLimited, reset, "window resets in "+reset.Round(time.Second).String())
+	case http.StatusForbidden, http.StatusNotFound:
+		// Private, quarantined and banned subreddits answer 403; unknown ones 404 (or a search redirect)
+		return fmt.Errorf("%w: %s", ErrTargetNotFound, path)
+	}
+	return fmt.Errorf("reddit %s failed with status %d", path, resp.StatusCode)
+}
+
+// Me returns the username of the token's owner
+func (c *Client) Me(ctx context.Context) (string, error) {
+	var out struct {
+		Name string `json:"name"`
+	}
+	if err := c.get(ctx, "/api/v1/me", url.Values{}, &out); err != nil {
+		return "", err
+	}
+	if out.Name == "" {
+		return "", fmt.Errorf("%w: /api/v1/me returned no name", ErrUnauthorized)
+	}
+	return strings.ToLower(out.Name), nil
+}
+
+// IsSubscriber reports whether the token's owner joined the subreddit
+func (c *Client) IsSubscriber(ctx context.Context, subreddit string) (bool, error) {
+	var out struct {
+		Kind string `json:"kind"`
+		Data struct {
+			UserIsSubscriber *bool `json:"user_is_subscriber"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/r/"+subreddit+"/about", url.Values{}, &out); err != nil {
+		return false, err
+	}
+	if out.Kind != "t5" {
+		return false, fmt.Errorf("%w: r/%s", ErrTargetNotFound, subreddit)
+	}
+	return out.Data.UserIsSubscriber != nil && *out.Data.UserIsSubscriber, nil
+}
+
+// HasUpvoted reports whether the token's owner upvoted the post (likes is true for up, false for down)
+func (c *Client) HasUpvoted(ctx context.Context, postID string) (bool, error) {
+	q := url.Values{}
+	q.Set("id", "t3_"+postID)
+	var out struct {
+		Data struct {
+			Children []struct {
+				Data struct {
+					Likes *bool `json:"likes"`
+				} `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/api/info", q, &out); err != nil {
+		return false, err
+	}
+	if len(out.Data.Children) == 0 {
+		return false, fmt.Errorf("%w: post %s", ErrTargetNotFound, postID)
+	}
+	likes := out.Data.Children[0].Data.Likes
+	return likes != nil && *likes, nil
+}
+
+// HasCommented scans the user's newest comments for one on the post; it returns errScanLimit when none of
+// the first maxCommentPages pages has one and the history goes on
+func (c *Client) HasCommented(ctx context.Context, username string, postID string) (bool, error) {
+	q := url.Values{}
+	q.Set("limit", "100")
+	q.Set("sort", "new")
+	for page := 0; page < maxCommentPages; page++ {
+		var out struct {
+			Data struct {
+				After    string `json:"after"`
+				Children []struct {
+					Data struct {
+						LinkID string `json:"link_id"`
+					} `json:"data"`
+				} `json:"children"`
+			} `json:"data"`
+		}
+		if err := c.get(ctx, "/user/"+username+"/comments", q, &out); err != nil {
+			return false, err
+		}
+		for _, child := range out.Data.Children {
+			if child.Data.LinkID == "t3_"+postID {
+				return true, nil
+			}
+		}
+		if out.Data.After == "" {
+			return false, nil
+		}
+		q.Set("after", out.Data.After)
+	}
+	return false, fmt.Errorf("%w: u/%s has more than %d pages of comments", errScanLimit, username, maxCommentPages)
+}
+
+// ParseUsername accepts "name", "u/name", "/u/name" or a reddit.com/user/ URL and returns the lowercased name
+func ParseUsername(username string) (string, error) {
+	s := strings.TrimSpace(username)
+	if segments, ok := redditPath(s); ok {
+		if len(segments) < 2 || (segments[0] != "user" && segments[0] != "u") {
+			return "", fmt.Errorf("%w: %q", ErrInvalidUsername, username)
+		}
+		s = segments[1]
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "/"), "u/")
+	if !usernamePattern.MatchString(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUsername, username)
+	}
+	return strings.ToLower(s), nil
+}
+
+// ParseSubreddit accepts "name", "r/name" or a reddit.com/r/ URL
+func ParseSubreddit(subreddit string) (string, error) {
+	s := strings.TrimSpace(subreddit)
+	if segments, ok := redditPath(s); ok {
+		if len(segments) < 2 || segments[0] != "r" {
+			return "", fmt.Errorf("%w: %q", ErrInvalidTarget, subreddit)
+		}
+		s = segments[1]
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "/"), "r/")
+	if !subredditPattern.MatchString(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, subreddit)
+	}
+	return s, nil
+}
+
+// ParsePostID accepts a post ID ("1abcde", "t3_1abcde"), a reddit.com/r/<sub>/comments/<id>/ URL or a redd.it link
+func ParsePostID(post string) (string, error) {
+	s := strings.TrimSpace(post)
+	if segments, ok := redditPath(s); ok {
+		switch {
+		case len(segments) == 1:
+			// redd.it/<id>
+			s = segments[0]
+		case len(segments) >= 4 && segments[0] == "r" && segments[2] == "comments":
+			s = segments[3]
+		case len(segments) >= 2 && segments[0] == "comments":
+			s = segments[1]
+		default:
+			return "", fmt.Errorf("%w: %q", ErrInvalidTarget, post)
+		}
+	}
+	s = strings.ToLower(strings.TrimPrefix(s, "t3_"))
+	if !postIDPattern.MatchString(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, post)
+	}
+	return s, nil
+}
+
+// redditPath returns the path segments of s when it is a reddit.com or redd.it URL
+func redditPath(s string) ([]string, bool) {
+	lower := strings.ToLower(s)
+	if !strings.Contains(lower, "reddit.com/") && !strings.Contains(lower, "redd.it/") {
+		return nil, false
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, false
+	}
+	return strings.Split(strings.Trim(u.Path, "/"), "/"), true
+}
+
+// ActionResult is the outcome of CheckAction
+type ActionResult struct {
+	Username string
+	Target   string
+	Done     bool
+	// Verifiable is false when the comment history was too long to scan; Done is then false
+	Verifiable bool
+	// Reason explains why the check was not verifiable
+	Reason string
+}
+
+// CheckAction checks join_subreddit, upvote or comment with the token the user granted.
+// target is a subreddit for join_subreddit (empty uses REDDIT_SUBREDDIT) and a post for upvote and comment.
+func CheckAction(accessToken string, username string, action string, target string) (*ActionResult, error) {
+	_ = godotenv.Load()
+
+	username, err := ParseUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res := &ActionResult{Username: username, Verifiable: true}
+	switch action {
+	case ActionJoinSubreddit:
+		if strings.TrimSpace(target) == "" {
+			target = os.Getenv("REDDIT_SUBREDDIT")
+		}
+		if res.Target, err = ParseSubreddit(target); err != nil {
+			return nil, err
+		}
+		res.Done, err = client.IsSubscriber(ctx, res.Target)
+	case ActionUpvote, ActionComment:
+		if res.Target, err = ParsePostID(target); err != nil {
+			return nil, err
+		}
+		if action == ActionUpvote {
+			res.Done, err = client.HasUpvoted(ctx, res.Target)
+		} else {
+			res.Done, err = client.HasCommented(ctx, username, res.Target)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported reddit action %q", action)
+	}
+	if errors.Is(err, errScanLimit) {
+		res.Verifiable, res.Reason = false, err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Reddit][DEBUG] CheckAction user=%s action=%s target=%s done=%v verifiable=%v", username, action, res.Target, res.Done, res.Verifiable)
+	return res, nil
+}
@@ -0,0 +1,149 @@
+package reddit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// fakeCommentsAPI serves /user/{name}/comments: alice commented on post abc on her second page, bob's
+// history never ends and has no comment on it
+func fakeCommentsAPI(t *testing.T) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /user/{name}/comments", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		link := "t3_other"
+		if r.PathValue("name") == "alice" && page == 1 {
+			link = "t3_abc"
+		}
+		fmt.Fprintf(w, `{"data":{"after":"%d","children":[{"data":{"link_id":%q}}]}}`, page+1, link)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Setenv("REDDIT_API_BASE_URL", srv.URL)
+}
+
+func TestCheckActionComment(t *testing.T) {
+	fakeCommentsAPI(t)
+
+	res, err := CheckAction("token-alice", "u/alice", ActionComment, "t3_abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Done || !res.Verifiable {
+		t.Errorf("comment on the second page: got done=%v verifiable=%v", res.Done, res.Verifiable)
+	}
+
+	res, err = CheckAction("token-bob", "bob", ActionComment, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Done || res.Verifiable || res.Reason == "" {
+		t.Errorf("history past the scan cap: got done=%v verifiable=%v reason=%q, want an unverifiable result", res.Done, res.Verifiable, res.Reason)
+	}
+}
+
+// fakeRateLimitedAPI serves /api/v1/me with the X-Ratelimit-* headers (and status) of the next answer
+// and counts the requests that reached it
+func fakeRateLimitedAPI(t *testing.T, remaining string, reset string, status int) (*Client, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("X-Ratelimit-Remaining", remaining)
+		w.Header().Set("X-Ratelimit-Reset", reset)
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"name":"Alice"}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("REDDIT_API_BASE_URL", srv.URL)
+	client, err := NewClient(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, &hits
+}
+
+func TestRateLimitWaitsForWindowReset(t *testing.T) {
+	client, hits := fakeRateLimitedAPI(t, "0", "0.3", http.StatusOK)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Me(ctx); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := client.Me(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited < 250*time.Millisecond {
+		t.Errorf("second request sent after %v, want it held until the window reset", waited)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("API got %d requests, want 2", n)
+	}
+}
+
+func TestRateLimitFailsFastPastDeadline(t *testing.T) {
+	client, hits := fakeRateLimitedAPI(t, "0", "120", http.StatusOK)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Me(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Me(ctx)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+	var rl *ratelimit.Error
+	if !errors.As(err, &rl) || rl.RetryAfter < 110*time.Second || rl.RetryAfter > 120*time.Second {
+		t.Errorf("retry after %v, want about 120s", rl)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("API got %d requests, want the exhausted window to stop the second one", n)
+	}
+}
+
+func TestRateLimit429(t *testing.T) {
+	client, _ := fakeRateLimitedAPI(t, "0", "42", http.StatusTooManyRequests)
+
+	_, err := client.Me(context.Background())
+	var rl *ratelimit.Error
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) || rl.RetryAfter != 42*time.Second {
+		t.Fatalf("got %v, want ErrRateLimited retrying after 42s", err)
+	}
+}
+
+func TestRateLimit429WithoutReset(t *testing.T) {
+	client, _ := fakeRateLimitedAPI(t, "", "", http.StatusTooManyRequests)
+
+	_, err := client.Me(context.Background())
+	var rl *ratelimit.Error
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) || rl.RetryAfter != 0 || rl.Detail != "" {
+		t.Fatalf("got %v, want ErrRateLimited without a retry hint", err)
+	}
+}
+
+func TestRateLimitClearedWhenRequestsRemain(t *testing.T) {
+	client, _ := fakeRateLimitedAPI(t, "599.0", "120", http.StatusOK)
+	rateLimits.Lock()
+	rateLimits.resets[client.accessToken] = time.Now().Add(time.Hour)
+	rateLimits.Unlock()
+	client.recordRateLimit(http.Header{"X-Ratelimit-Remaining": {"599.0"}, "X-Ratelimit-Reset": {"120"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := client.Me(ctx); err != nil {
+		t.Fatalf("window with requests left: %v", err)
+	}
+}
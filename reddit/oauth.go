@@ -0,0 +1,164 @@
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	defaultAuthURL  = "https://www.reddit.com/api/v1/authorize"
+	defaultTokenURL = "https://www.reddit.com/api/v1/access_token"
+	// scopes: identity for /api/v1/me, read for /r/<sub>/about and /api/info, history for the user's comments
+	scopes = "identity read history"
+)
+
+// ErrTokenRevoked is returned when Reddit rejects a refresh token
+var ErrTokenRevoked = errors.New("reddit refresh token revoked")
+
+// OAuthConfig is the Reddit OAuth app used to obtain user tokens
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+}
+
+// LoadOAuthConfig reads the OAuth app.
+// Config via ENV:
+//   - REDDIT_OAUTH_CLIENT_ID, REDDIT_OAUTH_CLIENT_SECRET: the "web app" registered at reddit.com/prefs/apps
+//   - REDDIT_OAUTH_REDIRECT_URL: the registered redirect URI (our /api/v1/auth/reddit/callback)
+//   - REDDIT_OAUTH_AUTH_URL, REDDIT_OAUTH_TOKEN_URL (optional): override the Reddit endpoints
+func LoadOAuthConfig() (*OAuthConfig, error) {
+	_ = godotenv.Load()
+
+	cfg := &OAuthConfig{
+		ClientID:     os.Getenv("REDDIT_OAUTH_CLIENT_ID"),
+		ClientSecret: os.Getenv("REDDIT_OAUTH_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("REDDIT_OAUTH_REDIRECT_URL"),
+		AuthURL:      os.Getenv("REDDIT_OAUTH_AUTH_URL"),
+		TokenURL:     os.Getenv("REDDIT_OAUTH_TOKEN_URL"),
+	}
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, errors.New("REDDIT_OAUTH_CLIENT_ID and REDDIT_OAUTH_CLIENT_SECRET environment variables not set")
+	}
+	if cfg.AuthURL == "" {
+		cfg.AuthURL = defaultAuthURL
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = defaultTokenURL
+	}
+	return cfg, nil
+}
+
+// Token is an OAuth token granted by a Reddit user
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+// AuthCodeURL returns the consent URL; duration=permanent makes Reddit return a refresh token
+func (c *OAuthConfig) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("client_id", c.ClientID)
+	q.Set("response_type", "code")
+	q.Set("state", state)
+	q.Set("redirect_uri", c.RedirectURL)
+	q.Set("duration", "permanent")
+	q.Set("scope", scopes)
+	return c.AuthURL + "?" + q.Encode()
+}
+
+// Exchange trades an authorization code for a token
+func (c *OAuthConfig) Exchange(ctx context.Context, code string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.RedirectURL)
+	return c.token(ctx, form)
+}
+
+// Refresh obtains a new access token, keeping refreshToken when Reddit does not rotate it
+func (c *OAuthConfig) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	tok, err := c.token(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = refreshToken
+	}
+	return tok, nil
+}
+
+// token posts form to the token endpoint with the app credentials as HTTP Basic auth
+func (c *OAuthConfig) token(ctx context.Context, form url.Values) (*Token, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.ClientID, c.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := (&http.Client{Timeout: 15 * time.Second}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	var out struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+		Error        string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("token endpoint: status %d: failed to unmarshal response: %w", resp.StatusCode, err)
+	}
+	if out.Error == "invalid_grant" {
+		return nil, fmt.Errorf("%w: %s", ErrTokenRevoked, out.Error)
+	}
+	if resp.StatusCode != http.StatusOK || out.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint failed with status %d: %s", resp.StatusCode, out.Error)
+	}
+	return &Token{
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(out.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// RefreshToken refreshes a stored token with the OAuth app from the environment
+func RefreshToken(ctx context.Context, refreshToken string) (*Token, error) {
+	cfg, err := LoadOAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Refresh(ctx, refreshToken)
+}
+
+// userAgent returns the User-Agent Reddit requires on every request
+func userAgent() string {
+	if ua := os.Getenv("REDDIT_USER_AGENT"); ua != "" {
+		return ua
+	}
+	return "web:checkingsocial:v1.0"
+}
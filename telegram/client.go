@@ -0,0 +1,233 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultAPIBaseURL is the Telegram Bot API base URL
+const defaultAPIBaseURL = "https://api.telegram.org"
+
+// ActionJoin checks that the user is a member of the chat
+const ActionJoin = "join"
+
+// Chat member statuses returned by getChatMember
+const (
+	StatusCreator       = "creator"
+	StatusAdministrator = "administrator"
+	StatusMember        = "member"
+	StatusRestricted    = "restricted"
+	StatusLeft          = "left"
+	StatusKicked        = "kicked"
+)
+
+var (
+	// ErrInvalidUserID is returned when the user is not a numeric Telegram user ID
+	ErrInvalidUserID = errors.New("invalid telegram user id")
+	// ErrInvalidChat is returned when the chat is neither a numeric chat ID nor an @username
+	ErrInvalidChat = errors.New("invalid telegram chat")
+	// ErrUserNotFound is returned when Telegram does not know the user
+	ErrUserNotFound = errors.New("telegram user not found")
+	// ErrChatNotFound is returned when the chat does not exist or the bot is not in it
+	ErrChatNotFound = errors.New("telegram chat not found")
+	// ErrRateLimited is returned when the Bot API answered 429
+	ErrRateLimited = errors.New("telegram rate limited")
+)
+
+// Client calls the Telegram Bot API
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a Bot API client.
+// Config via ENV:
+//   - TELEGRAM_BOT_TOKEN: the bot token; the bot must be a member (admin for channels) of the chat
+//   - TELEGRAM_API_BASE_URL (optional): override the API base URL (defaults to https://api.telegram.org)
+func NewClient() (*Client, error) {
+	token := os.Getenv("TELEGRAM_BOT_TOKEN")
+	if token == "" {
+		return nil, errors.New("TELEGRAM_BOT_TOKEN environment variable not set")
+	}
+	baseURL := strings.TrimRight(os.Getenv("TELEGRAM_API_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = defaultAPIBaseURL
+	}
+	return &Client{
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// ChatMember is the result of getChatMember
+type ChatMember struct {
+	Status string `json:"status"`
+	User   struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	// IsMember is set for restricted members
+	IsMember bool `json:"is_member"`
+}
+
+// InChat reports whether the status counts as being in the chat
+func (m *ChatMember) InChat() bool {
+	switch m.Status {
+	case StatusCreator, StatusAdministrator, StatusMember:
+		return true
+	case StatusRestricted:
+		return m.IsMember
+	}
+	return false
+}
+
+// apiResponse is the Bot API envelope
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// call performs a Bot API method and decodes its result into out
+func (c *Client) call(ctx context.Context, method string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/bot"+c.token+"/"+method, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The URL contains the token, so only the method is reported
+		return fmt.Errorf("telegram %s request failed", method)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	var envelope apiResponse
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("telegram %s: status %d: failed to unmarshal response: %w", method, resp.StatusCode, err)
+	}
+	if !envelope.OK {
+		desc := strings.ToLower(envelope.Description)
+		switch {
+		case envelope.ErrorCode == http.StatusTooManyRequests:
+			retryAfter := time.Duration(envelope.Parameters.RetryAfter) * time.Second
+			return ratelimit.New(ErrRateLimited, retryAfter, "retry after "+retryAfter.String())
+		case strings.Contains(desc, "chat not found"):
+			return fmt.Errorf("%w: %s", ErrChatNotFound, envelope.Description)
+		case strings.Contains(desc, "user not found"), strings.Contains(desc, "participant_id_invalid"):
+			return fmt.Errorf("%w: %s", ErrUserNotFound, envelope.Description)
+		}
+		return fmt.Errorf("telegram %s failed with code %d: %s", method, envelope.ErrorCode, envelope.Description)
+	}
+	if err := json.Unmarshal(envelope.Result, out); err != nil {
+		return fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+	return nil
+}
+
+// GetChatMember fetches the membership of userID in chat
+func (c *Client) GetChatMember(ctx context.Context, chat string, userID int64) (*ChatMember, error) {
+	q := url.Values{}
+	q.Set("chat_id", chat)
+	q.Set("user_id", strconv.FormatInt(userID, 10))
+	var member ChatMember
+	if err := c.call(ctx, "getChatMember", q, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// MembershipResult is the outcome of CheckMembership
+type MembershipResult struct {
+	UserID int64
+	Chat   string
+	Status string
+	Member bool
+}
+
+// CheckMembership checks if the numeric Telegram userID is in chat.
+// chat is a numeric chat ID ("-1001234567890") or a public @username; empty uses TELEGRAM_CHAT_ID.
+func CheckMembership(userID string, chat string) (*MembershipResult, error) {
+	_ = godotenv.Load()
+
+	uid, err := strconv.ParseInt(strings.TrimSpace(userID), 10, 64)
+	if err != nil || uid <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidUserID, userID)
+	}
+	chat, err = normalizeChat(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	member, err := client.GetChatMember(ctx, chat, uid)
+	if errors.Is(err, ErrUserNotFound) {
+		// Telegram answers this for users that never interacted with the chat: not a member, not an error
+		member = &ChatMember{Status: StatusLeft}
+	} else if err != nil {
+		return nil, err
+	}
+
+	res := &MembershipResult{UserID: uid, Chat: chat, Status: member.Status, Member: member.InChat()}
+	log.Printf("[Telegram][DEBUG] CheckMembership user=%d chat=%s status=%s member=%v", uid, chat, res.Status, res.Member)
+	return res, nil
+}
+
+// normalizeChat accepts a chat ID, an @username or a t.me link; empty falls back to TELEGRAM_CHAT_ID
+func normalizeChat(chat string) (string, error) {
+	chat = strings.TrimSpace(chat)
+	if chat == "" {
+		chat = strings.TrimSpace(os.Getenv("TELEGRAM_CHAT_ID"))
+		if chat == "" {
+			return "", fmt.Errorf("%w: no target and TELEGRAM_CHAT_ID not set", ErrInvalidChat)
+		}
+	}
+	if _, err := strconv.ParseInt(chat, 10, 64); err == nil {
+		return chat, nil
+	}
+
+	for _, prefix := range []string{"https://t.me/", "http://t.me/", "t.me/", "https://telegram.me/", "telegram.me/"} {
+		if strings.HasPrefix(strings.ToLower(chat), prefix) {
+			chat = chat[len(prefix):]
+			break
+		}
+	}
+	chat = strings.TrimPrefix(strings.Trim(chat, "/"), "@")
+	if chat == "" || strings.ContainsAny(chat, "/?# ") || strings.HasPrefix(chat, "+") {
+		// Private invite links (t.me/+xyz) cannot be used as chat_id
+		return "", fmt.Errorf("%w: %q", ErrInvalidChat, chat)
+	}
+	return "@" + chat, nil
+}
@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// fakeBotAPI answers getChatMember from a user_id -> response body table
+func fakeBotAPI(t *testing.T, answers map[string]string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottest-token/getChatMember" {
+			http.NotFound(w, r)
+			return
+		}
+		body, ok := answers[r.URL.Query().Get("user_id")]
+		if !ok {
+			t.Errorf("unexpected user_id %q", r.URL.Query().Get("user_id"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test-token")
+	t.Setenv("TELEGRAM_API_BASE_URL", srv.URL)
+}
+
+func TestCheckMembership(t *testing.T) {
+	fakeBotAPI(t, map[string]string{
+		"1": `{"ok":true,"result":{"status":"member","user":{"id":1}}}`,
+		"2": `{"ok":true,"result":{"status":"restricted","is_member":false,"user":{"id":2}}}`,
+		"3": `{"ok":false,"error_code":400,"description":"Bad Request: user not found"}`,
+		"4": `{"ok":false,"error_code":400,"description":"Bad Request: PARTICIPANT_ID_INVALID"}`,
+		"5": `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`,
+	})
+
+	tests := []struct {
+		user   string
+		status string
+		member bool
+	}{
+		{"1", StatusMember, true},
+		{"2", StatusRestricted, false},
+		{"3", StatusLeft, false},
+		{"4", StatusLeft, false},
+	}
+	for _, tt := range tests {
+		res, err := CheckMembership(tt.user, "@chat")
+		if err != nil {
+			t.Fatalf("user %s: %v", tt.user, err)
+		}
+		if res.Status != tt.status || res.Member != tt.member {
+			t.Errorf("user %s: got status=%s member=%v, want status=%s member=%v", tt.user, res.Status, res.Member, tt.status, tt.member)
+		}
+	}
+
+	if _, err := CheckMembership("5", "@chat"); !errors.Is(err, ErrChatNotFound) {
+		t.Errorf("chat not found: got %v, want ErrChatNotFound", err)
+	}
+}
+
+func TestCheckMembershipRateLimited(t *testing.T) {
+	fakeBotAPI(t, map[string]string{
+		"6": `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`,
+	})
+
+	_, err := CheckMembership("6", "@chat")
+	var rl *ratelimit.Error
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &rl) || rl.RetryAfter != 7*time.Second {
+		t.Fatalf("got %v, want ErrRateLimited retrying after 7s", err)
+	}
+}
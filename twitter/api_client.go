@@ -0,0 +1,265 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+const (
+	// defaultAPIBaseURL is the X API v2 base URL
+	defaultAPIBaseURL = "https://api.x.com/2"
+	// maxAPIPages bounds how many pages are scanned per lookup
+	maxAPIPages = 15
+	// apiTweetLimit is how many recent tweets ProfileTexts fetches
+	apiTweetLimit = 20
+)
+
+// APIClient queries the official X API v2 with an app bearer token or an OAuth2 user-context token
+type APIClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewAPIClient creates an X API client.
+// Config via ENV:
+//   - X_API_USER_TOKEN: OAuth2 user-context access token (preferred when set)
+//   - X_API_BEARER_TOKEN: app-only bearer token
+//   - X_API_BASE_URL (optional): override the API base URL (defaults to https://api.x.com/2)
+func NewAPIClient() (*APIClient, error) {
+	token := os.Getenv("X_API_USER_TOKEN")
+	if token == "" {
+		token = os.Getenv("X_API_BEARER_TOKEN")
+	}
+	if token == "" {
+		return nil, errors.New("X_API_BEARER_TOKEN or X_API_USER_TOKEN environment variable not set")
+	}
+	baseURL := strings.TrimRight(os.Getenv("X_API_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = defaultAPIBaseURL
+	}
+
+	return &APIClient{
+		baseURL: baseURL,
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// APIError is an entry of the "errors" array returned next to (or instead of) "data"
+type APIError struct {
+	Title        string `json:"title"`
+	Detail       string `json:"detail"`
+	Type         string `json:"type"`
+	ResourceType string `json:"resource_type"`
+}
+
+// APIUser is an X user object
+type APIUser struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// apiUsersPage is a page of users (following, liking_users, retweeted_by)
+type apiUsersPage struct {
+	Data   []APIUser  `json:"data"`
+	Errors []APIError `json:"errors"`
+	Meta   struct {
+		NextToken   string `json:"next_token"`
+		ResultCount int    `json:"result_count"`
+	} `json:"meta"`
+}
+
+// getJSON performs a GET against the X API and decodes the JSON body into out
+func (c *APIClient) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		reset := resp.Header.Get("x-rate-limit-reset")
+		if sec, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			return ratelimit.New(ErrRateLimited, ratelimit.FromUnix(reset), "resets at "+time.Unix(sec, 0).UTC().Format(time.RFC3339))
+		}
+		return ErrRateLimited
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrUserNotFound
+	case resp.StatusCode != http.StatusOK:
+		snippet := string(respBody)
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		return fmt.Errorf("x api request failed with status %d: %s", resp.StatusCode, snippet)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// LookupUser fetches a user (with bio) by username
+func (c *APIClient) LookupUser(ctx context.Context, username string) (*APIUser, error) {
+	var resp struct {
+		Data   *APIUser   `json:"data"`
+		Errors []APIError `json:"errors"`
+	}
+	q := url.Values{}
+	q.Set("user.fields", "description")
+	if err := c.getJSON(ctx, "/users/by/username/"+url.PathEscape(username), q, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+	}
+	return resp.Data, nil
+}
+
+// scanUsers pages through a users endpoint until a user with id is found.
+// It returns ErrScanLimit when the list has more than maxAPIPages pages and id was not on them.
+func (c *APIClient) scanUsers(ctx context.Context, path string, pageSize int, id string, notFound error) (bool, error) {
+	q := url.Values{}
+	q.Set("max_results", strconv.Itoa(pageSize))
+	for page := 0; page < maxAPIPages; page++ {
+		var resp apiUsersPage
+		if err := c.getJSON(ctx, path, q, &resp); err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				return false, notFound
+			}
+			return false, err
+		}
+		// A missing resource is reported as 200 with errors and no data
+		if len(resp.Data) == 0 && len(resp.Errors) > 0 && page == 0 {
+			return false, fmt.Errorf("%w: %s", notFound, resp.Errors[0].Detail)
+		}
+		for _, u := range resp.Data {
+			if u.ID == id {
+				return true, nil
+			}
+		}
+		if resp.Meta.NextToken == "" {
+			return false, nil
+		}
+		q.Set("pagination_token", resp.Meta.NextToken)
+	}
+	return false, fmt.Errorf("%w: %s has more than %d pages", ErrScanLimit, path, maxAPIPages)
+}
+
+// Name implements Provider
+func (c *APIClient) Name() string {
+	return ProviderAPI
+}
+
+// Follows implements Provider with GET /users/:id/following
+func (c *APIClient) Follows(ctx context.Context, user string, target string) (bool, error) {
+	u, err := c.LookupUser(ctx, user)
+	if err != nil {
+		return false, err
+	}
+	t, err := c.LookupUser(ctx, target)
+	if err != nil {
+		return false, err
+	}
+	return c.scanUsers(ctx, "/users/"+u.ID+"/following", 1000, t.ID, ErrUserNotFound)
+}
+
+// HasLiked implements Provider with GET /tweets/:id/liking_users
+func (c *APIClient) HasLiked(ctx context.Context, user string, tweetID string) (bool, error) {
+	u, err := c.LookupUser(ctx, user)
+	if err != nil {
+		return false, err
+	}
+	return c.scanUsers(ctx, "/tweets/"+tweetID+"/liking_users", 100, u.ID, ErrTweetNotFound)
+}
+
+// HasRetweeted implements Provider with GET /tweets/:id/retweeted_by
+func (c *APIClient) HasRetweeted(ctx context.Context, user string, tweetID string) (bool, error) {
+	u, err := c.LookupUser(ctx, user)
+	if err != nil {
+		return false, err
+	}
+	return c.scanUsers(ctx, "/tweets/"+tweetID+"/retweeted_by", 100, u.ID, ErrTweetNotFound)
+}
+
+// ProfileTexts implements Provider with the user bio and GET /users/:id/tweets
+func (c *APIClient) ProfileTexts(ctx context.Context, user string) ([]string, error) {
+	u, err := c.LookupUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data []struct {
+			ID   string `json:"id"`
+			Text string `json:"text"`
+		} `json:"data"`
+	}
+	q := url.Values{}
+	q.Set("max_results", strconv.Itoa(apiTweetLimit))
+	if err := c.getJSON(ctx, "/users/"+u.ID+"/tweets", q, &resp); err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, 0, len(resp.Data)+1)
+	if u.Description != "" {
+		texts = append(texts, u.Description)
+	}
+	for _, t := range resp.Data {
+		texts = append(texts, t.Text)
+	}
+	return texts, nil
+}
+
+// LookupUserID implements Provider with GET /users/by/username/:username
+func (c *APIClient) LookupUserID(ctx context.Context, username string) (string, error) {
+	u, err := c.LookupUser(ctx, username)
+	if err != nil {
+		return "", err
+	}
+	return u.ID, nil
+}
+
+// LookupUsername implements Provider with GET /users/:id
+func (c *APIClient) LookupUsername(ctx context.Context, id string) (string, error) {
+	var resp struct {
+		Data *APIUser `json:"data"`
+	}
+	if err := c.getJSON(ctx, "/users/"+url.PathEscape(id), url.Values{}, &resp); err != nil {
+		return "", err
+	}
+	if resp.Data == nil {
+		return "", fmt.Errorf("%w: id %s", ErrUserNotFound, id)
+	}
+	return resp.Data.Username, nil
+}
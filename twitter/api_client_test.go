@@ -0,0 +1,226 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+// fakeXAPI serves the X API v2 endpoints used by APIClient for users alice (1) and target (2)
+// and tweet 100, liked by alice and retweeted by nobody. Following lists are paginated; tweet 200 has
+// more retweeters than maxAPIPages pages.
+func fakeXAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	users := map[string]APIUser{
+		"alice":  {ID: "1", Username: "alice", Description: "bio"},
+		"target": {ID: "2", Username: "target"},
+	}
+	write := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/by/username/{username}", func(w http.ResponseWriter, r *http.Request) {
+		u, ok := users[r.PathValue("username")]
+		if !ok {
+			write(w, map[string]any{"errors": []APIError{{Title: "Not Found Error", Detail: "Could not find user"}}})
+			return
+		}
+		write(w, map[string]any{"data": u})
+	})
+	mux.HandleFunc("GET /users/{id}/following", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("max_results") != "1000" {
+			t.Errorf("following max_results = %q", r.URL.Query().Get("max_results"))
+		}
+		switch {
+		case r.PathValue("id") == "1" && r.URL.Query().Get("pagination_token") == "":
+			write(w, map[string]any{"data": []APIUser{{ID: "7"}}, "meta": map[string]any{"next_token": "p2", "result_count": 1}})
+		case r.PathValue("id") == "1" && r.URL.Query().Get("pagination_token") == "p2":
+			write(w, map[string]any{"data": []APIUser{{ID: "8"}, {ID: "2"}}, "meta": map[string]any{"result_count": 2}})
+		default:
+			write(w, map[string]any{"meta": map[string]any{"result_count": 0}})
+		}
+	})
+	mux.HandleFunc("GET /tweets/{id}/liking_users", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "100" {
+			write(w, map[string]any{"errors": []APIError{{Title: "Not Found Error", Detail: "Could not find tweet"}}})
+			return
+		}
+		write(w, map[string]any{"data": []APIUser{{ID: "1"}}, "meta": map[string]any{"result_count": 1}})
+	})
+	mux.HandleFunc("GET /tweets/{id}/retweeted_by", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "200" {
+			// Tweet 200 has endless retweeters, none of them alice
+			write(w, map[string]any{"data": []APIUser{{ID: "9"}}, "meta": map[string]any{"next_token": "more", "result_count": 1}})
+			return
+		}
+		write(w, map[string]any{"meta": map[string]any{"result_count": 0}})
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestAPIClient(t *testing.T, baseURL string) *APIClient {
+	t.Helper()
+	t.Setenv("X_API_USER_TOKEN", "")
+	t.Setenv("X_API_BEARER_TOKEN", "test-token")
+	t.Setenv("X_API_BASE_URL", baseURL)
+	c, err := NewAPIClient()
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	return c
+}
+
+func TestAPIClientLookups(t *testing.T) {
+	c := newTestAPIClient(t, fakeXAPI(t).URL)
+	ctx := context.Background()
+
+	if ok, err := c.Follows(ctx, "alice", "target"); err != nil || !ok {
+		t.Fatalf("alice follows target (second page): %v, %v", ok, err)
+	}
+	if ok, err := c.Follows(ctx, "target", "alice"); err != nil || ok {
+		t.Fatalf("target follows alice: %v, %v", ok, err)
+	}
+	if _, err := c.Follows(ctx, "ghost", "target"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("unknown user: got %v, want ErrUserNotFound", err)
+	}
+	if ok, err := c.HasLiked(ctx, "alice", "100"); err != nil || !ok {
+		t.Fatalf("alice liked 100: %v, %v", ok, err)
+	}
+	if _, err := c.HasLiked(ctx, "alice", "404"); !errors.Is(err, ErrTweetNotFound) {
+		t.Fatalf("unknown tweet: got %v, want ErrTweetNotFound", err)
+	}
+	if ok, err := c.HasRetweeted(ctx, "alice", "100"); err != nil || ok {
+		t.Fatalf("alice retweeted 100: %v, %v", ok, err)
+	}
+}
+
+func TestAPIClientRateLimited(t *testing.T) {
+	reset := time.Now().Add(2 * time.Minute).Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-rate-limit-reset", strconv.FormatInt(reset, 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	c := newTestAPIClient(t, srv.URL)
+
+	_, err := c.LookupUserID(context.Background(), "alice")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+	var rl *ratelimit.Error
+	if !errors.As(err, &rl) || rl.RetryAfter < 110*time.Second || rl.RetryAfter > 2*time.Minute {
+		t.Fatalf("retry after = %v, want about 2m from x-rate-limit-reset", rl)
+	}
+}
+
+func TestCheckActionProviderFallback(t *testing.T) {
+	srv := fakeXAPI(t)
+	newTestAPIClient(t, srv.URL)
+	t.Setenv("TWITTER_TARGET_USERNAME", "target")
+
+	// apify cannot answer likes, so the api provider answers
+	t.Setenv("X_PROVIDER", "apify,api")
+	res, err := CheckAction("@alice", ActionLike, "https://x.com/target/status/100")
+	if err != nil || !res.Done || res.Source != ProviderAPI {
+		t.Fatalf("like via fallback: %+v, %v", res, err)
+	}
+
+	// A missing tweet is definitive and must not fall through to apify
+	t.Setenv("X_PROVIDER", "api,apify")
+	if _, err := CheckAction("alice", ActionLike, "404"); !errors.Is(err, ErrTweetNotFound) {
+		t.Fatalf("missing tweet: got %v, want ErrTweetNotFound", err)
+	}
+
+	// When every provider fails the last error is returned
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	t.Setenv("X_API_BASE_URL", limited.URL)
+	t.Setenv("X_PROVIDER", "api")
+	if _, err := CheckAction("alice", ActionRetweet, "100"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("rate limited provider: got %v, want ErrRateLimited", err)
+	}
+}
+
+func TestCanonicalAccount(t *testing.T) {
+	srv := fakeXAPI(t)
+	newTestAPIClient(t, srv.URL)
+	ctx := context.Background()
+
+	t.Setenv("X_PROVIDER", "apify")
+	if _, err := CanonicalAccount(ctx, "@bob"); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("apify only: got %v, want ErrNotSupported", err)
+	}
+	if id, err := CanonicalAccount(ctx, "https://x.com/i/user/44196397"); err != nil || id != "44196397" {
+		t.Fatalf("numeric id: %q, %v", id, err)
+	}
+
+	t.Setenv("X_PROVIDER", "apify,api")
+	if id, err := CanonicalAccount(ctx, "@Alice"); err != nil || id != "1" {
+		t.Fatalf("resolved through the api provider: %q, %v", id, err)
+	}
+}
+
+func TestResolveCacheEviction(t *testing.T) {
+	defer func(max int) { maxResolveCacheEntries = max }(maxResolveCacheEntries)
+	maxResolveCacheEntries = 2
+	resolveCache.Lock()
+	resolveCache.entries = map[string]resolveCacheEntry{
+		"username:stale": {value: "9", expiresAt: time.Now().Add(-time.Minute)},
+	}
+	resolveCache.Unlock()
+
+	if _, ok := cachedValue("username:stale"); ok {
+		t.Fatalf("expired entry answered from the cache")
+	}
+	for i := 0; i < 5; i++ {
+		storeValue("username:u"+strconv.Itoa(i), strconv.Itoa(i))
+	}
+	resolveCache.RLock()
+	size := len(resolveCache.entries)
+	_, stale := resolveCache.entries["username:stale"]
+	resolveCache.RUnlock()
+	if size > maxResolveCacheEntries || stale {
+		t.Fatalf("cache holds %d entries (stale kept: %v), want at most %d", size, stale, maxResolveCacheEntries)
+	}
+	if value, ok := cachedValue("username:u4"); !ok || value != "4" {
+		t.Fatalf("latest entry = %q, %v, want 4", value, ok)
+	}
+}
+
+func TestCheckActionScanLimit(t *testing.T) {
+	srv := fakeXAPI(t)
+	newTestAPIClient(t, srv.URL)
+
+	for _, spec := range []string{"api", "api,apify"} {
+		t.Setenv("X_PROVIDER", spec)
+		res, err := CheckAction("alice", ActionRetweet, "200")
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+		if res.Done || res.Verifiable || res.Reason == "" || res.Source != ProviderAPI {
+			t.Fatalf("%s: result = %+v, want unverifiable after the page cap", spec, res)
+		}
+	}
+	res, err := CheckAction("alice", ActionRetweet, "100")
+	if err != nil || res.Done || !res.Verifiable {
+		t.Fatalf("short list: %+v, %v, want a verified false", res, err)
+	}
+}
@@ -29,27 +29,57 @@ func isTruthy(s string) bool {
 	return false
 }
 
-// CheckFollow checks if user_b (userID) follows user_a (target username) using Apify actor.
+// CheckFollow checks if user_b (userID) follows user_a (target username).
 // Inputs:
 //   - userID: will be sent as user_b (the account to check if it follows target)
 //
 // Config via ENV:
 //   - TWITTER_TARGET_USERNAME: the target account (user_a)
+//   - X_PROVIDER (optional): providers to try in order, "apify" (default) and/or "api" (see NewProviders)
 //   - APIFY_ACT_URL (optional): override Apify actor URL (defaults to UC0t7r32caYf7tYgZ)
 //   - X_TOKEN_FILE (optional): path to x.txt (default: ./x.txt) with lines: cookie|token
 //
-// Behavior:
+// Behavior with the apify provider:
 //   - Picks a random cookie|token pair from x.txt
 //   - Calls Apify run-sync-get-dataset-items with JSON body
 //   - Returns true if user_b_follows_user_a is true in the first item of result
 func CheckFollow(userID string) (bool, error) {
+	return checkBool(userID, ActionFollow)
+}
+
+// CheckFollowedBy checks if the target account follows userID back.
+// With the apify provider it runs the same actor with user_a/user_b swapped.
+func CheckFollowedBy(userID string) (bool, error) {
+	return checkBool(userID, ActionFollowedBy)
+}
+
+// CheckMutualFollow checks if userID and the target account follow each other
+func CheckMutualFollow(userID string) (bool, error) {
+	return checkBool(userID, ActionMutual)
+}
+
+// checkBool runs CheckAction for a follow-type action and drops the source
+func checkBool(userID string, action string) (bool, error) {
+	res, err := CheckAction(userID, action, "")
+	if err != nil {
+		return false, err
+	}
+	return res.Done, nil
+}
+
+// targetUsername returns TWITTER_TARGET_USERNAME
+func targetUsername() (string, error) {
 	_ = godotenv.Load()
 
 	target := os.Getenv("TWITTER_TARGET_USERNAME")
 	if target == "" {
-		return false, errors.New("TWITTER_TARGET_USERNAME not set")
+		return "", errors.New("TWITTER_TARGET_USERNAME not set")
 	}
+	return target, nil
+}
 
+// checkUserBFollowsUserA calls the Apify actor and returns user_b_follows_user_a
+func checkUserBFollowsUserA(userA, userB string) (bool, error) {
 	apifyURL := os.Getenv("APIFY_ACT_URL")
 	if apifyURL == "" {
 		apifyURL = "https://api.apify.com/v2/acts/UC0t7r32caYf7tYgZ/run-sync-get-dataset-items"
@@ -76,8 +106,8 @@ func CheckFollow(userID string) (bool, error) {
 		UserB   string `json:"user_b"`
 	}{
 		Cookies: cookies,
-		UserA:   target,
-		UserB:   userID,
+		UserA:   userA,
+		UserB:   userB,
 	}
 	bodyBytes, _ := json.Marshal(payload)
 
@@ -85,8 +115,8 @@ func CheckFollow(userID string) (bool, error) {
 	if isTruthy(os.Getenv("APIFY_DEBUG")) {
 		masked := map[string]any{
 			"cookies": "<masked>",
-			"user_a":  target,
-			"user_b":  userID,
+			"user_a":  userA,
+			"user_b":  userB,
 		}
 		mb, _ := json.Marshal(masked)
 		log.Printf("[Apify] URL=%s", apifyURL)
@@ -117,8 +147,8 @@ func CheckFollow(userID string) (bool, error) {
 		// Log masked request for troubleshooting (top-level payload)
 		masked := map[string]any{
 			"cookies": "<masked>",
-			"user_a":  target,
-			"user_b":  userID,
+			"user_a":  userA,
+			"user_b":  userB,
 		}
 		mb, _ := json.Marshal(masked)
 		log.Printf("[Apify][ERROR] URL=%s", apifyURL)
@@ -0,0 +1,113 @@
+package twitter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// profileTweetLimit is how many recent tweets are fetched when looking for a challenge code
+const profileTweetLimit = 20
+
+// fetchProfileTextsApify returns the bio and the recent tweet texts of username using an Apify scraper actor.
+// Config via ENV:
+//   - APIFY_PROFILE_ACT_URL (optional): override the actor URL (defaults to apidojo~tweet-scraper)
+//   - APIFY_ACT_KEY: JSON array of tokens, shared with CheckFollow
+func fetchProfileTextsApify(username string) ([]string, error) {
+	apifyURL := os.Getenv("APIFY_PROFILE_ACT_URL")
+	if apifyURL == "" {
+		apifyURL = "https://api.apify.com/v2/acts/apidojo~tweet-scraper/run-sync-get-dataset-items"
+	}
+	payload := map[string]any{
+		"twitterHandles": []string{username},
+		"maxItems":       profileTweetLimit,
+		"sort":           "Latest",
+	}
+	body, err := runApifyActor(apifyURL, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	type item struct {
+		Text     string `json:"text"`
+		FullText string `json:"fullText"`
+		Author   struct {
+			UserName    string `json:"userName"`
+			Description string `json:"description"`
+		} `json:"author"`
+	}
+	var items []item
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("apify decode error: %w", err)
+	}
+
+	var texts []string
+	bioSeen := false
+	for _, it := range items {
+		// Skip retweets/quotes surfaced from other authors
+		if it.Author.UserName != "" && !strings.EqualFold(it.Author.UserName, username) {
+			continue
+		}
+		if !bioSeen && it.Author.Description != "" {
+			texts = append(texts, it.Author.Description)
+			bioSeen = true
+		}
+		if it.FullText != "" {
+			texts = append(texts, it.FullText)
+		} else if it.Text != "" {
+			texts = append(texts, it.Text)
+		}
+	}
+	return texts, nil
+}
+
+// runApifyActor posts payload to a run-sync-get-dataset-items actor URL and returns the raw body
+func runApifyActor(apifyURL string, payload any) ([]byte, error) {
+	token, err := getRandomApifyToken()
+	if err != nil {
+		return nil, err
+	}
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if isTruthy(os.Getenv("APIFY_DEBUG")) {
+		log.Printf("[Apify] URL=%s", apifyURL)
+		log.Printf("[Apify] Request=%s", string(bodyBytes))
+	}
+
+	req, err := http.NewRequest("POST", apifyURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("apify read body error: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet := string(body)
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		log.Printf("[Apify][ERROR] URL=%s Status=%d Body=%s", apifyURL, resp.StatusCode, snippet)
+		return nil, fmt.Errorf("apify status %d: %s", resp.StatusCode, snippet)
+	}
+	return body, nil
+}
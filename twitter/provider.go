@@ -0,0 +1,291 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Provider names selectable with X_PROVIDER
+const (
+	ProviderApify = "apify"
+	ProviderAPI   = "api"
+)
+
+// Actions supported by CheckAction
+const (
+	ActionFollow     = "follow"
+	ActionFollowedBy = "followed_by"
+	ActionMutual     = "mutual_follow"
+	ActionLike       = "like"
+	ActionRetweet    = "retweet"
+)
+
+var (
+	// ErrNotSupported is returned when a provider cannot answer a lookup
+	ErrNotSupported = errors.New("not supported by x provider")
+	// ErrUserNotFound is returned when the X account does not exist
+	ErrUserNotFound = errors.New("x user not found")
+	// ErrTweetNotFound is returned when the tweet does not exist or is not visible
+	ErrTweetNotFound = errors.New("tweet not found")
+	// ErrInvalidTweetID is returned when the target is not a numeric tweet ID or tweet URL
+	ErrInvalidTweetID = errors.New("invalid tweet id")
+	// ErrRateLimited is returned when the provider answered 429
+	ErrRateLimited = errors.New("x provider rate limited")
+	// ErrScanLimit is returned when a user list is too long to scan to the end without finding the user
+	ErrScanLimit = errors.New("x list too long to scan")
+
+	tweetIDPattern  = regexp.MustCompile(`^[0-9]{1,20}$`)
+	tweetURLPattern = regexp.MustCompile(`^https?://(?:www\.|mobile\.)?(?:x|twitter)\.com/[^/]+/status(?:es)?/([0-9]{1,20})`)
+)
+
+// Provider is an X data backend used by the follow and engagement checks
+type Provider interface {
+	// Name identifies the provider in results (see ActionResult.Source)
+	Name() string
+	// Follows reports whether user follows target (both usernames)
+	Follows(ctx context.Context, user string, target string) (bool, error)
+	// HasLiked reports whether user liked tweetID
+	HasLiked(ctx context.Context, user string, tweetID string) (bool, error)
+	// HasRetweeted reports whether user retweeted tweetID
+	HasRetweeted(ctx context.Context, user string, tweetID string) (bool, error)
+	// ProfileTexts returns the bio and recent tweet texts of user
+	ProfileTexts(ctx context.Context, user string) ([]string, error)
+	// LookupUserID returns the numeric user ID of username
+	LookupUserID(ctx context.Context, username string) (string, error)
+	// LookupUsername returns the current username of a numeric user ID
+	LookupUsername(ctx context.Context, id string) (string, error)
+}
+
+// ActionResult is the outcome of CheckAction
+type ActionResult struct {
+	Done bool
+	// Source is the name of the provider that answered
+	Source string
+	// Verifiable is false when every provider able to answer gave up on a list too long to scan
+	Verifiable bool
+	// Reason explains why the check was not verifiable
+	Reason string
+}
+
+// NewProviders creates the providers listed in X_PROVIDER, in fallback order.
+// X_PROVIDER is a comma separated list of "apify" (default) and "api", e.g. "api,apify".
+func NewProviders() ([]Provider, error) {
+	var providers []Provider
+	for _, name := range providerNames() {
+		switch name {
+		case ProviderApify:
+			providers = append(providers, apifyProvider{})
+		case ProviderAPI:
+			client, err := NewAPIClient()
+			if err != nil {
+				return nil, fmt.Errorf("failed to create X API client: %w", err)
+			}
+			providers = append(providers, client)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown X_PROVIDER %q", name)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, errors.New("X_PROVIDER lists no provider")
+	}
+	return providers, nil
+}
+
+// providerNames returns the provider names listed in X_PROVIDER, "apify" when unset
+func providerNames() []string {
+	spec := strings.ToLower(strings.TrimSpace(os.Getenv("X_PROVIDER")))
+	if spec == "" {
+		spec = ProviderApify
+	}
+	var names []string
+	for _, name := range strings.Split(spec, ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
+
+// ResolvesUserIDs reports whether X_PROVIDER lists a provider that resolves user IDs ("api"); binding an
+// X account (see CanonicalAccount) fails without one
+func ResolvesUserIDs() bool {
+	for _, name := range providerNames() {
+		if name == ProviderAPI {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAction checks a follow/followed_by/mutual_follow against TWITTER_TARGET_USERNAME, or a like/retweet
+// of the tweet target. userID may be any form accepted by ParseIdentifier. Providers from X_PROVIDER are tried in order until one answers.
+func CheckAction(userID string, action string, target string) (*ActionResult, error) {
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	user, err := ResolveUsername(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var check func(ctx context.Context, p Provider) (bool, error)
+	switch action {
+	case ActionFollow, ActionFollowedBy, ActionMutual:
+		target, err := targetUsername()
+		if err != nil {
+			return nil, err
+		}
+		targetUser, err := ResolveUsername(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("TWITTER_TARGET_USERNAME: %w", err)
+		}
+		check = func(ctx context.Context, p Provider) (bool, error) {
+			switch action {
+			case ActionFollow:
+				return p.Follows(ctx, user, targetUser)
+			case ActionFollowedBy:
+				return p.Follows(ctx, targetUser, user)
+			}
+			follows, err := p.Follows(ctx, user, targetUser)
+			if err != nil || !follows {
+				return false, err
+			}
+			return p.Follows(ctx, targetUser, user)
+		}
+	case ActionLike, ActionRetweet:
+		tweetID, err := ParseTweetID(target)
+		if err != nil {
+			return nil, err
+		}
+		check = func(ctx context.Context, p Provider) (bool, error) {
+			if action == ActionLike {
+				return p.HasLiked(ctx, user, tweetID)
+			}
+			return p.HasRetweeted(ctx, user, tweetID)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported x action %q", action)
+	}
+
+	providers, err := NewProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	var limited *ActionResult
+	for _, p := range providers {
+		done, err := check(ctx, p)
+		if err == nil {
+			return &ActionResult{Done: done, Source: p.Name(), Verifiable: true}, nil
+		}
+		// Missing users/tweets are definitive, every other error falls back to the next provider
+		if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrTweetNotFound) {
+			return nil, err
+		}
+		// A capped scan is reported as unverifiable unless a later provider answers
+		if errors.Is(err, ErrScanLimit) {
+			log.Printf("[X][WARN] provider=%s action=%s user=%s: %v", p.Name(), action, user, err)
+			limited = &ActionResult{Source: p.Name(), Reason: err.Error()}
+			continue
+		}
+		if !errors.Is(err, ErrNotSupported) {
+			log.Printf("[X][ERROR] provider=%s action=%s user=%s: %v", p.Name(), action, user, err)
+		}
+		lastErr = err
+	}
+	if limited != nil {
+		return limited, nil
+	}
+	return nil, lastErr
+}
+
+// FetchProfileTexts returns the bio and recent tweet texts of username (any form accepted by ParseIdentifier), trying the X_PROVIDER providers in order
+func FetchProfileTexts(username string) ([]string, error) {
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	user, err := ResolveUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := NewProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, p := range providers {
+		texts, err := p.ProfileTexts(ctx, user)
+		if err == nil {
+			return texts, nil
+		}
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, err
+		}
+		log.Printf("[X][ERROR] provider=%s profile texts user=%s: %v", p.Name(), user, err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// ParseTweetID accepts a numeric tweet ID or an x.com / twitter.com status URL
+func ParseTweetID(target string) (string, error) {
+	target = strings.TrimSpace(target)
+	if tweetIDPattern.MatchString(target) {
+		return target, nil
+	}
+	if m := tweetURLPattern.FindStringSubmatch(target); m != nil {
+		return m[1], nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidTweetID, target)
+}
+
+// apifyProvider answers follow checks and profile texts with the Apify actors
+type apifyProvider struct{}
+
+// Name implements Provider
+func (apifyProvider) Name() string {
+	return ProviderApify
+}
+
+// Follows implements Provider with the follow-check actor
+func (apifyProvider) Follows(ctx context.Context, user string, target string) (bool, error) {
+	return checkUserBFollowsUserA(target, user)
+}
+
+// HasLiked implements Provider; the Apify actor has no like lookup
+func (apifyProvider) HasLiked(ctx context.Context, user string, tweetID string) (bool, error) {
+	return false, fmt.Errorf("%w: like lookup needs the api provider", ErrNotSupported)
+}
+
+// HasRetweeted implements Provider; the Apify actor has no retweet lookup
+func (apifyProvider) HasRetweeted(ctx context.Context, user string, tweetID string) (bool, error) {
+	return false, fmt.Errorf("%w: retweet lookup needs the api provider", ErrNotSupported)
+}
+
+// ProfileTexts implements Provider with the tweet scraper actor
+func (apifyProvider) ProfileTexts(ctx context.Context, user string) ([]string, error) {
+	return fetchProfileTextsApify(user)
+}
+
+// LookupUserID implements Provider; the Apify actors cannot resolve IDs
+func (apifyProvider) LookupUserID(ctx context.Context, username string) (string, error) {
+	return "", fmt.Errorf("%w: user id lookup needs the api provider", ErrNotSupported)
+}
+
+// LookupUsername implements Provider; the Apify actors cannot resolve IDs
+func (apifyProvider) LookupUsername(ctx context.Context, id string) (string, error) {
+	return "", fmt.Errorf("%w: username lookup needs the api provider", ErrNotSupported)
+}
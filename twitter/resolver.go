@@ -0,0 +1,236 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultResolveCacheTTL = 24 * time.Hour
+
+// maxResolveCacheEntries bounds the resolve cache; expired entries are swept first when it is full
+var maxResolveCacheEntries = 10000
+
+// Identifier kinds returned by ParseIdentifier
+const (
+	KindUsername = "username"
+	KindID       = "id"
+)
+
+var (
+	// ErrInvalidHandle is returned when an identifier is neither a username, a numeric user ID nor a profile URL
+	ErrInvalidHandle = errors.New("invalid x handle")
+
+	usernamePattern = regexp.MustCompile(`^[a-z0-9_]{1,15}$`)
+	userIDPattern   = regexp.MustCompile(`^[0-9]{1,20}$`)
+
+	// profileHosts are the hosts whose URLs look like https://host/<username>[/status/<id>]
+	profileHosts = map[string]bool{
+		"x.com":              true,
+		"www.x.com":          true,
+		"mobile.x.com":       true,
+		"twitter.com":        true,
+		"www.twitter.com":    true,
+		"mobile.twitter.com": true,
+	}
+
+	// reservedPaths are first path segments of x.com that are not usernames
+	reservedPaths = map[string]bool{
+		"home": true, "search": true, "explore": true, "intent": true, "settings": true, "messages": true, "notifications": true,
+	}
+)
+
+// ParseIdentifier normalizes a user supplied X identifier without any network call.
+// Accepted inputs:
+//   - a username, with or without the leading @ ("@Alice", "alice"), lowercased
+//   - a numeric user ID ("44196397"); prefix it with @ to force an all digit username
+//   - a profile or status URL ("https://x.com/alice", "twitter.com/alice/status/1", "https://x.com/i/user/44196397")
+func ParseIdentifier(identifier string) (kind string, value string, err error) {
+	s := strings.TrimSpace(identifier)
+	if s == "" {
+		return "", "", ErrInvalidHandle
+	}
+
+	if userIDPattern.MatchString(s) {
+		return KindID, s, nil
+	}
+
+	if strings.Contains(s, "/") {
+		kind, value, ok := parseProfileURL(s)
+		if !ok {
+			return "", "", fmt.Errorf("%w: unsupported profile URL %q", ErrInvalidHandle, identifier)
+		}
+		if kind == KindID {
+			return KindID, value, nil
+		}
+		s = value
+	}
+
+	s = strings.ToLower(strings.TrimPrefix(s, "@"))
+	if !usernamePattern.MatchString(s) {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidHandle, identifier)
+	}
+	return KindUsername, s, nil
+}
+
+// parseProfileURL extracts the username (or the user ID of /i/user/<id>) from an x.com / twitter.com URL
+func parseProfileURL(raw string) (kind string, value string, ok bool) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !profileHosts[strings.ToLower(u.Host)] {
+		return "", "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) == 0 || segments[0] == "" {
+		return "", "", false
+	}
+	if segments[0] == "i" {
+		if len(segments) == 3 && segments[1] == "user" && userIDPattern.MatchString(segments[2]) {
+			return KindID, segments[2], true
+		}
+		return "", "", false
+	}
+	if reservedPaths[strings.ToLower(segments[0])] {
+		return "", "", false
+	}
+	return KindUsername, segments[0], true
+}
+
+// resolveCacheEntry is a cached username <-> ID mapping
+type resolveCacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// resolveCache keeps resolved mappings in memory so repeated checks don't hit the X API
+var resolveCache = struct {
+	sync.RWMutex
+	entries map[string]resolveCacheEntry
+}{entries: map[string]resolveCacheEntry{}}
+
+// ResolveUserID turns an identifier into the stable numeric user ID, which survives handle renames.
+// Usernames are resolved through the X_PROVIDER providers and cached (X_RESOLVE_CACHE_TTL, default 24h).
+// ErrNotSupported is returned when no configured provider can resolve IDs (e.g. apify only).
+func ResolveUserID(ctx context.Context, identifier string) (string, error) {
+	kind, value, err := ParseIdentifier(identifier)
+	if err != nil {
+		return "", err
+	}
+	if kind == KindID {
+		return value, nil
+	}
+	return resolveCached(ctx, "username:"+value, func(p Provider) (string, error) {
+		return p.LookupUserID(ctx, value)
+	})
+}
+
+// ResolveUsername turns an identifier into the current lowercase username; numeric IDs are looked up
+// so an ID bound before a rename still reaches the renamed account.
+func ResolveUsername(ctx context.Context, identifier string) (string, error) {
+	kind, value, err := ParseIdentifier(identifier)
+	if err != nil {
+		return "", err
+	}
+	if kind == KindUsername {
+		return value, nil
+	}
+	username, err := resolveCached(ctx, "id:"+value, func(p Provider) (string, error) {
+		return p.LookupUsername(ctx, value)
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(username), nil
+}
+
+// CanonicalAccount returns the form an X account is stored in: the numeric user ID, which survives handle
+// renames. Binding fails with ErrNotSupported when no configured provider resolves IDs (e.g. apify only)
+// rather than storing a username that the next owner of the handle would inherit.
+func CanonicalAccount(ctx context.Context, identifier string) (string, error) {
+	id, err := ResolveUserID(ctx, identifier)
+	if errors.Is(err, ErrNotSupported) {
+		return "", fmt.Errorf("%w: binding an x account needs a provider that resolves user ids, add \"api\" to X_PROVIDER", err)
+	}
+	return id, err
+}
+
+// resolveCached answers from the cache or asks the providers in order
+func resolveCached(ctx context.Context, key string, lookup func(Provider) (string, error)) (string, error) {
+	if value, ok := cachedValue(key); ok {
+		return value, nil
+	}
+
+	providers, err := NewProviders()
+	if err != nil {
+		return "", err
+	}
+	lastErr := error(ErrNotSupported)
+	for _, p := range providers {
+		value, err := lookup(p)
+		if err == nil {
+			log.Printf("[X][DEBUG] resolve %s=%s via %s", key, value, p.Name())
+			storeValue(key, value)
+			return value, nil
+		}
+		if errors.Is(err, ErrUserNotFound) {
+			return "", err
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+func cachedValue(key string) (string, bool) {
+	resolveCache.Lock()
+	defer resolveCache.Unlock()
+	entry, ok := resolveCache.entries[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(resolveCache.entries, key)
+		return "", false
+	}
+	return entry.value, true
+}
+
+func storeValue(key string, value string) {
+	ttl := defaultResolveCacheTTL
+	if v := os.Getenv("X_RESOLVE_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			ttl = d
+		}
+	}
+	resolveCache.Lock()
+	defer resolveCache.Unlock()
+	if _, ok := resolveCache.entries[key]; !ok && len(resolveCache.entries) >= maxResolveCacheEntries {
+		evictResolveCache()
+	}
+	resolveCache.entries[key] = resolveCacheEntry{value: value, expiresAt: time.Now().Add(ttl)}
+}
+
+// evictResolveCache makes room for one entry: expired entries go first, then arbitrary live ones.
+// The caller holds the write lock.
+func evictResolveCache() {
+	now := time.Now()
+	for key, entry := range resolveCache.entries {
+		if now.After(entry.expiresAt) {
+			delete(resolveCache.entries, key)
+		}
+	}
+	for key := range resolveCache.entries {
+		if len(resolveCache.entries) < maxResolveCacheEntries {
+			return
+		}
+		delete(resolveCache.entries, key)
+	}
+}
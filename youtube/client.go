@@ -0,0 +1,383 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultAPIBaseURL is the YouTube Data API v3 base URL
+const defaultAPIBaseURL = "https://www.googleapis.com/youtube/v3"
+
+// maxCommentPages bounds how many commentThreads pages (100 threads each) are scanned per check
+const maxCommentPages = 20
+
+// Actions supported by the service dispatcher
+const (
+	ActionSubscribe = "subscribe"
+	ActionComment   = "comment"
+)
+
+var (
+	// ErrInvalidChannel is returned when a channel is neither a UC... ID, a channel URL nor an @handle
+	ErrInvalidChannel = errors.New("invalid youtube channel")
+	// ErrChannelNotFound is returned when an @handle does not resolve to a channel
+	ErrChannelNotFound = errors.New("youtube channel not found")
+	// ErrInvalidVideoID is returned when a video is neither an 11 character ID nor a video URL
+	ErrInvalidVideoID = errors.New("invalid youtube video id")
+	// ErrVideoNotFound is returned when the video does not exist or is private
+	ErrVideoNotFound = errors.New("youtube video not found")
+	// ErrUnauthorized is returned when the OAuth access token is missing, expired or revoked
+	ErrUnauthorized = errors.New("youtube authorization required")
+	// ErrQuotaExceeded is returned when the API key or project ran out of quota
+	ErrQuotaExceeded = errors.New("youtube quota exceeded")
+	// errScanLimit is returned by HasCommented when the video has more than maxCommentPages pages of comments
+	errScanLimit = errors.New("too many comments to scan")
+
+	channelIDPattern = regexp.MustCompile(`^UC[0-9A-Za-z_-]{22}$`)
+	videoIDPattern   = regexp.MustCompile(`^[0-9A-Za-z_-]{11}$`)
+	handlePattern    = regexp.MustCompile(`^@[0-9A-Za-z._-]{3,30}$`)
+)
+
+// Client calls the YouTube Data API
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a YouTube Data API client.
+// Config via ENV:
+//   - YOUTUBE_API_KEY (optional): key for public reads (comments, handle lookup) when no user token is available
+//   - YOUTUBE_API_BASE_URL (optional): override the API base URL (defaults to https://www.googleapis.com/youtube/v3)
+func NewClient() *Client {
+	baseURL := strings.TrimRight(os.Getenv("YOUTUBE_API_BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = defaultAPIBaseURL
+	}
+	return &Client{
+		baseURL:    baseURL,
+		apiKey:     os.Getenv("YOUTUBE_API_KEY"),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// apiError is the Google API error body
+type apiError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Reason string `json:"reason"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+// reason returns the first error reason
+func (e *apiError) reason() string {
+	if len(e.Error.Errors) == 0 {
+		return ""
+	}
+	return e.Error.Errors[0].Reason
+}
+
+// get performs a GET on path and decodes the JSON response into out.
+// The request is authorized with accessToken when set, with the API key otherwise.
+func (c *Client) get(ctx context.Context, path string, query url.Values, accessToken string, out any) error {
+	if accessToken == "" {
+		if c.apiKey == "" {
+			return errors.New("YOUTUBE_API_KEY environment variable not set")
+		}
+		query.Set("key", c.apiKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The URL may contain the API key, so only the path is reported
+		return fmt.Errorf("youtube %s request failed", path)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		_ = json.Unmarshal(body, &apiErr)
+		reason := apiErr.reason()
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized:
+			return fmt.Errorf("%w: %s", ErrUnauthorized, apiErr.Error.Message)
+		case reason == "quotaExceeded", reason == "rateLimitExceeded", reason == "dailyLimitExceeded",
+			reason == "userRateLimitExceeded", resp.StatusCode == http.StatusTooManyRequests:
+			return ratelimit.New(ErrQuotaExceeded, ratelimit.FromSeconds(resp.Header.Get("Retry-After")), apiErr.Error.Message)
+		case reason == "videoNotFound":
+			return fmt.Errorf("%w: %s", ErrVideoNotFound, apiErr.Error.Message)
+		case reason == "insufficientPermissions":
+			return fmt.Errorf("%w: token lacks the youtube.readonly scope", ErrUnauthorized)
+		}
+		return fmt.Errorf("youtube %s failed with status %d: %s", path, resp.StatusCode, apiErr.Error.Message)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// idList is a list response whose items only carry an ID
+type idList struct {
+	Items []struct {
+		ID string `json:"id"`
+	} `json:"items"`
+}
+
+// MyChannelID returns the channel owned by the OAuth token
+func (c *Client) MyChannelID(ctx context.Context, accessToken string) (string, error) {
+	q := url.Values{}
+	q.Set("part", "id")
+	q.Set("mine", "true")
+	var out idList
+	if err := c.get(ctx, "/channels", q, accessToken, &out); err != nil {
+		return "", err
+	}
+	if len(out.Items) == 0 {
+		return "", fmt.Errorf("%w: the google account has no youtube channel", ErrChannelNotFound)
+	}
+	return out.Items[0].ID, nil
+}
+
+// LookupHandle resolves an @handle to its channel ID
+func (c *Client) LookupHandle(ctx context.Context, handle string) (string, error) {
+	q := url.Values{}
+	q.Set("part", "id")
+	q.Set("forHandle", handle)
+	var out idList
+	if err := c.get(ctx, "/channels", q, "", &out); err != nil {
+		return "", err
+	}
+	if len(out.Items) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrChannelNotFound, handle)
+	}
+	return out.Items[0].ID, nil
+}
+
+// IsSubscribed reports whether the owner of the OAuth token is subscribed to channelID
+func (c *Client) IsSubscribed(ctx context.Context, accessToken string, channelID string) (bool, error) {
+	if accessToken == "" {
+		return false, fmt.Errorf("%w: subscriptions can only be read with the subscriber's token", ErrUnauthorized)
+	}
+	q := url.Values{}
+	q.Set("part", "id")
+	q.Set("mine", "true")
+	q.Set("forChannelId", channelID)
+	var out idList
+	if err := c.get(ctx, "/subscriptions", q, accessToken, &out); err != nil {
+		return false, err
+	}
+	return len(out.Items) > 0, nil
+}
+
+// commentSnippet is the part of a comment snippet used to match the author
+type commentSnippet struct {
+	AuthorChannelID struct {
+		Value string `json:"value"`
+	} `json:"authorChannelId"`
+}
+
+// commentThreadList is a page of commentThreads.list
+type commentThreadList struct {
+	NextPageToken string `json:"nextPageToken"`
+	Items         []struct {
+		Snippet struct {
+			TopLevelComment struct {
+				Snippet commentSnippet `json:"snippet"`
+			} `json:"topLevelComment"`
+		} `json:"snippet"`
+		Replies struct {
+			Comments []struct {
+				Snippet commentSnippet `json:"snippet"`
+			} `json:"comments"`
+		} `json:"replies"`
+	} `json:"items"`
+}
+
+// HasCommented scans the comment threads of videoID (newest first) for a comment or reply by channelID.
+// accessToken is optional; without it the API key is used. It returns errScanLimit when there are more than
+// maxCommentPages pages and none was by channelID.
+func (c *Client) HasCommented(ctx context.Context, accessToken string, videoID string, channelID string) (bool, error) {
+	q := url.Values{}
+	q.Set("part", "snippet,replies")
+	q.Set("videoId", videoID)
+	q.Set("maxResults", "100")
+	q.Set("order", "time")
+	q.Set("textFormat", "plainText")
+
+	for page := 0; page < maxCommentPages; page++ {
+		var out commentThreadList
+		if err := c.get(ctx, "/commentThreads", q, accessToken, &out); err != nil {
+			return false, err
+		}
+		for _, thread := range out.Items {
+			if thread.Snippet.TopLevelComment.Snippet.AuthorChannelID.Value == channelID {
+				return true, nil
+			}
+			for _, reply := range thread.Replies.Comments {
+				if reply.Snippet.AuthorChannelID.Value == channelID {
+					return true, nil
+				}
+			}
+		}
+		if out.NextPageToken == "" {
+			return false, nil
+		}
+		q.Set("pageToken", out.NextPageToken)
+	}
+	return false, fmt.Errorf("%w: video %s has more than %d pages of comments", errScanLimit, videoID, maxCommentPages)
+}
+
+// ResolveChannelID turns a UC... channel ID, a youtube.com/channel/ or /@handle URL, or an @handle into a channel ID.
+// Handles are looked up through the API key.
+func ResolveChannelID(ctx context.Context, channel string) (string, error) {
+	_ = godotenv.Load()
+
+	s := strings.TrimSpace(channel)
+	if u, ok := parseYouTubeURL(s); ok {
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		switch {
+		case len(segments) >= 2 && segments[0] == "channel":
+			s = segments[1]
+		case len(segments) >= 1 && strings.HasPrefix(segments[0], "@"):
+			s = segments[0]
+		default:
+			return "", fmt.Errorf("%w: %q", ErrInvalidChannel, channel)
+		}
+	}
+	if channelIDPattern.MatchString(s) {
+		return s, nil
+	}
+	if !handlePattern.MatchString(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidChannel, channel)
+	}
+	id, err := NewClient().LookupHandle(ctx, s)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("[YouTube][DEBUG] ResolveChannelID handle=%s channel=%s", s, id)
+	return id, nil
+}
+
+// ParseVideoID accepts an 11 character video ID or a watch, youtu.be, shorts or embed URL
+func ParseVideoID(video string) (string, error) {
+	s := strings.TrimSpace(video)
+	if u, ok := parseYouTubeURL(s); ok {
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		switch {
+		case strings.HasSuffix(strings.ToLower(u.Host), "youtu.be") && len(segments) >= 1:
+			s = segments[0]
+		case len(segments) >= 2 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live"):
+			s = segments[1]
+		default:
+			s = u.Query().Get("v")
+		}
+	}
+	if !videoIDPattern.MatchString(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidVideoID, video)
+	}
+	return s, nil
+}
+
+// parseYouTubeURL parses s when it looks like a youtube.com or youtu.be URL
+func parseYouTubeURL(s string) (*url.URL, bool) {
+	lower := strings.ToLower(s)
+	if !strings.Contains(lower, "youtube.com/") && !strings.Contains(lower, "youtu.be/") {
+		return nil, false
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, false
+	}
+	return u, true
+}
+
+// ActionResult is the outcome of CheckSubscription and CheckComment
+type ActionResult struct {
+	ChannelID string
+	Target    string
+	Done      bool
+	// Verifiable is false when the video had too many comments to scan, so Done could not be checked
+	Verifiable bool
+	// Reason explains why the check was not verifiable
+	Reason string
+}
+
+// CheckSubscription checks that the owner of accessToken (channelID) is subscribed to target.
+// target is a channel ID, channel URL or @handle; empty uses YOUTUBE_CHANNEL_ID.
+func CheckSubscription(accessToken string, channelID string, target string) (*ActionResult, error) {
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if strings.TrimSpace(target) == "" {
+		target = os.Getenv("YOUTUBE_CHANNEL_ID")
+	}
+	targetID, err := ResolveChannelID(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := NewClient().IsSubscribed(ctx, accessToken, targetID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[YouTube][DEBUG] CheckSubscription channel=%s target=%s subscribed=%v", channelID, targetID, done)
+	return &ActionResult{ChannelID: channelID, Target: targetID, Done: done, Verifiable: true}, nil
+}
+
+// CheckComment checks that channelID commented on the target video; accessToken is optional
+func CheckComment(accessToken string, channelID string, target string) (*ActionResult, error) {
+	_ = godotenv.Load()
+
+	videoID, err := ParseVideoID(target)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	res := &ActionResult{ChannelID: channelID, Target: videoID, Verifiable: true}
+	res.Done, err = NewClient().HasCommented(ctx, accessToken, videoID, channelID)
+	if errors.Is(err, errScanLimit) {
+		res.Verifiable, res.Reason = false, err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+	log.Printf("[YouTube][DEBUG] CheckComment channel=%s video=%s commented=%v verifiable=%v", channelID, videoID, res.Done, res.Verifiable)
+	return res, nil
+}
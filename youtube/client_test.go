@@ -0,0 +1,147 @@
+package youtube
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"checkingsocial/pkg/ratelimit"
+)
+
+const (
+	aliceChannel  = "UCaliceaaaaaaaaaaaaaaaaa"
+	targetChannel = "UCtargetaaaaaaaaaaaaaaaa"
+	// shortVideo has two pages of comments, alice replied on the second one
+	shortVideo = "shortvideo1"
+	// endlessVideo always has a next page and no comment by alice
+	endlessVideo = "endlessvid1"
+	// limitedVideo answers with a quota error
+	limitedVideo = "limitedvid1"
+)
+
+// comment is a comment thread by author with replies by replyAuthors
+func comment(author string, replyAuthors ...string) map[string]any {
+	snippet := func(id string) map[string]any {
+		return map[string]any{"snippet": map[string]any{"authorChannelId": map[string]any{"value": id}}}
+	}
+	replies := []map[string]any{}
+	for _, id := range replyAuthors {
+		replies = append(replies, snippet(id))
+	}
+	return map[string]any{
+		"snippet": map[string]any{"topLevelComment": snippet(author)},
+		"replies": map[string]any{"comments": replies},
+	}
+}
+
+// fakeDataAPI serves the YouTube Data API endpoints used by Client. The OAuth token "alice-token" belongs to a
+// subscriber of targetChannel; public reads need the API key "test-key".
+func fakeDataAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	write := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer alice-token" || r.URL.Query().Get("mine") != "true" {
+			write(w, http.StatusUnauthorized, map[string]any{"error": map[string]any{"code": 401, "message": "Invalid Credentials"}})
+			return
+		}
+		items := []map[string]any{}
+		if r.URL.Query().Get("forChannelId") == targetChannel {
+			items = append(items, map[string]any{"id": "sub-1"})
+		}
+		write(w, http.StatusOK, map[string]any{"items": items})
+	})
+	mux.HandleFunc("GET /commentThreads", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("key") != "test-key" {
+			write(w, http.StatusForbidden, map[string]any{"error": map[string]any{"code": 403, "message": "API key not valid"}})
+			return
+		}
+		switch q.Get("videoId") {
+		case shortVideo:
+			if q.Get("pageToken") == "" {
+				write(w, http.StatusOK, map[string]any{"items": []any{comment("UCother")}, "nextPageToken": "p2"})
+				return
+			}
+			write(w, http.StatusOK, map[string]any{"items": []any{comment("UCother", aliceChannel)}})
+		case endlessVideo:
+			write(w, http.StatusOK, map[string]any{"items": []any{comment("UCother")}, "nextPageToken": "more"})
+		case limitedVideo:
+			w.Header().Set("Retry-After", "30")
+			write(w, http.StatusForbidden, map[string]any{"error": map[string]any{
+				"code": 403, "message": "quota exhausted", "errors": []any{map[string]any{"reason": "quotaExceeded"}},
+			}})
+		default:
+			write(w, http.StatusNotFound, map[string]any{"error": map[string]any{
+				"code": 404, "message": "video not found", "errors": []any{map[string]any{"reason": "videoNotFound"}},
+			}})
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Setenv("YOUTUBE_API_BASE_URL", srv.URL)
+	t.Setenv("YOUTUBE_API_KEY", "test-key")
+	return srv
+}
+
+func TestCheckSubscription(t *testing.T) {
+	fakeDataAPI(t)
+
+	res, err := CheckSubscription("alice-token", aliceChannel, targetChannel)
+	if err != nil || !res.Done || !res.Verifiable {
+		t.Fatalf("subscribed: %+v, %v", res, err)
+	}
+	res, err = CheckSubscription("alice-token", aliceChannel, "https://www.youtube.com/channel/UCotheraaaaaaaaaaaaaaaaa")
+	if err != nil || res.Done {
+		t.Fatalf("not subscribed: %+v, %v", res, err)
+	}
+	if _, err := CheckSubscription("revoked-token", aliceChannel, targetChannel); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("bad token: got %v, want ErrUnauthorized", err)
+	}
+	if _, err := CheckSubscription("", aliceChannel, targetChannel); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("without token: got %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestCheckCommentPaging(t *testing.T) {
+	fakeDataAPI(t)
+
+	res, err := CheckComment("", aliceChannel, "https://youtu.be/"+shortVideo)
+	if err != nil || !res.Done || !res.Verifiable {
+		t.Fatalf("reply on the second page: %+v, %v", res, err)
+	}
+	res, err = CheckComment("", targetChannel, shortVideo)
+	if err != nil || res.Done || !res.Verifiable {
+		t.Fatalf("no comment: %+v, %v, want a verified false", res, err)
+	}
+	res, err = CheckComment("", aliceChannel, endlessVideo)
+	if err != nil {
+		t.Fatalf("endless comments: %v", err)
+	}
+	if res.Done || res.Verifiable || res.Reason == "" {
+		t.Fatalf("endless comments: %+v, want unverifiable after the page cap", res)
+	}
+	if _, err := CheckComment("", aliceChannel, "missingvid1"); !errors.Is(err, ErrVideoNotFound) {
+		t.Fatalf("missing video: got %v, want ErrVideoNotFound", err)
+	}
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	fakeDataAPI(t)
+
+	_, err := CheckComment("", aliceChannel, limitedVideo)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("got %v, want ErrQuotaExceeded", err)
+	}
+	var rl *ratelimit.Error
+	if !errors.As(err, &rl) || rl.RetryAfter != 30*time.Second {
+		t.Fatalf("retry after = %v, want 30s from Retry-After", rl)
+	}
+}
@@ -0,0 +1,160 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	defaultAuthURL  = "https://accounts.google.com/o/oauth2/v2/auth"
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+	// readonlyScope is enough for subscriptions.list?mine=true and channels.list?mine=true
+	readonlyScope = "https://www.googleapis.com/auth/youtube.readonly"
+)
+
+// ErrTokenRevoked is returned when Google rejects a refresh token (revoked, expired or for another client)
+var ErrTokenRevoked = errors.New("youtube refresh token revoked")
+
+// OAuthConfig is the Google OAuth client used to obtain YouTube tokens
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+}
+
+// LoadOAuthConfig reads the OAuth client.
+// Config via ENV:
+//   - YOUTUBE_OAUTH_CLIENT_ID, YOUTUBE_OAUTH_CLIENT_SECRET: the Google OAuth client
+//   - YOUTUBE_OAUTH_REDIRECT_URL: the registered redirect URL (our /api/v1/auth/youtube/callback)
+//   - YOUTUBE_OAUTH_AUTH_URL, YOUTUBE_OAUTH_TOKEN_URL (optional): override the Google endpoints
+func LoadOAuthConfig() (*OAuthConfig, error) {
+	_ = godotenv.Load()
+
+	cfg := &OAuthConfig{
+		ClientID:     os.Getenv("YOUTUBE_OAUTH_CLIENT_ID"),
+		ClientSecret: os.Getenv("YOUTUBE_OAUTH_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("YOUTUBE_OAUTH_REDIRECT_URL"),
+		AuthURL:      os.Getenv("YOUTUBE_OAUTH_AUTH_URL"),
+		TokenURL:     os.Getenv("YOUTUBE_OAUTH_TOKEN_URL"),
+	}
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, errors.New("YOUTUBE_OAUTH_CLIENT_ID and YOUTUBE_OAUTH_CLIENT_SECRET environment variables not set")
+	}
+	if cfg.AuthURL == "" {
+		cfg.AuthURL = defaultAuthURL
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = defaultTokenURL
+	}
+	return cfg, nil
+}
+
+// Token is an OAuth token granted by a YouTube user
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+// AuthCodeURL returns the consent URL; offline access and forced consent make Google return a refresh token
+func (c *OAuthConfig) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("client_id", c.ClientID)
+	q.Set("redirect_uri", c.RedirectURL)
+	q.Set("response_type", "code")
+	q.Set("scope", readonlyScope)
+	q.Set("access_type", "offline")
+	q.Set("prompt", "consent")
+	q.Set("include_granted_scopes", "true")
+	q.Set("state", state)
+	return c.AuthURL + "?" + q.Encode()
+}
+
+// Exchange trades an authorization code for a token
+func (c *OAuthConfig) Exchange(ctx context.Context, code string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.RedirectURL)
+	return c.token(ctx, form)
+}
+
+// Refresh obtains a new access token; the returned token keeps refreshToken when Google does not rotate it
+func (c *OAuthConfig) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	tok, err := c.token(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = refreshToken
+	}
+	return tok, nil
+}
+
+// token posts form to the token endpoint
+func (c *OAuthConfig) token(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", c.ClientID)
+	form.Set("client_secret", c.ClientSecret)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := (&http.Client{Timeout: 15 * time.Second}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	var out struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("token endpoint: status %d: failed to unmarshal response: %w", resp.StatusCode, err)
+	}
+	if out.Error == "invalid_grant" {
+		return nil, fmt.Errorf("%w: %s", ErrTokenRevoked, out.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || out.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint failed with status %d: %s %s", resp.StatusCode, out.Error, out.ErrorDescription)
+	}
+	return &Token{
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(out.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// RefreshToken refreshes a stored token with the OAuth client from the environment
+func RefreshToken(ctx context.Context, refreshToken string) (*Token, error) {
+	cfg, err := LoadOAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Refresh(ctx, refreshToken)
+}